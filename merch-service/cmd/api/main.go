@@ -7,6 +7,7 @@ import (
 
 	"cyansnbrst/merch-service/config"
 	"cyansnbrst/merch-service/internal/server"
+	"cyansnbrst/merch-service/pkg/auth/jwt"
 	"cyansnbrst/merch-service/pkg/db/postgres"
 	"cyansnbrst/merch-service/pkg/db/redis"
 )
@@ -25,6 +26,10 @@ import (
 // @in							header
 // @name						Authorization
 // @desctiprion					JWT Bearer token
+
+// @securityDefinitions.apikey	APIKey
+// @in							header
+// @name						X-API-Key
 func main() {
 	log.Println("starting merch-service server")
 
@@ -43,6 +48,11 @@ func main() {
 		}
 	}()
 
+	keys, err := jwt.LoadKeys(cfg)
+	if err != nil {
+		logger.Fatal("failed to load jwt keys", zap.String("error", err.Error()))
+	}
+
 	psqlDB, err := postgres.OpenDB(cfg)
 	if err != nil {
 		logger.Fatal("failed to init storage", zap.String("error", err.Error()))
@@ -58,7 +68,7 @@ func main() {
 	}()
 	logger.Info("redis connected")
 
-	s := server.NewServer(cfg, logger, psqlDB, redisClient)
+	s := server.NewServer(cfg, logger, psqlDB, redisClient, keys)
 	if err = s.Run(); err != nil {
 		logger.Fatal("an error occurred", zap.String("error", err.Error()))
 	}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"net"
 	"path/filepath"
 	"time"
 
@@ -24,7 +25,32 @@ type App struct {
 	WriteTimeout    time.Duration `yaml:"write_timeout" env:"APP_WRITE_TIMEOUT" env-required:"true"`
 	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env:"APP_SHUTDOWN_TIMEOUT" env-required:"true"`
 	JWTTokenTTL     time.Duration `yaml:"jwt_token_ttl" env:"JWT_TOKEN_TTL" env-required:"true"`
-	JWTSecretKey    string        `env:"JWT_SECRET_KEY" env-required:"true"`
+	// HS256 signs with JWTSecretKey, RS256 and EdDSA with the PEM private key,
+	// public key files are previous keys still accepted during rotation
+	JWTAlgorithm      string        `yaml:"jwt_algorithm" env:"JWT_ALGORITHM" env-default:"HS256"`
+	JWTSecretKey      string        `env:"JWT_SECRET_KEY"`
+	JWTPrivateKeyFile string        `yaml:"jwt_private_key_file" env:"JWT_PRIVATE_KEY_FILE"`
+	JWTPublicKeyFiles []string      `yaml:"jwt_public_key_files" env:"JWT_PUBLIC_KEY_FILES" env-separator:","`
+	RefreshTokenTTL   time.Duration `yaml:"refresh_token_ttl" env:"REFRESH_TOKEN_TTL" env-required:"true"`
+	PasswordResetTTL  time.Duration `yaml:"password_reset_ttl" env:"APP_PASSWORD_RESET_TTL" env-required:"true"`
+	RefundWindow      time.Duration `yaml:"refund_window" env:"APP_REFUND_WINDOW" env-required:"true"`
+	InfoHistorySize   int64         `yaml:"info_history_size" env:"APP_INFO_HISTORY_SIZE" env-required:"true"`
+	// Users promoted to admins on startup, so the first admin can be created
+	AdminUserIDs []int64 `yaml:"admin_user_ids" env:"APP_ADMIN_USER_IDS" env-separator:","`
+	// Proxy networks in CIDR notation allowed to set X-Forwarded-For,
+	// without them client IP is taken from the connection
+	TrustedProxies []string `yaml:"trusted_proxies" env:"APP_TRUSTED_PROXIES" env-separator:","`
+	// Serve the combined login-or-register endpoint /api/auth
+	LegacyAuth bool `yaml:"legacy_auth" env:"APP_LEGACY_AUTH"`
+	// Login brute-force protection, 0 attempts means no limit
+	MaxLoginAttempts      int64         `yaml:"max_login_attempts" env:"APP_MAX_LOGIN_ATTEMPTS"`
+	MaxLoginAttemptsPerIP int64         `yaml:"max_login_attempts_per_ip" env:"APP_MAX_LOGIN_ATTEMPTS_PER_IP"`
+	LoginLockout          time.Duration `yaml:"login_lockout" env:"APP_LOGIN_LOCKOUT"`
+	MaxLoginLockout       time.Duration `yaml:"max_login_lockout" env:"APP_MAX_LOGIN_LOCKOUT"`
+	// Transfer limits, 0 means no limit
+	MaxTransferAmount int64 `yaml:"max_transfer_amount" env:"APP_MAX_TRANSFER_AMOUNT"`
+	DailySendLimit    int64 `yaml:"daily_send_limit" env:"APP_DAILY_SEND_LIMIT"`
+	DailyReceiveLimit int64 `yaml:"daily_receive_limit" env:"APP_DAILY_RECEIVE_LIMIT"`
 }
 
 // PostgreSQL config struct
@@ -42,12 +68,14 @@ type PostgreSQL struct {
 
 // Redis config struct
 type Redis struct {
-	Addr         string        `env:"REDIS_ADDR" env-required:"true"`
-	DB           int           `env:"REDIS_DB" env-required:"true"`
-	MinIdleConns int           `yaml:"min_idle_conns" env:"REDIS_MIN_IDLE_CONNS" env-required:"true"`
-	PoolSize     int           `yaml:"pool_size" env:"REDIS_POOL_SIZE" env-required:"true"`
-	PoolTimeout  time.Duration `yaml:"pool_timeout" env:"REDIS_POOL_TIMEOUT" env-required:"true"`
-	CacheTTL     time.Duration `yaml:"cache_ttl" env:"REDIS_CACHE_TTL" env-required:"true"`
+	Addr           string        `env:"REDIS_ADDR" env-required:"true"`
+	DB             int           `env:"REDIS_DB" env-required:"true"`
+	MinIdleConns   int           `yaml:"min_idle_conns" env:"REDIS_MIN_IDLE_CONNS" env-required:"true"`
+	PoolSize       int           `yaml:"pool_size" env:"REDIS_POOL_SIZE" env-required:"true"`
+	PoolTimeout    time.Duration `yaml:"pool_timeout" env:"REDIS_POOL_TIMEOUT" env-required:"true"`
+	CacheTTL       time.Duration `yaml:"cache_ttl" env:"REDIS_CACHE_TTL" env-required:"true"`
+	CartTTL        time.Duration `yaml:"cart_ttl" env:"REDIS_CART_TTL" env-required:"true"`
+	IdempotencyTTL time.Duration `yaml:"idempotency_ttl" env:"REDIS_IDEMPOTENCY_TTL" env-required:"true"`
 }
 
 // Load config file from given path and env variables
@@ -64,5 +92,11 @@ func LoadConfig(filename string) (*Config, error) {
 		return nil, fmt.Errorf("failed to read env variables: %w", err)
 	}
 
+	for _, cidr := range cfg.App.TrustedProxies {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy network: %w", err)
+		}
+	}
+
 	return &cfg, nil
 }
@@ -0,0 +1,10 @@
+package apikeys
+
+import "github.com/labstack/echo/v4"
+
+// API keys handlers interface
+type Handlers interface {
+	CreateAPIKey(c echo.Context) error
+	ListAPIKeys(c echo.Context) error
+	RevokeAPIKey(c echo.Context) error
+}
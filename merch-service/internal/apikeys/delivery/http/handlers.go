@@ -0,0 +1,112 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"cyansnbrst/merch-service/internal/apikeys"
+	"cyansnbrst/merch-service/internal/middleware"
+	m "cyansnbrst/merch-service/internal/models"
+	"cyansnbrst/merch-service/pkg/db"
+	hh "cyansnbrst/merch-service/pkg/http_helpers"
+)
+
+// API keys handlers struct
+type apiKeysHandlers struct {
+	apiKeysUC apikeys.UseCase
+	logger    *zap.Logger
+}
+
+// API keys handlers constructor
+func NewAPIKeysHandlers(apiKeysUC apikeys.UseCase, logger *zap.Logger) apikeys.Handlers {
+	return &apiKeysHandlers{
+		apiKeysUC: apiKeysUC,
+		logger:    logger,
+	}
+}
+
+// @Summary		Create API key
+// @Description	Create a service account API key. The key is returned only once and must be sent in the X-API-Key header.
+// @Tags		admin
+// @Accept		json
+// @Produce		json
+// @Param input body models.CreateAPIKeyRequest true "input"
+// @Success		201	{object}	models.CreateAPIKeyResponse	"created"
+// @Failure		400	{object}	httphelpers.ErrorResponse	"bad request"
+// @Failure		401	{object}	httphelpers.ErrorResponse	"authentication required"
+// @Failure		403	{object}	httphelpers.ErrorResponse	"not permitted"
+// @Failure		500	{object}	httphelpers.ErrorResponse	"internal server error"
+// @Security 	JWT
+// @Router		/admin/api-keys [post]
+func (h *apiKeysHandlers) CreateAPIKey(c echo.Context) error {
+	adminID, err := middleware.ContextGetUserID(c)
+	if err != nil {
+		return hh.ServerErrorResponse(c, h.logger, err)
+	}
+
+	var input m.CreateAPIKeyRequest
+	if err := c.Bind(&input); err != nil {
+		return hh.BadRequestResponse(c, err)
+	}
+
+	if err := c.Validate(input); err != nil {
+		return hh.BadRequestResponse(c, err)
+	}
+
+	key, err := h.apiKeysUC.CreateAPIKey(c.Request().Context(), adminID, input)
+	if err != nil {
+		return hh.ServerErrorResponse(c, h.logger, err)
+	}
+
+	return c.JSON(http.StatusCreated, key)
+}
+
+// @Summary		List API keys
+// @Description	Get all service account API keys including revoked ones.
+// @Tags		admin
+// @Produce		json
+// @Success		200	{array}		models.APIKey				"successful"
+// @Failure		401	{object}	httphelpers.ErrorResponse	"authentication required"
+// @Failure		403	{object}	httphelpers.ErrorResponse	"not permitted"
+// @Failure		500	{object}	httphelpers.ErrorResponse	"internal server error"
+// @Security 	JWT
+// @Router		/admin/api-keys [get]
+func (h *apiKeysHandlers) ListAPIKeys(c echo.Context) error {
+	keys, err := h.apiKeysUC.ListAPIKeys(c.Request().Context())
+	if err != nil {
+		return hh.ServerErrorResponse(c, h.logger, err)
+	}
+
+	return c.JSON(http.StatusOK, keys)
+}
+
+// @Summary		Revoke API key
+// @Description	Revoke a service account API key. It stops working immediately.
+// @Tags		admin
+// @Param		id	path	int	true	"api key id"
+// @Success		200	"successful"
+// @Failure		400	{object}	httphelpers.ErrorResponse	"bad request"
+// @Failure		401	{object}	httphelpers.ErrorResponse	"authentication required"
+// @Failure		403	{object}	httphelpers.ErrorResponse	"not permitted"
+// @Failure		404	{object}	httphelpers.ErrorResponse	"api key not found"
+// @Failure		500	{object}	httphelpers.ErrorResponse	"internal server error"
+// @Security 	JWT
+// @Router		/admin/api-keys/{id} [delete]
+func (h *apiKeysHandlers) RevokeAPIKey(c echo.Context) error {
+	id, err := hh.ReadIDParam(c)
+	if err != nil {
+		return hh.BadRequestResponse(c, err)
+	}
+
+	if err := h.apiKeysUC.RevokeAPIKey(c.Request().Context(), id); err != nil {
+		if errors.Is(err, db.ErrAPIKeyNotFound) {
+			return hh.NotFoundResponse(c, err)
+		}
+		return hh.ServerErrorResponse(c, h.logger, err)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
@@ -0,0 +1,14 @@
+package http
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"cyansnbrst/merch-service/internal/apikeys"
+)
+
+// Register API keys admin routes
+func RegisterAPIKeysAdminRoutes(g *echo.Group, h apikeys.Handlers) {
+	g.POST("/api-keys", h.CreateAPIKey)
+	g.GET("/api-keys", h.ListAPIKeys)
+	g.DELETE("/api-keys/:id", h.RevokeAPIKey)
+}
@@ -0,0 +1,15 @@
+package apikeys
+
+import (
+	"context"
+
+	m "cyansnbrst/merch-service/internal/models"
+)
+
+// API keys repository interface
+type Repository interface {
+	CreateAPIKey(ctx context.Context, name, keyHash string, scopes []string, createdBy int64) (*m.APIKey, error)
+	ListAPIKeys(ctx context.Context) ([]m.APIKey, error)
+	RevokeAPIKey(ctx context.Context, id int64) error
+	UseAPIKey(ctx context.Context, keyHash string) (*m.APIKey, error)
+}
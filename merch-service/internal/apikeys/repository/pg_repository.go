@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"cyansnbrst/merch-service/internal/apikeys"
+	m "cyansnbrst/merch-service/internal/models"
+	"cyansnbrst/merch-service/pkg/db"
+)
+
+const apiKeyColumns = `id, name, scopes, created_by, created_at, last_used_at, revoked_at`
+
+// API keys repository struct
+type apiKeysRepo struct {
+	db *pgxpool.Pool
+}
+
+// API keys repository constructor
+func NewAPIKeysRepo(db *pgxpool.Pool) apikeys.Repository {
+	return &apiKeysRepo{db: db}
+}
+
+// Create a new API key
+func (r *apiKeysRepo) CreateAPIKey(ctx context.Context, name, keyHash string, scopes []string, createdBy int64) (*m.APIKey, error) {
+	query := `
+		INSERT INTO api_keys (name, key_hash, scopes, created_by)
+		VALUES ($1, $2, $3, $4)
+		RETURNING ` + apiKeyColumns
+
+	key, err := scanAPIKey(r.db.QueryRow(ctx, query, name, keyHash, scopes, createdBy))
+	if err != nil {
+		return nil, fmt.Errorf("repo - failed to create api key: %w", err)
+	}
+
+	return key, nil
+}
+
+// Get all API keys, including revoked ones
+func (r *apiKeysRepo) ListAPIKeys(ctx context.Context) ([]m.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys ORDER BY id`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("repo - failed to get api keys: %w", err)
+	}
+	defer rows.Close()
+
+	keys := make([]m.APIKey, 0)
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("repo - failed to scan api key: %w", err)
+		}
+		keys = append(keys, *key)
+	}
+
+	return keys, nil
+}
+
+// Revoke an active API key
+func (r *apiKeysRepo) RevokeAPIKey(ctx context.Context, id int64) error {
+	query := `
+		UPDATE api_keys
+		SET revoked_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND revoked_at IS NULL
+	`
+
+	result, err := r.db.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("repo - failed to revoke api key: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return db.ErrAPIKeyNotFound
+	}
+
+	return nil
+}
+
+// Get an active API key by hash and mark it as used
+func (r *apiKeysRepo) UseAPIKey(ctx context.Context, keyHash string) (*m.APIKey, error) {
+	query := `
+		UPDATE api_keys
+		SET last_used_at = CURRENT_TIMESTAMP
+		WHERE key_hash = $1 AND revoked_at IS NULL
+		RETURNING ` + apiKeyColumns
+
+	key, err := scanAPIKey(r.db.QueryRow(ctx, query, keyHash))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, db.ErrAPIKeyNotFound
+		}
+		return nil, fmt.Errorf("repo - failed to get api key: %w", err)
+	}
+
+	return key, nil
+}
+
+// Scan API key columns
+func scanAPIKey(row pgx.Row) (*m.APIKey, error) {
+	var key m.APIKey
+	err := row.Scan(&key.ID, &key.Name, &key.Scopes, &key.CreatedBy, &key.CreatedAt, &key.LastUsedAt, &key.RevokedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
@@ -0,0 +1,15 @@
+package apikeys
+
+import (
+	"context"
+
+	m "cyansnbrst/merch-service/internal/models"
+)
+
+// API keys usecase interface
+type UseCase interface {
+	CreateAPIKey(ctx context.Context, adminID int64, input m.CreateAPIKeyRequest) (*m.CreateAPIKeyResponse, error)
+	ListAPIKeys(ctx context.Context) ([]m.APIKey, error)
+	RevokeAPIKey(ctx context.Context, id int64) error
+	ValidateAPIKey(ctx context.Context, key string) (*m.APIKey, error)
+}
@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"cyansnbrst/merch-service/internal/apikeys"
+	m "cyansnbrst/merch-service/internal/models"
+	"cyansnbrst/merch-service/pkg/auth"
+	"cyansnbrst/merch-service/pkg/auth/token"
+	"cyansnbrst/merch-service/pkg/db"
+)
+
+// Prefix that makes API keys recognizable, e.g. by secret scanners
+const keyPrefix = "msk_"
+
+// API keys usecase struct
+type apiKeysUC struct {
+	apiKeysRepo apikeys.Repository
+}
+
+// API keys usecase constructor
+func NewAPIKeysUseCase(apiKeysRepo apikeys.Repository) apikeys.UseCase {
+	return &apiKeysUC{apiKeysRepo: apiKeysRepo}
+}
+
+// Create a service account API key, only its hash is stored
+func (u *apiKeysUC) CreateAPIKey(ctx context.Context, adminID int64, input m.CreateAPIKeyRequest) (*m.CreateAPIKeyResponse, error) {
+	secret, err := token.Generate()
+	if err != nil {
+		return nil, err
+	}
+	key := keyPrefix + secret
+
+	apiKey, err := u.apiKeysRepo.CreateAPIKey(ctx, input.Name, token.Hash(key), input.Scopes, adminID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &m.CreateAPIKeyResponse{
+		APIKey: *apiKey,
+		Key:    key,
+	}, nil
+}
+
+// Get all API keys
+func (u *apiKeysUC) ListAPIKeys(ctx context.Context) ([]m.APIKey, error) {
+	return u.apiKeysRepo.ListAPIKeys(ctx)
+}
+
+// Revoke API key, it stops working immediately
+func (u *apiKeysUC) RevokeAPIKey(ctx context.Context, id int64) error {
+	return u.apiKeysRepo.RevokeAPIKey(ctx, id)
+}
+
+// Get active API key by its plaintext value
+func (u *apiKeysUC) ValidateAPIKey(ctx context.Context, key string) (*m.APIKey, error) {
+	if !strings.HasPrefix(key, keyPrefix) {
+		return nil, auth.ErrInvalidAPIKey
+	}
+
+	apiKey, err := u.apiKeysRepo.UseAPIKey(ctx, token.Hash(key))
+	if err != nil {
+		if errors.Is(err, db.ErrAPIKeyNotFound) {
+			return nil, auth.ErrInvalidAPIKey
+		}
+		return nil, err
+	}
+
+	return apiKey, nil
+}
@@ -0,0 +1,159 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	mock_apikeys "cyansnbrst/merch-service/internal/apikeys/mock"
+	"cyansnbrst/merch-service/internal/apikeys/usecase"
+	m "cyansnbrst/merch-service/internal/models"
+	"cyansnbrst/merch-service/pkg/auth"
+	"cyansnbrst/merch-service/pkg/auth/token"
+	"cyansnbrst/merch-service/pkg/db"
+)
+
+var ErrRandomDBError = errors.New("db error")
+
+func TestAPIKeysUC_CreateAPIKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_apikeys.NewMockRepository(ctrl)
+
+	apiKeysUC := usecase.NewAPIKeysUseCase(mockRepo)
+
+	input := m.CreateAPIKeyRequest{Name: "hr-bot", Scopes: []string{m.ScopeGrantCoins}}
+
+	t.Run("success", func(t *testing.T) {
+		var storedHash string
+		mockRepo.EXPECT().CreateAPIKey(gomock.Any(), "hr-bot", gomock.Any(), input.Scopes, int64(1)).
+			DoAndReturn(func(_ context.Context, name, keyHash string, scopes []string, createdBy int64) (*m.APIKey, error) {
+				storedHash = keyHash
+				return &m.APIKey{ID: 3, Name: name, Scopes: scopes}, nil
+			})
+
+		key, err := apiKeysUC.CreateAPIKey(context.Background(), 1, input)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), key.ID)
+		assert.True(t, strings.HasPrefix(key.Key, "msk_"))
+		assert.Equal(t, token.Hash(key.Key), storedHash)
+	})
+
+	t.Run("db error", func(t *testing.T) {
+		mockRepo.EXPECT().CreateAPIKey(gomock.Any(), "hr-bot", gomock.Any(), input.Scopes, int64(1)).Return(nil, ErrRandomDBError)
+
+		key, err := apiKeysUC.CreateAPIKey(context.Background(), 1, input)
+
+		assert.Nil(t, key)
+		assert.Equal(t, ErrRandomDBError, err)
+	})
+}
+
+func TestAPIKeysUC_RevokeAPIKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_apikeys.NewMockRepository(ctrl)
+
+	apiKeysUC := usecase.NewAPIKeysUseCase(mockRepo)
+
+	tests := []struct {
+		name          string
+		id            int64
+		mockSetup     func()
+		expectedError error
+	}{
+		{
+			name: "success",
+			id:   1,
+			mockSetup: func() {
+				mockRepo.EXPECT().RevokeAPIKey(gomock.Any(), int64(1)).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name: "not found",
+			id:   2,
+			mockSetup: func() {
+				mockRepo.EXPECT().RevokeAPIKey(gomock.Any(), int64(2)).Return(db.ErrAPIKeyNotFound)
+			},
+			expectedError: db.ErrAPIKeyNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			err := apiKeysUC.RevokeAPIKey(context.Background(), tt.id)
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
+
+func TestAPIKeysUC_ValidateAPIKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_apikeys.NewMockRepository(ctrl)
+
+	apiKeysUC := usecase.NewAPIKeysUseCase(mockRepo)
+
+	apiKey := &m.APIKey{ID: 1, Name: "hr-bot", Scopes: []string{m.ScopeGrantCoins}}
+
+	tests := []struct {
+		name          string
+		key           string
+		mockSetup     func()
+		expectedKey   *m.APIKey
+		expectedError error
+	}{
+		{
+			name: "success",
+			key:  "msk_valid",
+			mockSetup: func() {
+				mockRepo.EXPECT().UseAPIKey(gomock.Any(), token.Hash("msk_valid")).Return(apiKey, nil)
+			},
+			expectedKey:   apiKey,
+			expectedError: nil,
+		},
+		{
+			name:          "wrong prefix",
+			key:           "not-a-key",
+			mockSetup:     func() {},
+			expectedError: auth.ErrInvalidAPIKey,
+		},
+		{
+			name: "unknown or revoked",
+			key:  "msk_revoked",
+			mockSetup: func() {
+				mockRepo.EXPECT().UseAPIKey(gomock.Any(), token.Hash("msk_revoked")).Return(nil, db.ErrAPIKeyNotFound)
+			},
+			expectedError: auth.ErrInvalidAPIKey,
+		},
+		{
+			name: "db error",
+			key:  "msk_valid",
+			mockSetup: func() {
+				mockRepo.EXPECT().UseAPIKey(gomock.Any(), token.Hash("msk_valid")).Return(nil, ErrRandomDBError)
+			},
+			expectedError: ErrRandomDBError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			key, err := apiKeysUC.ValidateAPIKey(context.Background(), tt.key)
+			assert.Equal(t, tt.expectedKey, key)
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
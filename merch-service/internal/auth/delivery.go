@@ -5,4 +5,15 @@ import "github.com/labstack/echo/v4"
 // Auth handlers interface
 type Handlers interface {
 	Authenticate(c echo.Context) error
+	Register(c echo.Context) error
+	Login(c echo.Context) error
+	Refresh(c echo.Context) error
+	Logout(c echo.Context) error
+	ListSessions(c echo.Context) error
+	TerminateSession(c echo.Context) error
+	RevokeAllSessions(c echo.Context) error
+	ChangePassword(c echo.Context) error
+	CreateResetToken(c echo.Context) error
+	ResetPassword(c echo.Context) error
+	JWKS(c echo.Context) error
 }
@@ -4,12 +4,15 @@ import (
 	"errors"
 	"net/http"
 
+	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	"go.uber.org/zap"
 
 	"cyansnbrst/merch-service/internal/auth"
 	"cyansnbrst/merch-service/internal/auth/usecase"
+	"cyansnbrst/merch-service/internal/middleware"
 	m "cyansnbrst/merch-service/internal/models"
+	"cyansnbrst/merch-service/pkg/db"
 	hh "cyansnbrst/merch-service/pkg/http_helpers"
 )
 
@@ -36,7 +39,10 @@ func NewAuthHandlers(authUC auth.UseCase, logger *zap.Logger) auth.Handlers {
 // @Success		200	{object}	models.AuthResponse			"successful"
 // @Failure		400	{object}	httphelpers.ErrorResponse	"bad request"
 // @Failure		401	{object}	httphelpers.ErrorResponse	"invalid credentials"
+// @Failure		403	{object}	httphelpers.ErrorResponse	"account deactivated"
+// @Failure		429	{object}	httphelpers.ErrorResponse	"too many failed attempts"
 // @Failure		500	{object}	httphelpers.ErrorResponse	"internal server error"
+// @Deprecated
 // @Router		/auth [post]
 func (h *authHandlers) Authenticate(c echo.Context) error {
 	var input m.AuthRequest
@@ -48,13 +54,343 @@ func (h *authHandlers) Authenticate(c echo.Context) error {
 		return hh.BadRequestResponse(c, err)
 	}
 
-	token, err := h.authUC.LoginOrRegister(c.Request().Context(), input.Username, input.Password)
+	tokens, err := h.authUC.LoginOrRegister(c.Request().Context(), input.Username, input.Password, clientInfo(c))
 	if err != nil {
+		var lockedErr *usecase.LoginLockedError
+		if errors.As(err, &lockedErr) {
+			return hh.TooManyRequestsResponse(c, err, lockedErr.RetryAfter)
+		}
 		if errors.Is(err, usecase.ErrIncorrectPassword) {
 			return hh.InvalidCredentialsResponse(c)
 		}
+		if errors.Is(err, db.ErrUserDeactivated) {
+			return hh.ForbiddenResponse(c, err)
+		}
+		return hh.ServerErrorResponse(c, h.logger, err)
+	}
+
+	return c.JSON(http.StatusOK, tokens)
+}
+
+// @Summary		Register a user
+// @Description	Create a new user and return an access token.
+// @Tags		auth
+// @Accept		json
+// @Produce		json
+// @Param input body models.AuthRequest true "input"
+// @Success		201	{object}	models.AuthResponse			"created"
+// @Failure		400	{object}	httphelpers.ErrorResponse	"bad request"
+// @Failure		409	{object}	httphelpers.ErrorResponse	"username already taken"
+// @Failure		500	{object}	httphelpers.ErrorResponse	"internal server error"
+// @Router		/register [post]
+func (h *authHandlers) Register(c echo.Context) error {
+	var input m.AuthRequest
+	if err := c.Bind(&input); err != nil {
+		return hh.BadRequestResponse(c, err)
+	}
+
+	if err := c.Validate(input); err != nil {
+		return hh.BadRequestResponse(c, err)
+	}
+
+	tokens, err := h.authUC.Register(c.Request().Context(), input.Username, input.Password, clientInfo(c))
+	if err != nil {
+		if errors.Is(err, db.ErrUserAlreadyExists) {
+			return hh.ConflictResponse(c, err)
+		}
+		return hh.ServerErrorResponse(c, h.logger, err)
+	}
+
+	return c.JSON(http.StatusCreated, tokens)
+}
+
+// @Summary		Login a user
+// @Description	Return an access token for an existing user.
+// @Tags		auth
+// @Accept		json
+// @Produce		json
+// @Param input body models.AuthRequest true "input"
+// @Success		200	{object}	models.AuthResponse			"successful"
+// @Failure		400	{object}	httphelpers.ErrorResponse	"bad request"
+// @Failure		401	{object}	httphelpers.ErrorResponse	"invalid credentials"
+// @Failure		403	{object}	httphelpers.ErrorResponse	"account deactivated"
+// @Failure		429	{object}	httphelpers.ErrorResponse	"too many failed attempts"
+// @Failure		500	{object}	httphelpers.ErrorResponse	"internal server error"
+// @Router		/login [post]
+func (h *authHandlers) Login(c echo.Context) error {
+	var input m.AuthRequest
+	if err := c.Bind(&input); err != nil {
+		return hh.BadRequestResponse(c, err)
+	}
+
+	if err := c.Validate(input); err != nil {
+		return hh.BadRequestResponse(c, err)
+	}
+
+	tokens, err := h.authUC.Login(c.Request().Context(), input.Username, input.Password, clientInfo(c))
+	if err != nil {
+		var lockedErr *usecase.LoginLockedError
+		if errors.As(err, &lockedErr) {
+			return hh.TooManyRequestsResponse(c, err, lockedErr.RetryAfter)
+		}
+		if errors.Is(err, db.ErrUserNotFound) || errors.Is(err, usecase.ErrIncorrectPassword) {
+			return hh.InvalidCredentialsResponse(c)
+		}
+		if errors.Is(err, db.ErrUserDeactivated) {
+			return hh.ForbiddenResponse(c, err)
+		}
+		return hh.ServerErrorResponse(c, h.logger, err)
+	}
+
+	return c.JSON(http.StatusOK, tokens)
+}
+
+// @Summary		Refresh tokens
+// @Description	Exchange a refresh token for a new token pair. Each refresh token can be used once, reusing it revokes the whole session.
+// @Tags		auth
+// @Accept		json
+// @Produce		json
+// @Param input body models.RefreshRequest true "input"
+// @Success		200	{object}	models.AuthResponse			"successful"
+// @Failure		400	{object}	httphelpers.ErrorResponse	"bad request"
+// @Failure		401	{object}	httphelpers.ErrorResponse	"invalid refresh token"
+// @Failure		500	{object}	httphelpers.ErrorResponse	"internal server error"
+// @Router		/token/refresh [post]
+func (h *authHandlers) Refresh(c echo.Context) error {
+	var input m.RefreshRequest
+	if err := c.Bind(&input); err != nil {
+		return hh.BadRequestResponse(c, err)
+	}
+
+	if err := c.Validate(input); err != nil {
+		return hh.BadRequestResponse(c, err)
+	}
+
+	tokens, err := h.authUC.Refresh(c.Request().Context(), input.RefreshToken)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidRefreshToken) || errors.Is(err, usecase.ErrRefreshTokenReused) {
+			return hh.InvalidAuthenticationTokenResponse(c)
+		}
+		return hh.ServerErrorResponse(c, h.logger, err)
+	}
+
+	return c.JSON(http.StatusOK, tokens)
+}
+
+// @Summary		Logout
+// @Description	Revoke the current access token and end its session.
+// @Tags		auth
+// @Success		200	"successful"
+// @Failure		401	{object}	httphelpers.ErrorResponse	"authentication required"
+// @Failure		500	{object}	httphelpers.ErrorResponse	"internal server error"
+// @Security 	JWT
+// @Router		/logout [post]
+func (h *authHandlers) Logout(c echo.Context) error {
+	claims, err := middleware.ContextGetClaims(c)
+	if err != nil {
+		return hh.ServerErrorResponse(c, h.logger, err)
+	}
+
+	if err := h.authUC.Logout(c.Request().Context(), claims); err != nil {
+		return hh.ServerErrorResponse(c, h.logger, err)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+// @Summary		List sessions
+// @Description	Get active sessions of the current user, the session of the token used is marked as current.
+// @Tags		auth
+// @Produce		json
+// @Success		200	{array}		models.Session				"successful"
+// @Failure		401	{object}	httphelpers.ErrorResponse	"authentication required"
+// @Failure		500	{object}	httphelpers.ErrorResponse	"internal server error"
+// @Security 	JWT
+// @Router		/sessions [get]
+func (h *authHandlers) ListSessions(c echo.Context) error {
+	claims, err := middleware.ContextGetClaims(c)
+	if err != nil {
+		return hh.ServerErrorResponse(c, h.logger, err)
+	}
+
+	sessions, err := h.authUC.ListSessions(c.Request().Context(), claims.UserID, claims.SessionID)
+	if err != nil {
+		return hh.ServerErrorResponse(c, h.logger, err)
+	}
+
+	return c.JSON(http.StatusOK, sessions)
+}
+
+// @Summary		Terminate session
+// @Description	End one of the current user's sessions, revoking its access and refresh tokens.
+// @Tags		auth
+// @Param		id	path	string	true	"session id"
+// @Success		200	"successful"
+// @Failure		400	{object}	httphelpers.ErrorResponse	"bad request"
+// @Failure		401	{object}	httphelpers.ErrorResponse	"authentication required"
+// @Failure		404	{object}	httphelpers.ErrorResponse	"session not found"
+// @Failure		500	{object}	httphelpers.ErrorResponse	"internal server error"
+// @Security 	JWT
+// @Router		/sessions/{id} [delete]
+func (h *authHandlers) TerminateSession(c echo.Context) error {
+	userID, err := middleware.ContextGetUserID(c)
+	if err != nil {
+		return hh.ServerErrorResponse(c, h.logger, err)
+	}
+
+	sessionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return hh.BadRequestResponse(c, hh.ErrInvalidIDParam)
+	}
+
+	if err := h.authUC.TerminateSession(c.Request().Context(), userID, sessionID.String()); err != nil {
+		if errors.Is(err, db.ErrSessionNotFound) {
+			return hh.NotFoundResponse(c, err)
+		}
+		return hh.ServerErrorResponse(c, h.logger, err)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+// @Summary		Revoke user sessions
+// @Description	Invalidate all access and refresh tokens of the user.
+// @Tags		admin
+// @Param		id	path	int	true	"user id"
+// @Success		200	"successful"
+// @Failure		400	{object}	httphelpers.ErrorResponse	"bad request"
+// @Failure		401	{object}	httphelpers.ErrorResponse	"authentication required"
+// @Failure		403	{object}	httphelpers.ErrorResponse	"not permitted"
+// @Failure		404	{object}	httphelpers.ErrorResponse	"user not found"
+// @Failure		500	{object}	httphelpers.ErrorResponse	"internal server error"
+// @Security 	JWT
+// @Router		/admin/users/{id}/revoke-sessions [post]
+func (h *authHandlers) RevokeAllSessions(c echo.Context) error {
+	userID, err := hh.ReadIDParam(c)
+	if err != nil {
+		return hh.BadRequestResponse(c, err)
+	}
+
+	if err := h.authUC.RevokeAllSessions(c.Request().Context(), userID); err != nil {
+		if errors.Is(err, db.ErrUserNotFound) {
+			return hh.NotFoundResponse(c, err)
+		}
+		return hh.ServerErrorResponse(c, h.logger, err)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+// @Summary		Change password
+// @Description	Set a new password for the current user. All existing tokens are revoked.
+// @Tags		auth
+// @Accept		json
+// @Param input body models.ChangePasswordRequest true "input"
+// @Success		200	"successful"
+// @Failure		400	{object}	httphelpers.ErrorResponse	"bad request"
+// @Failure		401	{object}	httphelpers.ErrorResponse	"authentication required"
+// @Failure		500	{object}	httphelpers.ErrorResponse	"internal server error"
+// @Security 	JWT
+// @Router		/password [post]
+func (h *authHandlers) ChangePassword(c echo.Context) error {
+	userID, err := middleware.ContextGetUserID(c)
+	if err != nil {
+		return hh.ServerErrorResponse(c, h.logger, err)
+	}
+
+	var input m.ChangePasswordRequest
+	if err := c.Bind(&input); err != nil {
+		return hh.BadRequestResponse(c, err)
+	}
+
+	if err := c.Validate(input); err != nil {
+		return hh.BadRequestResponse(c, err)
+	}
+
+	if err := h.authUC.ChangePassword(c.Request().Context(), userID, input.OldPassword, input.NewPassword); err != nil {
+		if errors.Is(err, usecase.ErrIncorrectPassword) {
+			return hh.BadRequestResponse(c, err)
+		}
+		return hh.ServerErrorResponse(c, h.logger, err)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+// @Summary		Create password reset token
+// @Description	Issue a one-time token the user can redeem to set a new password.
+// @Tags		admin
+// @Produce		json
+// @Param		id	path	int	true	"user id"
+// @Success		201	{object}	models.PasswordResetResponse	"created"
+// @Failure		400	{object}	httphelpers.ErrorResponse		"bad request"
+// @Failure		401	{object}	httphelpers.ErrorResponse		"authentication required"
+// @Failure		403	{object}	httphelpers.ErrorResponse		"not permitted"
+// @Failure		404	{object}	httphelpers.ErrorResponse		"user not found"
+// @Failure		500	{object}	httphelpers.ErrorResponse		"internal server error"
+// @Security 	JWT
+// @Router		/admin/users/{id}/password-reset [post]
+func (h *authHandlers) CreateResetToken(c echo.Context) error {
+	userID, err := hh.ReadIDParam(c)
+	if err != nil {
+		return hh.BadRequestResponse(c, err)
+	}
+
+	reset, err := h.authUC.CreateResetToken(c.Request().Context(), userID)
+	if err != nil {
+		if errors.Is(err, db.ErrUserNotFound) {
+			return hh.NotFoundResponse(c, err)
+		}
+		return hh.ServerErrorResponse(c, h.logger, err)
+	}
+
+	return c.JSON(http.StatusCreated, reset)
+}
+
+// @Summary		Reset password
+// @Description	Set a new password using a one-time reset token. All existing tokens are revoked.
+// @Tags		auth
+// @Accept		json
+// @Param input body models.ResetPasswordRequest true "input"
+// @Success		200	"successful"
+// @Failure		400	{object}	httphelpers.ErrorResponse	"bad request"
+// @Failure		401	{object}	httphelpers.ErrorResponse	"invalid reset token"
+// @Failure		500	{object}	httphelpers.ErrorResponse	"internal server error"
+// @Router		/password/reset [post]
+func (h *authHandlers) ResetPassword(c echo.Context) error {
+	var input m.ResetPasswordRequest
+	if err := c.Bind(&input); err != nil {
+		return hh.BadRequestResponse(c, err)
+	}
+
+	if err := c.Validate(input); err != nil {
+		return hh.BadRequestResponse(c, err)
+	}
+
+	if err := h.authUC.ResetPassword(c.Request().Context(), input.ResetToken, input.NewPassword); err != nil {
+		if errors.Is(err, usecase.ErrInvalidResetToken) || errors.Is(err, db.ErrUserNotFound) {
+			return hh.InvalidAuthenticationTokenResponse(c)
+		}
 		return hh.ServerErrorResponse(c, h.logger, err)
 	}
 
-	return c.JSON(http.StatusOK, m.AuthResponse{Token: token})
+	return c.NoContent(http.StatusOK)
+}
+
+// @Summary		JSON Web Key Set
+// @Description	Get public keys for verifying access tokens in JWK Set format, including previous keys still accepted during rotation. Empty for HS256. Served outside the API base path at /.well-known/jwks.json.
+// @Tags		auth
+// @Produce		json
+// @Success		200	{object}	jwt.JWKS	"successful"
+// @Router		/.well-known/jwks.json [get]
+func (h *authHandlers) JWKS(c echo.Context) error {
+	return c.JSON(http.StatusOK, h.authUC.JWKS())
+}
+
+// Client the request came from
+func clientInfo(c echo.Context) m.ClientInfo {
+	return m.ClientInfo{
+		IP:        c.RealIP(),
+		UserAgent: c.Request().UserAgent(),
+	}
 }
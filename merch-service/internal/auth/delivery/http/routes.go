@@ -8,5 +8,32 @@ import (
 
 // Register auth routes
 func RegisterAuthRoutes(g *echo.Group, h auth.Handlers) {
+	g.POST("/register", h.Register)
+	g.POST("/login", h.Login)
+	g.POST("/token/refresh", h.Refresh)
+	g.POST("/password/reset", h.ResetPassword)
+}
+
+// Register combined login-or-register route
+func RegisterLegacyAuthRoutes(g *echo.Group, h auth.Handlers) {
 	g.POST("/auth", h.Authenticate)
 }
+
+// Register well-known routes
+func RegisterWellKnownRoutes(g *echo.Group, h auth.Handlers) {
+	g.GET("/jwks.json", h.JWKS)
+}
+
+// Register auth routes requiring authentication
+func RegisterAuthProtectedRoutes(g *echo.Group, h auth.Handlers) {
+	g.POST("/logout", h.Logout)
+	g.POST("/password", h.ChangePassword)
+	g.GET("/sessions", h.ListSessions)
+	g.DELETE("/sessions/:id", h.TerminateSession)
+}
+
+// Register auth admin routes
+func RegisterAuthAdminRoutes(g *echo.Group, h auth.Handlers) {
+	g.POST("/users/:id/revoke-sessions", h.RevokeAllSessions)
+	g.POST("/users/:id/password-reset", h.CreateResetToken)
+}
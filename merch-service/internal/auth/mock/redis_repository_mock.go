@@ -0,0 +1,181 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/auth/redis_repository.go
+
+// Package mock_auth is a generated GoMock package.
+package mock_auth
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockRedisRepository is a mock of RedisRepository interface.
+type MockRedisRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRedisRepositoryMockRecorder
+}
+
+// MockRedisRepositoryMockRecorder is the mock recorder for MockRedisRepository.
+type MockRedisRepositoryMockRecorder struct {
+	mock *MockRedisRepository
+}
+
+// NewMockRedisRepository creates a new mock instance.
+func NewMockRedisRepository(ctrl *gomock.Controller) *MockRedisRepository {
+	mock := &MockRedisRepository{ctrl: ctrl}
+	mock.recorder = &MockRedisRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRedisRepository) EXPECT() *MockRedisRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetLockout mocks base method.
+func (m *MockRedisRepository) GetLockout(ctx context.Context, key string) (time.Duration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLockout", ctx, key)
+	ret0, _ := ret[0].(time.Duration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLockout indicates an expected call of GetLockout.
+func (mr *MockRedisRepositoryMockRecorder) GetLockout(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLockout", reflect.TypeOf((*MockRedisRepository)(nil).GetLockout), ctx, key)
+}
+
+// GetRevokedBefore mocks base method.
+func (m *MockRedisRepository) GetRevokedBefore(ctx context.Context, key string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRevokedBefore", ctx, key)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRevokedBefore indicates an expected call of GetRevokedBefore.
+func (mr *MockRedisRepositoryMockRecorder) GetRevokedBefore(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRevokedBefore", reflect.TypeOf((*MockRedisRepository)(nil).GetRevokedBefore), ctx, key)
+}
+
+// IncrFailedAttempts mocks base method.
+func (m *MockRedisRepository) IncrFailedAttempts(ctx context.Context, key string, window time.Duration) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrFailedAttempts", ctx, key, window)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncrFailedAttempts indicates an expected call of IncrFailedAttempts.
+func (mr *MockRedisRepositoryMockRecorder) IncrFailedAttempts(ctx, key, window interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrFailedAttempts", reflect.TypeOf((*MockRedisRepository)(nil).IncrFailedAttempts), ctx, key, window)
+}
+
+// IsTokenRevoked mocks base method.
+func (m *MockRedisRepository) IsTokenRevoked(ctx context.Context, key string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsTokenRevoked", ctx, key)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsTokenRevoked indicates an expected call of IsTokenRevoked.
+func (mr *MockRedisRepositoryMockRecorder) IsTokenRevoked(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsTokenRevoked", reflect.TypeOf((*MockRedisRepository)(nil).IsTokenRevoked), ctx, key)
+}
+
+// PopResetToken mocks base method.
+func (m *MockRedisRepository) PopResetToken(ctx context.Context, key string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PopResetToken", ctx, key)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PopResetToken indicates an expected call of PopResetToken.
+func (mr *MockRedisRepositoryMockRecorder) PopResetToken(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PopResetToken", reflect.TypeOf((*MockRedisRepository)(nil).PopResetToken), ctx, key)
+}
+
+// ResetFailedAttempts mocks base method.
+func (m *MockRedisRepository) ResetFailedAttempts(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetFailedAttempts", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetFailedAttempts indicates an expected call of ResetFailedAttempts.
+func (mr *MockRedisRepositoryMockRecorder) ResetFailedAttempts(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetFailedAttempts", reflect.TypeOf((*MockRedisRepository)(nil).ResetFailedAttempts), ctx, key)
+}
+
+// RevokeToken mocks base method.
+func (m *MockRedisRepository) RevokeToken(ctx context.Context, key string, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeToken", ctx, key, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeToken indicates an expected call of RevokeToken.
+func (mr *MockRedisRepositoryMockRecorder) RevokeToken(ctx, key, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeToken", reflect.TypeOf((*MockRedisRepository)(nil).RevokeToken), ctx, key, ttl)
+}
+
+// SetLockout mocks base method.
+func (m *MockRedisRepository) SetLockout(ctx context.Context, key string, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetLockout", ctx, key, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetLockout indicates an expected call of SetLockout.
+func (mr *MockRedisRepositoryMockRecorder) SetLockout(ctx, key, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLockout", reflect.TypeOf((*MockRedisRepository)(nil).SetLockout), ctx, key, ttl)
+}
+
+// SetResetToken mocks base method.
+func (m *MockRedisRepository) SetResetToken(ctx context.Context, key string, userID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetResetToken", ctx, key, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetResetToken indicates an expected call of SetResetToken.
+func (mr *MockRedisRepositoryMockRecorder) SetResetToken(ctx, key, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetResetToken", reflect.TypeOf((*MockRedisRepository)(nil).SetResetToken), ctx, key, userID)
+}
+
+// SetRevokedBefore mocks base method.
+func (m *MockRedisRepository) SetRevokedBefore(ctx context.Context, key string, revokedBefore int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetRevokedBefore", ctx, key, revokedBefore)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetRevokedBefore indicates an expected call of SetRevokedBefore.
+func (mr *MockRedisRepositoryMockRecorder) SetRevokedBefore(ctx, key, revokedBefore interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRevokedBefore", reflect.TypeOf((*MockRedisRepository)(nil).SetRevokedBefore), ctx, key, revokedBefore)
+}
@@ -10,4 +10,13 @@ import (
 type Repository interface {
 	CreateUser(ctx context.Context, username, passwordHash string) (*m.User, error)
 	GetUserByUsername(ctx context.Context, username string) (*m.User, error)
+	GetUserByID(ctx context.Context, userID int64) (*m.User, error)
+	UpdatePassword(ctx context.Context, userID int64, passwordHash string) error
+	CreateSession(ctx context.Context, session *m.Session, token *m.RefreshToken) error
+	GetSessions(ctx context.Context, userID int64) ([]m.Session, error)
+	GetSession(ctx context.Context, sessionID string) (*m.Session, error)
+	GetRefreshToken(ctx context.Context, tokenHash string) (*m.RefreshToken, error)
+	RotateRefreshToken(ctx context.Context, usedID int64, token *m.RefreshToken, session *m.Session) error
+	RevokeTokenFamily(ctx context.Context, familyID string) error
+	RevokeUserTokens(ctx context.Context, userID int64) error
 }
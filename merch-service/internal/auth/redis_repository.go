@@ -0,0 +1,20 @@
+package auth
+
+import (
+	"context"
+	"time"
+)
+
+// Auth Redis repository interface
+type RedisRepository interface {
+	RevokeToken(ctx context.Context, key string, ttl time.Duration) error
+	IsTokenRevoked(ctx context.Context, key string) (bool, error)
+	SetRevokedBefore(ctx context.Context, key string, revokedBefore int64) error
+	GetRevokedBefore(ctx context.Context, key string) (int64, error)
+	SetResetToken(ctx context.Context, key string, userID int64) error
+	PopResetToken(ctx context.Context, key string) (int64, error)
+	IncrFailedAttempts(ctx context.Context, key string, window time.Duration) (int64, error)
+	ResetFailedAttempts(ctx context.Context, key string) error
+	SetLockout(ctx context.Context, key string, ttl time.Duration) error
+	GetLockout(ctx context.Context, key string) (time.Duration, error)
+}
@@ -4,15 +4,23 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"cyansnbrst/merch-service/internal/auth"
 	m "cyansnbrst/merch-service/internal/models"
 	"cyansnbrst/merch-service/pkg/db"
+	"cyansnbrst/merch-service/pkg/db/postgres"
 )
 
+// Statement executor, either the pool or a transaction
+type querier interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
 // Auth repository struct
 type authRepo struct {
 	db *pgxpool.Pool
@@ -28,7 +36,7 @@ func (r *authRepo) CreateUser(ctx context.Context, username, passwordHash string
 	query := `
 		INSERT INTO users (username, password_hash)
 		VALUES ($1, $2)
-		RETURNING id, username, password_hash, balance, created_at
+		RETURNING id, username, password_hash, balance, role, created_at, deactivated_at
 	`
 
 	var user m.User
@@ -37,9 +45,14 @@ func (r *authRepo) CreateUser(ctx context.Context, username, passwordHash string
 		&user.Username,
 		&user.PasswordHash,
 		&user.Balance,
+		&user.Role,
 		&user.CreatedAt,
+		&user.DeactivatedAt,
 	)
 	if err != nil {
+		if postgres.IsUniqueViolation(err) {
+			return nil, db.ErrUserAlreadyExists
+		}
 		return nil, fmt.Errorf("repo - failed to create user: %w", err)
 	}
 
@@ -49,7 +62,7 @@ func (r *authRepo) CreateUser(ctx context.Context, username, passwordHash string
 // Get user by username
 func (r *authRepo) GetUserByUsername(ctx context.Context, username string) (*m.User, error) {
 	query := `
-		SELECT id, username, password_hash, balance, created_at
+		SELECT id, username, password_hash, balance, role, created_at, deactivated_at
 		FROM users
 		WHERE username = $1
 	`
@@ -60,7 +73,9 @@ func (r *authRepo) GetUserByUsername(ctx context.Context, username string) (*m.U
 		&user.Username,
 		&user.PasswordHash,
 		&user.Balance,
+		&user.Role,
 		&user.CreatedAt,
+		&user.DeactivatedAt,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -71,3 +86,285 @@ func (r *authRepo) GetUserByUsername(ctx context.Context, username string) (*m.U
 
 	return &user, nil
 }
+
+// Get user by id
+func (r *authRepo) GetUserByID(ctx context.Context, userID int64) (*m.User, error) {
+	query := `
+		SELECT id, username, password_hash, balance, role, created_at, deactivated_at
+		FROM users
+		WHERE id = $1
+	`
+
+	var user m.User
+	err := r.db.QueryRow(ctx, query, userID).Scan(
+		&user.ID,
+		&user.Username,
+		&user.PasswordHash,
+		&user.Balance,
+		&user.Role,
+		&user.CreatedAt,
+		&user.DeactivatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, db.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("repo - failed to get user: %w", err)
+	}
+
+	return &user, nil
+}
+
+// Update user's password hash
+func (r *authRepo) UpdatePassword(ctx context.Context, userID int64, passwordHash string) error {
+	query := `
+		UPDATE users
+		SET password_hash = $2
+		WHERE id = $1
+	`
+
+	result, err := r.db.Exec(ctx, query, userID, passwordHash)
+	if err != nil {
+		return fmt.Errorf("repo - failed to update password: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return db.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// Store a new session with its first refresh token
+func (r *authRepo) CreateSession(ctx context.Context, session *m.Session, token *m.RefreshToken) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("repo - failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			log.Printf("repo - failed to rollback transaction: %v", err)
+		}
+	}()
+
+	query := `
+		INSERT INTO sessions (id, user_id, token_id, issued_at, expires_at, user_agent, ip)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err = tx.Exec(ctx, query,
+		session.ID,
+		session.UserID,
+		session.TokenID,
+		session.IssuedAt,
+		session.ExpiresAt,
+		session.UserAgent,
+		session.IP,
+	)
+	if err != nil {
+		return fmt.Errorf("repo - failed to create session: %w", err)
+	}
+
+	if err := r.createRefreshToken(ctx, tx, token); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("repo - failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// Get user's active sessions, most recently issued first
+func (r *authRepo) GetSessions(ctx context.Context, userID int64) ([]m.Session, error) {
+	query := `
+		SELECT id, user_id, token_id, issued_at, expires_at, user_agent, ip, created_at, revoked_at
+		FROM sessions
+		WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > NOW()
+		ORDER BY issued_at DESC
+	`
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("repo - failed to get sessions: %w", err)
+	}
+	defer rows.Close()
+
+	sessions := make([]m.Session, 0)
+	for rows.Next() {
+		var session m.Session
+		if err := scanSession(rows, &session); err != nil {
+			return nil, fmt.Errorf("repo - failed to scan session: %w", err)
+		}
+		sessions = append(sessions, session)
+	}
+
+	return sessions, nil
+}
+
+// Get session by id
+func (r *authRepo) GetSession(ctx context.Context, sessionID string) (*m.Session, error) {
+	query := `
+		SELECT id, user_id, token_id, issued_at, expires_at, user_agent, ip, created_at, revoked_at
+		FROM sessions
+		WHERE id = $1
+	`
+
+	var session m.Session
+	if err := scanSession(r.db.QueryRow(ctx, query, sessionID), &session); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, db.ErrSessionNotFound
+		}
+		return nil, fmt.Errorf("repo - failed to get session: %w", err)
+	}
+
+	return &session, nil
+}
+
+// Get refresh token by hash
+func (r *authRepo) GetRefreshToken(ctx context.Context, tokenHash string) (*m.RefreshToken, error) {
+	query := `
+		SELECT id, user_id, family_id, token_hash, expires_at, used_at, revoked_at
+		FROM refresh_tokens
+		WHERE token_hash = $1
+	`
+
+	var token m.RefreshToken
+	err := r.db.QueryRow(ctx, query, tokenHash).Scan(
+		&token.ID,
+		&token.UserID,
+		&token.FamilyID,
+		&token.TokenHash,
+		&token.ExpiresAt,
+		&token.UsedAt,
+		&token.RevokedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, db.ErrTokenNotFound
+		}
+		return nil, fmt.Errorf("repo - failed to get refresh token: %w", err)
+	}
+
+	return &token, nil
+}
+
+// Mark refresh token as used, store its replacement and record the new access token in the session
+func (r *authRepo) RotateRefreshToken(ctx context.Context, usedID int64, token *m.RefreshToken, session *m.Session) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("repo - failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			log.Printf("repo - failed to rollback transaction: %v", err)
+		}
+	}()
+
+	query := `
+		UPDATE refresh_tokens
+		SET used_at = NOW()
+		WHERE id = $1 AND used_at IS NULL AND revoked_at IS NULL
+	`
+
+	result, err := tx.Exec(ctx, query, usedID)
+	if err != nil {
+		return fmt.Errorf("repo - failed to mark refresh token used: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return db.ErrTokenAlreadyUsed
+	}
+
+	if err := r.createRefreshToken(ctx, tx, token); err != nil {
+		return err
+	}
+
+	sessionQuery := `
+		UPDATE sessions
+		SET token_id = $2, issued_at = $3, expires_at = $4
+		WHERE id = $1
+	`
+
+	_, err = tx.Exec(ctx, sessionQuery, session.ID, session.TokenID, session.IssuedAt, session.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("repo - failed to update session: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("repo - failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// Revoke all refresh tokens issued from one login and end its session
+func (r *authRepo) RevokeTokenFamily(ctx context.Context, familyID string) error {
+	query := `
+		WITH revoked_session AS (
+			UPDATE sessions
+			SET revoked_at = NOW()
+			WHERE id = $1 AND revoked_at IS NULL
+		)
+		UPDATE refresh_tokens
+		SET revoked_at = NOW()
+		WHERE family_id = $1 AND revoked_at IS NULL
+	`
+
+	if _, err := r.db.Exec(ctx, query, familyID); err != nil {
+		return fmt.Errorf("repo - failed to revoke token family: %w", err)
+	}
+
+	return nil
+}
+
+// Revoke all user's refresh tokens and end their sessions
+func (r *authRepo) RevokeUserTokens(ctx context.Context, userID int64) error {
+	query := `
+		WITH revoked_sessions AS (
+			UPDATE sessions
+			SET revoked_at = NOW()
+			WHERE user_id = $1 AND revoked_at IS NULL
+		)
+		UPDATE refresh_tokens
+		SET revoked_at = NOW()
+		WHERE user_id = $1 AND revoked_at IS NULL
+	`
+
+	if _, err := r.db.Exec(ctx, query, userID); err != nil {
+		return fmt.Errorf("repo - failed to revoke user tokens: %w", err)
+	}
+
+	return nil
+}
+
+// Insert refresh token
+func (r *authRepo) createRefreshToken(ctx context.Context, q querier, token *m.RefreshToken) error {
+	query := `
+		INSERT INTO refresh_tokens (user_id, family_id, token_hash, expires_at)
+		VALUES ($1, $2, $3, $4)
+	`
+
+	_, err := q.Exec(ctx, query, token.UserID, token.FamilyID, token.TokenHash, token.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("repo - failed to create refresh token: %w", err)
+	}
+
+	return nil
+}
+
+// Scan session columns
+func scanSession(row pgx.Row, session *m.Session) error {
+	return row.Scan(
+		&session.ID,
+		&session.UserID,
+		&session.TokenID,
+		&session.IssuedAt,
+		&session.ExpiresAt,
+		&session.UserAgent,
+		&session.IP,
+		&session.CreatedAt,
+		&session.RevokedAt,
+	)
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+
+	"cyansnbrst/merch-service/config"
+	"cyansnbrst/merch-service/internal/auth"
+)
+
+// Auth redis repository
+type authRedisRepo struct {
+	cfg         *config.Config
+	redisClient *redis.Client
+}
+
+// Auth redis repository constructor
+func NewAuthRedisRepo(cfg *config.Config, redisClient *redis.Client) auth.RedisRepository {
+	return &authRedisRepo{
+		cfg:         cfg,
+		redisClient: redisClient,
+	}
+}
+
+// Put token into the denylist until it expires
+func (r *authRedisRepo) RevokeToken(ctx context.Context, key string, ttl time.Duration) error {
+	if err := r.redisClient.Set(ctx, key, 1, ttl).Err(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Check if token is in the denylist
+func (r *authRedisRepo) IsTokenRevoked(ctx context.Context, key string) (bool, error) {
+	n, err := r.redisClient.Exists(ctx, key).Result()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// Revoke all user's tokens issued up to the given unix time in milliseconds
+func (r *authRedisRepo) SetRevokedBefore(ctx context.Context, key string, revokedBefore int64) error {
+	if err := r.redisClient.Set(ctx, key, revokedBefore, r.cfg.App.JWTTokenTTL).Err(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Get unix time in milliseconds up to which user's tokens are revoked, 0 if none
+func (r *authRedisRepo) GetRevokedBefore(ctx context.Context, key string) (int64, error) {
+	revokedBefore, err := r.redisClient.Get(ctx, key).Int64()
+	if err != nil {
+		if err == redis.Nil {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return revokedBefore, nil
+}
+
+// Store one-time password reset token
+func (r *authRedisRepo) SetResetToken(ctx context.Context, key string, userID int64) error {
+	if err := r.redisClient.Set(ctx, key, userID, r.cfg.App.PasswordResetTTL).Err(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Get user of password reset token and delete it, 0 if token doesn't exist
+func (r *authRedisRepo) PopResetToken(ctx context.Context, key string) (int64, error) {
+	userID, err := r.redisClient.GetDel(ctx, key).Int64()
+	if err != nil {
+		if err == redis.Nil {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return userID, nil
+}
+
+// Count failed login attempt, the counter expires after the window of inactivity
+func (r *authRedisRepo) IncrFailedAttempts(ctx context.Context, key string, window time.Duration) (int64, error) {
+	pipe := r.redisClient.TxPipeline()
+	incr := pipe.Incr(ctx, key)
+	pipe.Expire(ctx, key, window)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+	return incr.Val(), nil
+}
+
+// Forget failed login attempts
+func (r *authRedisRepo) ResetFailedAttempts(ctx context.Context, key string) error {
+	if err := r.redisClient.Del(ctx, key).Err(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Block logins for the given time
+func (r *authRedisRepo) SetLockout(ctx context.Context, key string, ttl time.Duration) error {
+	if err := r.redisClient.Set(ctx, key, 1, ttl).Err(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Get time left until logins are allowed again, 0 if not locked
+func (r *authRedisRepo) GetLockout(ctx context.Context, key string) (time.Duration, error) {
+	ttl, err := r.redisClient.PTTL(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+	return max(ttl, 0), nil
+}
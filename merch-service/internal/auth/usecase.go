@@ -3,10 +3,22 @@ package auth
 import (
 	"context"
 	"cyansnbrst/merch-service/internal/models"
+	"cyansnbrst/merch-service/pkg/auth/jwt"
 )
 
 // Auth usecase interface
 type UseCase interface {
-	LoginOrRegister(ctx context.Context, username, password string) (string, error)
-	GenerateJWT(user *models.User) (string, error)
+	LoginOrRegister(ctx context.Context, username, password string, client models.ClientInfo) (*models.AuthResponse, error)
+	Register(ctx context.Context, username, password string, client models.ClientInfo) (*models.AuthResponse, error)
+	Login(ctx context.Context, username, password string, client models.ClientInfo) (*models.AuthResponse, error)
+	Refresh(ctx context.Context, refreshToken string) (*models.AuthResponse, error)
+	ValidateToken(ctx context.Context, tokenString string) (*jwt.Claims, error)
+	JWKS() jwt.JWKS
+	Logout(ctx context.Context, claims *jwt.Claims) error
+	ListSessions(ctx context.Context, userID int64, currentSessionID string) ([]models.Session, error)
+	TerminateSession(ctx context.Context, userID int64, sessionID string) error
+	RevokeAllSessions(ctx context.Context, userID int64) error
+	ChangePassword(ctx context.Context, userID int64, oldPassword, newPassword string) error
+	CreateResetToken(ctx context.Context, userID int64) (*models.PasswordResetResponse, error)
+	ResetPassword(ctx context.Context, resetToken, newPassword string) error
 }
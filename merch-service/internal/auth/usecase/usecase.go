@@ -5,51 +5,519 @@ import (
 	"errors"
 	"fmt"
 	"time"
+	"unicode/utf8"
 
 	"github.com/alexedwards/argon2id"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 
 	"cyansnbrst/merch-service/config"
 	"cyansnbrst/merch-service/internal/auth"
 	"cyansnbrst/merch-service/internal/models"
+	pkgauth "cyansnbrst/merch-service/pkg/auth"
+	authjwt "cyansnbrst/merch-service/pkg/auth/jwt"
+	"cyansnbrst/merch-service/pkg/auth/token"
 	"cyansnbrst/merch-service/pkg/db"
+	"cyansnbrst/merch-service/pkg/db/redis"
 )
 
-var ErrIncorrectPassword = errors.New("incorrect password")
+// Login blocked after too many failed attempts
+type LoginLockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginLockedError) Error() string {
+	return "too many failed login attempts, try again later"
+}
+
+// Longest user agent stored with a session
+const maxUserAgentLength = 255
+
+// Hash checked for unknown usernames, so they take as long as a wrong password
+const dummyPasswordHash = "$argon2id$v=19$m=65536,t=1,p=1$xRaTGFc5MNYXaZ2gPuHVHQ$g5h1IOxyh+V7FAWdQpiM6ir9uzd2yPYIzlRc9JiE/x4"
+
+var (
+	ErrIncorrectPassword   = errors.New("incorrect password")
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token reuse detected, please log in again")
+	ErrInvalidResetToken   = errors.New("invalid or expired password reset token")
+)
 
 // Auth usecase struct
 type authUC struct {
-	cfg      *config.Config
-	authRepo auth.Repository
+	cfg           *config.Config
+	authRepo      auth.Repository
+	authRedisRepo auth.RedisRepository
+	keys          *authjwt.Keys
 }
 
 // Auth usecase constructor
-func NewAuthUseCase(cfg *config.Config, authRepo auth.Repository) auth.UseCase {
+func NewAuthUseCase(cfg *config.Config, authRepo auth.Repository, authRedisRepo auth.RedisRepository, keys *authjwt.Keys) auth.UseCase {
 	return &authUC{
-		cfg:      cfg,
-		authRepo: authRepo,
+		cfg:           cfg,
+		authRepo:      authRepo,
+		authRedisRepo: authRedisRepo,
+		keys:          keys,
 	}
 }
 
 // Login or register user
-func (u *authUC) LoginOrRegister(ctx context.Context, username, password string) (string, error) {
+func (u *authUC) LoginOrRegister(ctx context.Context, username, password string, client models.ClientInfo) (*models.AuthResponse, error) {
+	if err := u.checkLockout(ctx, username, client.IP); err != nil {
+		return nil, err
+	}
+
 	user, err := u.authRepo.GetUserByUsername(ctx, username)
 	if err != nil && !errors.Is(err, db.ErrUserNotFound) {
-		return "", err
+		return nil, err
 	}
 
 	if user == nil {
 		user, err = u.createUser(ctx, username, password)
 		if err != nil {
-			return "", err
+			return nil, err
 		}
 	} else {
-		if err := u.validatePassword(user, password); err != nil {
-			return "", err
+		if err := u.verifyLogin(ctx, user, password, client.IP); err != nil {
+			return nil, err
+		}
+	}
+
+	return u.issueTokens(ctx, user, client)
+}
+
+// Register a new user
+func (u *authUC) Register(ctx context.Context, username, password string, client models.ClientInfo) (*models.AuthResponse, error) {
+	user, err := u.createUser(ctx, username, password)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.issueTokens(ctx, user, client)
+}
+
+// Login existing user
+func (u *authUC) Login(ctx context.Context, username, password string, client models.ClientInfo) (*models.AuthResponse, error) {
+	if err := u.checkLockout(ctx, username, client.IP); err != nil {
+		return nil, err
+	}
+
+	user, err := u.authRepo.GetUserByUsername(ctx, username)
+	if err != nil {
+		if errors.Is(err, db.ErrUserNotFound) {
+			if _, err := argon2id.ComparePasswordAndHash(password, dummyPasswordHash); err != nil {
+				return nil, err
+			}
+			if err := u.recordFailedLogin(ctx, username, client.IP); err != nil {
+				return nil, err
+			}
+		}
+		return nil, err
+	}
+
+	if err := u.verifyLogin(ctx, user, password, client.IP); err != nil {
+		return nil, err
+	}
+
+	return u.issueTokens(ctx, user, client)
+}
+
+// Check password, counting failures and clearing them on success, then check the account is active
+func (u *authUC) verifyLogin(ctx context.Context, user *models.User, password, clientIP string) error {
+	if err := u.validatePassword(user, password); err != nil {
+		if errors.Is(err, ErrIncorrectPassword) {
+			if err := u.recordFailedLogin(ctx, user.Username, clientIP); err != nil {
+				return err
+			}
 		}
+		return err
 	}
 
-	return u.GenerateJWT(user)
+	// Only the username counter is cleared, the client IP one expires by TTL,
+	// so logging in to an own account doesn't reset guessing of others
+	if u.cfg.App.MaxLoginAttempts > 0 {
+		err := u.authRedisRepo.ResetFailedAttempts(ctx, redis.GetLoginAttemptsKey(usernameSubject(user.Username)))
+		if err != nil {
+			return fmt.Errorf("uc - failed to reset login attempts: %w", err)
+		}
+	}
+
+	if user.DeactivatedAt != nil {
+		return db.ErrUserDeactivated
+	}
+
+	return nil
+}
+
+// Reject login while the username or client IP is locked out
+func (u *authUC) checkLockout(ctx context.Context, username, clientIP string) error {
+	for _, subject := range u.loginSubjects(username, clientIP) {
+		retryAfter, err := u.authRedisRepo.GetLockout(ctx, redis.GetLoginLockoutKey(subject.key))
+		if err != nil {
+			return fmt.Errorf("uc - failed to get login lockout: %w", err)
+		}
+		if retryAfter > 0 {
+			return &LoginLockedError{RetryAfter: retryAfter}
+		}
+	}
+	return nil
+}
+
+// Count failed login and lock out subjects that reached the limit
+func (u *authUC) recordFailedLogin(ctx context.Context, username, clientIP string) error {
+	for _, subject := range u.loginSubjects(username, clientIP) {
+		attempts, err := u.authRedisRepo.IncrFailedAttempts(ctx, redis.GetLoginAttemptsKey(subject.key), u.cfg.App.MaxLoginLockout)
+		if err != nil {
+			return fmt.Errorf("uc - failed to count login attempt: %w", err)
+		}
+
+		if attempts < subject.limit {
+			continue
+		}
+
+		lockout := u.lockoutDuration(attempts - subject.limit)
+		if err := u.authRedisRepo.SetLockout(ctx, redis.GetLoginLockoutKey(subject.key), lockout); err != nil {
+			return fmt.Errorf("uc - failed to set login lockout: %w", err)
+		}
+	}
+	return nil
+}
+
+// Lockout doubles with every failed attempt over the limit
+func (u *authUC) lockoutDuration(overLimit int64) time.Duration {
+	lockout := u.cfg.App.LoginLockout
+	for i := int64(0); i < overLimit && lockout < u.cfg.App.MaxLoginLockout; i++ {
+		lockout *= 2
+	}
+	return min(lockout, u.cfg.App.MaxLoginLockout)
+}
+
+// Rate limited login subject
+type loginSubject struct {
+	key   string
+	limit int64
+}
+
+// Username and client IP subjects with enabled limits
+func (u *authUC) loginSubjects(username, clientIP string) []loginSubject {
+	if u.cfg.App.LoginLockout <= 0 || u.cfg.App.MaxLoginLockout <= 0 {
+		return nil
+	}
+
+	var subjects []loginSubject
+	if u.cfg.App.MaxLoginAttempts > 0 {
+		subjects = append(subjects, loginSubject{key: usernameSubject(username), limit: u.cfg.App.MaxLoginAttempts})
+	}
+	if u.cfg.App.MaxLoginAttemptsPerIP > 0 && clientIP != "" {
+		subjects = append(subjects, loginSubject{key: "ip:" + clientIP, limit: u.cfg.App.MaxLoginAttemptsPerIP})
+	}
+	return subjects
+}
+
+func usernameSubject(username string) string {
+	return "user:" + username
+}
+
+// Exchange refresh token for a new token pair, revoking the whole family on reuse
+func (u *authUC) Refresh(ctx context.Context, refreshToken string) (*models.AuthResponse, error) {
+	stored, err := u.authRepo.GetRefreshToken(ctx, token.Hash(refreshToken))
+	if err != nil {
+		if errors.Is(err, db.ErrTokenNotFound) {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+
+	if stored.UsedAt != nil || stored.RevokedAt != nil {
+		return nil, u.revokeFamily(ctx, stored.FamilyID)
+	}
+
+	if time.Now().After(stored.ExpiresAt) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	user, err := u.authRepo.GetUserByID(ctx, stored.UserID)
+	if err != nil {
+		if errors.Is(err, db.ErrUserNotFound) {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+
+	if user.DeactivatedAt != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	accessToken, claims, err := u.generateJWT(user, stored.FamilyID)
+	if err != nil {
+		return nil, err
+	}
+
+	newToken, next, err := u.newRefreshToken(user.ID, stored.FamilyID)
+	if err != nil {
+		return nil, err
+	}
+
+	session := &models.Session{
+		ID:        stored.FamilyID,
+		TokenID:   claims.ID,
+		IssuedAt:  claims.IssuedAt,
+		ExpiresAt: next.ExpiresAt,
+	}
+
+	if err := u.authRepo.RotateRefreshToken(ctx, stored.ID, next, session); err != nil {
+		if errors.Is(err, db.ErrTokenAlreadyUsed) {
+			return nil, u.revokeFamily(ctx, stored.FamilyID)
+		}
+		return nil, err
+	}
+
+	return &models.AuthResponse{Token: accessToken, RefreshToken: newToken}, nil
+}
+
+// Public keys for verifying access tokens
+func (u *authUC) JWKS() authjwt.JWKS {
+	return u.keys.JWKS()
+}
+
+// Parse access token and check it wasn't revoked
+func (u *authUC) ValidateToken(ctx context.Context, tokenString string) (*authjwt.Claims, error) {
+	claims, err := authjwt.ParseJWT(tokenString, u.keys)
+	if err != nil {
+		return nil, err
+	}
+
+	revoked, err := u.authRedisRepo.IsTokenRevoked(ctx, redis.GetRevokedTokenKey(claims.ID))
+	if err != nil {
+		return nil, fmt.Errorf("uc - failed to check token denylist: %w", err)
+	}
+	if revoked {
+		return nil, pkgauth.ErrRevokedToken
+	}
+
+	if claims.SessionID != "" {
+		revoked, err := u.authRedisRepo.IsTokenRevoked(ctx, redis.GetRevokedSessionKey(claims.SessionID))
+		if err != nil {
+			return nil, fmt.Errorf("uc - failed to check session denylist: %w", err)
+		}
+		if revoked {
+			return nil, pkgauth.ErrRevokedToken
+		}
+	}
+
+	revokedBefore, err := u.authRedisRepo.GetRevokedBefore(ctx, redis.GetUserRevokedBeforeKey(claims.UserID))
+	if err != nil {
+		return nil, fmt.Errorf("uc - failed to check user revocation: %w", err)
+	}
+	if claims.IssuedAt.UnixMilli() <= revokedBefore {
+		return nil, pkgauth.ErrRevokedToken
+	}
+
+	return claims, nil
+}
+
+// Revoke current access token and end its session
+func (u *authUC) Logout(ctx context.Context, claims *authjwt.Claims) error {
+	if ttl := time.Until(claims.ExpiresAt); ttl > 0 {
+		if err := u.authRedisRepo.RevokeToken(ctx, redis.GetRevokedTokenKey(claims.ID), ttl); err != nil {
+			return fmt.Errorf("uc - failed to revoke token: %w", err)
+		}
+	}
+
+	return u.endSession(ctx, claims.SessionID)
+}
+
+// Get user's active sessions, marking the one of the current token
+func (u *authUC) ListSessions(ctx context.Context, userID int64, currentSessionID string) ([]models.Session, error) {
+	sessions, err := u.authRepo.GetSessions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == currentSessionID
+	}
+
+	return sessions, nil
+}
+
+// End one of the user's sessions
+func (u *authUC) TerminateSession(ctx context.Context, userID int64, sessionID string) error {
+	session, err := u.authRepo.GetSession(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+
+	if session.UserID != userID || session.RevokedAt != nil {
+		return db.ErrSessionNotFound
+	}
+
+	return u.endSession(ctx, sessionID)
+}
+
+// Revoke all user's access and refresh tokens
+func (u *authUC) RevokeAllSessions(ctx context.Context, userID int64) error {
+	if _, err := u.authRepo.GetUserByID(ctx, userID); err != nil {
+		return err
+	}
+
+	return u.revokeSessions(ctx, userID)
+}
+
+// Change password of the current user and log out everywhere
+func (u *authUC) ChangePassword(ctx context.Context, userID int64, oldPassword, newPassword string) error {
+	user, err := u.authRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := u.validatePassword(user, oldPassword); err != nil {
+		return err
+	}
+
+	return u.setPassword(ctx, userID, newPassword)
+}
+
+// Issue one-time password reset token for the user
+func (u *authUC) CreateResetToken(ctx context.Context, userID int64) (*models.PasswordResetResponse, error) {
+	if _, err := u.authRepo.GetUserByID(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	resetToken, err := token.Generate()
+	if err != nil {
+		return nil, fmt.Errorf("uc - %w", err)
+	}
+
+	err = u.authRedisRepo.SetResetToken(ctx, redis.GetPasswordResetKey(token.Hash(resetToken)), userID)
+	if err != nil {
+		return nil, fmt.Errorf("uc - failed to store reset token: %w", err)
+	}
+
+	return &models.PasswordResetResponse{
+		ResetToken: resetToken,
+		ExpiresAt:  time.Now().Add(u.cfg.App.PasswordResetTTL),
+	}, nil
+}
+
+// Set new password using one-time reset token
+func (u *authUC) ResetPassword(ctx context.Context, resetToken, newPassword string) error {
+	userID, err := u.authRedisRepo.PopResetToken(ctx, redis.GetPasswordResetKey(token.Hash(resetToken)))
+	if err != nil {
+		return fmt.Errorf("uc - failed to get reset token: %w", err)
+	}
+	if userID == 0 {
+		return ErrInvalidResetToken
+	}
+
+	return u.setPassword(ctx, userID, newPassword)
+}
+
+// Store new password hash and revoke existing tokens
+func (u *authUC) setPassword(ctx context.Context, userID int64, password string) error {
+	hashedPassword, err := argon2id.CreateHash(password, argon2id.DefaultParams)
+	if err != nil {
+		return err
+	}
+
+	if err := u.authRepo.UpdatePassword(ctx, userID, hashedPassword); err != nil {
+		return err
+	}
+
+	return u.revokeSessions(ctx, userID)
+}
+
+// Revoke user's refresh tokens and access tokens issued so far
+func (u *authUC) revokeSessions(ctx context.Context, userID int64) error {
+	if err := u.authRepo.RevokeUserTokens(ctx, userID); err != nil {
+		return err
+	}
+
+	err := u.authRedisRepo.SetRevokedBefore(ctx, redis.GetUserRevokedBeforeKey(userID), time.Now().UnixMilli())
+	if err != nil {
+		return fmt.Errorf("uc - failed to revoke user tokens: %w", err)
+	}
+
+	return nil
+}
+
+// Start a new session, issuing access token and the first refresh token of its family
+func (u *authUC) issueTokens(ctx context.Context, user *models.User, client models.ClientInfo) (*models.AuthResponse, error) {
+	sessionID := uuid.NewString()
+
+	accessToken, claims, err := u.generateJWT(user, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, stored, err := u.newRefreshToken(user.ID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	session := &models.Session{
+		ID:        sessionID,
+		UserID:    user.ID,
+		TokenID:   claims.ID,
+		IssuedAt:  claims.IssuedAt,
+		ExpiresAt: stored.ExpiresAt,
+		UserAgent: truncate(client.UserAgent, maxUserAgentLength),
+		IP:        client.IP,
+	}
+
+	if err := u.authRepo.CreateSession(ctx, session, stored); err != nil {
+		return nil, err
+	}
+
+	return &models.AuthResponse{Token: accessToken, RefreshToken: refreshToken}, nil
+}
+
+// Generate refresh token and its stored representation
+func (u *authUC) newRefreshToken(userID int64, familyID string) (string, *models.RefreshToken, error) {
+	refreshToken, err := token.Generate()
+	if err != nil {
+		return "", nil, fmt.Errorf("uc - %w", err)
+	}
+
+	return refreshToken, &models.RefreshToken{
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: token.Hash(refreshToken),
+		ExpiresAt: time.Now().Add(u.cfg.App.RefreshTokenTTL),
+	}, nil
+}
+
+// Revoke token family after reuse was detected
+func (u *authUC) revokeFamily(ctx context.Context, familyID string) error {
+	if err := u.endSession(ctx, familyID); err != nil {
+		return err
+	}
+	return ErrRefreshTokenReused
+}
+
+// Revoke session's refresh tokens and access tokens issued so far
+func (u *authUC) endSession(ctx context.Context, sessionID string) error {
+	if err := u.authRepo.RevokeTokenFamily(ctx, sessionID); err != nil {
+		return err
+	}
+
+	err := u.authRedisRepo.RevokeToken(ctx, redis.GetRevokedSessionKey(sessionID), u.cfg.App.JWTTokenTTL)
+	if err != nil {
+		return fmt.Errorf("uc - failed to revoke session: %w", err)
+	}
+
+	return nil
+}
+
+// Cut string to at most n bytes without splitting runes
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
 }
 
 // Create a new user
@@ -74,20 +542,32 @@ func (u *authUC) validatePassword(user *models.User, password string) error {
 	return nil
 }
 
-// Generate JWT token
-func (u *authUC) GenerateJWT(user *models.User) (string, error) {
-	expirationTime := jwt.NewNumericDate(time.Now().Add(u.cfg.App.JWTTokenTTL))
-	claims := jwt.MapClaims{
-		"user_id": user.ID,
-		"exp":     expirationTime,
+// Generate JWT token of the session, returning its claims
+func (u *authUC) generateJWT(user *models.User, sessionID string) (string, *authjwt.Claims, error) {
+	now := time.UnixMilli(time.Now().UnixMilli())
+	claims := &authjwt.Claims{
+		ID:        uuid.NewString(),
+		UserID:    user.ID,
+		Role:      user.Role,
+		SessionID: sessionID,
+		IssuedAt:  now,
+		ExpiresAt: now.Add(u.cfg.App.JWTTokenTTL),
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	mapClaims := jwt.MapClaims{
+		"jti":     claims.ID,
+		"user_id": claims.UserID,
+		"role":    claims.Role,
+		"iat":     jwt.NewNumericDate(now),
+		"iat_ms":  now.UnixMilli(),
+		"exp":     jwt.NewNumericDate(claims.ExpiresAt),
+		"sid":     sessionID,
+	}
 
-	signedToken, err := token.SignedString([]byte(u.cfg.App.JWTSecretKey))
+	signedToken, err := u.keys.Sign(mapClaims)
 	if err != nil {
-		return "", fmt.Errorf("uc - failed to sign token: %w", err)
+		return "", nil, fmt.Errorf("uc - failed to sign token: %w", err)
 	}
 
-	return signedToken, nil
+	return signedToken, claims, nil
 }
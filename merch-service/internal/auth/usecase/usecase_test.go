@@ -2,7 +2,15 @@ package usecase
 
 import (
 	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
 	"errors"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -12,13 +20,24 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"cyansnbrst/merch-service/config"
+	"cyansnbrst/merch-service/internal/auth"
 	mock_auth "cyansnbrst/merch-service/internal/auth/mock"
 	"cyansnbrst/merch-service/internal/models"
+	pkgauth "cyansnbrst/merch-service/pkg/auth"
+	authjwt "cyansnbrst/merch-service/pkg/auth/jwt"
 	"cyansnbrst/merch-service/pkg/db"
 )
 
 var ErrRandomDBError = errors.New("db error")
 
+func loadKeys(t *testing.T, cfg *config.Config) *authjwt.Keys {
+	keys, err := authjwt.LoadKeys(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return keys
+}
+
 func TestAuthUC_LoginOrRegister(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -26,12 +45,13 @@ func TestAuthUC_LoginOrRegister(t *testing.T) {
 	mockRepo := mock_auth.NewMockRepository(ctrl)
 	cfg := &config.Config{
 		App: config.App{
+			JWTAlgorithm: authjwt.AlgorithmHS256,
 			JWTSecretKey: "secret",
 			JWTTokenTTL:  time.Hour * 1,
 		},
 	}
 
-	authUC := NewAuthUseCase(cfg, mockRepo)
+	authUC := NewAuthUseCase(cfg, mockRepo, nil, loadKeys(t, cfg))
 
 	tests := []struct {
 		name          string
@@ -51,6 +71,7 @@ func TestAuthUC_LoginOrRegister(t *testing.T) {
 					Username:     "user",
 					PasswordHash: hashedPassword,
 				}, nil)
+				mockRepo.EXPECT().CreateSession(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
 			},
 			expectedError: nil,
 		},
@@ -78,6 +99,7 @@ func TestAuthUC_LoginOrRegister(t *testing.T) {
 					ID:       2,
 					Username: "user",
 				}, nil)
+				mockRepo.EXPECT().CreateSession(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
 			},
 			expectedError: nil,
 		},
@@ -105,7 +127,7 @@ func TestAuthUC_LoginOrRegister(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockSetup()
-			result, err := authUC.LoginOrRegister(context.Background(), tt.username, tt.password)
+			result, err := authUC.LoginOrRegister(context.Background(), tt.username, tt.password, models.ClientInfo{IP: "127.0.0.1"})
 
 			assert.Equal(t, tt.expectedError, err)
 
@@ -117,26 +139,317 @@ func TestAuthUC_LoginOrRegister(t *testing.T) {
 	}
 }
 
+func TestAuthUC_Register(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_auth.NewMockRepository(ctrl)
+	cfg := &config.Config{
+		App: config.App{
+			JWTAlgorithm: authjwt.AlgorithmHS256,
+			JWTSecretKey: "secret",
+			JWTTokenTTL:  time.Hour * 1,
+		},
+	}
+
+	authUC := NewAuthUseCase(cfg, mockRepo, nil, loadKeys(t, cfg))
+
+	tests := []struct {
+		name          string
+		username      string
+		password      string
+		mockSetup     func()
+		expectedError error
+	}{
+		{
+			name:     "success",
+			username: "user",
+			password: "password",
+			mockSetup: func() {
+				mockRepo.EXPECT().CreateUser(gomock.Any(), "user", gomock.Any()).Return(&models.User{
+					ID:       1,
+					Username: "user",
+					Role:     models.RoleUser,
+				}, nil)
+				mockRepo.EXPECT().CreateSession(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, session *models.Session, token *models.RefreshToken) error {
+						assert.Equal(t, token.FamilyID, session.ID)
+						assert.Equal(t, int64(1), session.UserID)
+						assert.NotEmpty(t, session.TokenID)
+						assert.Equal(t, "127.0.0.1", session.IP)
+						assert.Equal(t, "curl/8.5.0", session.UserAgent)
+						return nil
+					})
+			},
+			expectedError: nil,
+		},
+		{
+			name:     "username taken",
+			username: "user",
+			password: "password",
+			mockSetup: func() {
+				mockRepo.EXPECT().CreateUser(gomock.Any(), "user", gomock.Any()).Return(nil, db.ErrUserAlreadyExists)
+			},
+			expectedError: db.ErrUserAlreadyExists,
+		},
+		{
+			name:     "db error",
+			username: "user",
+			password: "password",
+			mockSetup: func() {
+				mockRepo.EXPECT().CreateUser(gomock.Any(), "user", gomock.Any()).Return(nil, ErrRandomDBError)
+			},
+			expectedError: ErrRandomDBError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+			result, err := authUC.Register(context.Background(), tt.username, tt.password, models.ClientInfo{IP: "127.0.0.1", UserAgent: "curl/8.5.0"})
+
+			assert.Equal(t, tt.expectedError, err)
+
+			if tt.expectedError == nil {
+				assert.NotEmpty(t, result)
+			}
+		})
+	}
+}
+
+func TestAuthUC_Login(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_auth.NewMockRepository(ctrl)
+	cfg := &config.Config{
+		App: config.App{
+			JWTAlgorithm: authjwt.AlgorithmHS256,
+			JWTSecretKey: "secret",
+			JWTTokenTTL:  time.Hour * 1,
+		},
+	}
+
+	authUC := NewAuthUseCase(cfg, mockRepo, nil, loadKeys(t, cfg))
+
+	hashedPassword, err := argon2id.CreateHash("password", argon2id.DefaultParams)
+	assert.NoError(t, err)
+
+	deactivatedAt := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name          string
+		username      string
+		password      string
+		mockSetup     func()
+		expectedError error
+	}{
+		{
+			name:     "success",
+			username: "user",
+			password: "password",
+			mockSetup: func() {
+				mockRepo.EXPECT().GetUserByUsername(gomock.Any(), "user").Return(&models.User{
+					ID:           1,
+					Username:     "user",
+					PasswordHash: hashedPassword,
+					Role:         models.RoleUser,
+				}, nil)
+				mockRepo.EXPECT().CreateSession(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:     "incorrect password",
+			username: "user",
+			password: "wrong_password",
+			mockSetup: func() {
+				mockRepo.EXPECT().GetUserByUsername(gomock.Any(), "user").Return(&models.User{
+					ID:           1,
+					Username:     "user",
+					PasswordHash: hashedPassword,
+				}, nil)
+			},
+			expectedError: ErrIncorrectPassword,
+		},
+		{
+			name:     "user deactivated",
+			username: "user",
+			password: "password",
+			mockSetup: func() {
+				mockRepo.EXPECT().GetUserByUsername(gomock.Any(), "user").Return(&models.User{
+					ID:            1,
+					Username:      "user",
+					PasswordHash:  hashedPassword,
+					DeactivatedAt: &deactivatedAt,
+				}, nil)
+			},
+			expectedError: db.ErrUserDeactivated,
+		},
+		{
+			name:     "user not found",
+			username: "unknown",
+			password: "password",
+			mockSetup: func() {
+				mockRepo.EXPECT().GetUserByUsername(gomock.Any(), "unknown").Return(nil, db.ErrUserNotFound)
+			},
+			expectedError: db.ErrUserNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+			result, err := authUC.Login(context.Background(), tt.username, tt.password, models.ClientInfo{IP: "127.0.0.1"})
+
+			assert.Equal(t, tt.expectedError, err)
+
+			if tt.expectedError == nil {
+				assert.NotEmpty(t, result)
+			}
+		})
+	}
+}
+
+func TestAuthUC_Refresh(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_auth.NewMockRepository(ctrl)
+	mockRedisRepo := mock_auth.NewMockRedisRepository(ctrl)
+	cfg := &config.Config{
+		App: config.App{
+			JWTAlgorithm:    authjwt.AlgorithmHS256,
+			JWTSecretKey:    "secret",
+			JWTTokenTTL:     time.Minute * 15,
+			RefreshTokenTTL: time.Hour * 24,
+		},
+	}
+
+	authUC := NewAuthUseCase(cfg, mockRepo, mockRedisRepo, loadKeys(t, cfg))
+
+	usedAt := time.Now().Add(-time.Minute)
+	validToken := func() *models.RefreshToken {
+		return &models.RefreshToken{
+			ID:        1,
+			UserID:    1,
+			FamilyID:  "family",
+			ExpiresAt: time.Now().Add(time.Hour),
+		}
+	}
+
+	tests := []struct {
+		name          string
+		mockSetup     func()
+		expectedError error
+	}{
+		{
+			name: "success",
+			mockSetup: func() {
+				mockRepo.EXPECT().GetRefreshToken(gomock.Any(), gomock.Any()).Return(validToken(), nil)
+				mockRepo.EXPECT().GetUserByID(gomock.Any(), int64(1)).Return(&models.User{ID: 1, Role: models.RoleUser}, nil)
+				mockRepo.EXPECT().RotateRefreshToken(gomock.Any(), int64(1), gomock.Not(gomock.Nil()), gomock.Not(gomock.Nil())).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name: "token not found",
+			mockSetup: func() {
+				mockRepo.EXPECT().GetRefreshToken(gomock.Any(), gomock.Any()).Return(nil, db.ErrTokenNotFound)
+			},
+			expectedError: ErrInvalidRefreshToken,
+		},
+		{
+			name: "user deactivated",
+			mockSetup: func() {
+				mockRepo.EXPECT().GetRefreshToken(gomock.Any(), gomock.Any()).Return(validToken(), nil)
+				mockRepo.EXPECT().GetUserByID(gomock.Any(), int64(1)).Return(&models.User{ID: 1, DeactivatedAt: &usedAt}, nil)
+			},
+			expectedError: ErrInvalidRefreshToken,
+		},
+		{
+			name: "token expired",
+			mockSetup: func() {
+				expired := validToken()
+				expired.ExpiresAt = time.Now().Add(-time.Hour)
+				mockRepo.EXPECT().GetRefreshToken(gomock.Any(), gomock.Any()).Return(expired, nil)
+			},
+			expectedError: ErrInvalidRefreshToken,
+		},
+		{
+			name: "token reused",
+			mockSetup: func() {
+				used := validToken()
+				used.UsedAt = &usedAt
+				mockRepo.EXPECT().GetRefreshToken(gomock.Any(), gomock.Any()).Return(used, nil)
+				mockRepo.EXPECT().RevokeTokenFamily(gomock.Any(), "family").Return(nil)
+				mockRedisRepo.EXPECT().RevokeToken(gomock.Any(), "session:family:revoked", cfg.App.JWTTokenTTL).Return(nil)
+			},
+			expectedError: ErrRefreshTokenReused,
+		},
+		{
+			name: "token used concurrently",
+			mockSetup: func() {
+				mockRepo.EXPECT().GetRefreshToken(gomock.Any(), gomock.Any()).Return(validToken(), nil)
+				mockRepo.EXPECT().GetUserByID(gomock.Any(), int64(1)).Return(&models.User{ID: 1, Role: models.RoleUser}, nil)
+				mockRepo.EXPECT().RotateRefreshToken(gomock.Any(), int64(1), gomock.Any(), gomock.Any()).Return(db.ErrTokenAlreadyUsed)
+				mockRepo.EXPECT().RevokeTokenFamily(gomock.Any(), "family").Return(nil)
+				mockRedisRepo.EXPECT().RevokeToken(gomock.Any(), "session:family:revoked", cfg.App.JWTTokenTTL).Return(nil)
+			},
+			expectedError: ErrRefreshTokenReused,
+		},
+		{
+			name: "db error",
+			mockSetup: func() {
+				mockRepo.EXPECT().GetRefreshToken(gomock.Any(), gomock.Any()).Return(nil, ErrRandomDBError)
+			},
+			expectedError: ErrRandomDBError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+			result, err := authUC.Refresh(context.Background(), "refresh-token")
+
+			assert.Equal(t, tt.expectedError, err)
+
+			if tt.expectedError == nil {
+				assert.NotEmpty(t, result.Token)
+				assert.NotEmpty(t, result.RefreshToken)
+			}
+		})
+	}
+}
+
+// Generate access token bound to the session
+func generateSessionJWT(t *testing.T, uc auth.UseCase, user *models.User, sessionID string) string {
+	token, _, err := uc.(*authUC).generateJWT(user, sessionID)
+	assert.NoError(t, err)
+	return token
+}
+
 func TestAuthUC_GenerateJWT(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	cfg := &config.Config{
 		App: config.App{
+			JWTAlgorithm: authjwt.AlgorithmHS256,
 			JWTSecretKey: "secret",
 			JWTTokenTTL:  time.Hour * 1,
 		},
 	}
 
-	authUC := NewAuthUseCase(cfg, nil)
+	authUC := NewAuthUseCase(cfg, nil, nil, loadKeys(t, cfg))
 
 	user := &models.User{
 		ID:       1,
 		Username: "user",
+		Role:     models.RoleAdmin,
 	}
 
-	token, err := authUC.GenerateJWT(user)
-	assert.NoError(t, err)
+	token := generateSessionJWT(t, authUC, user, "session")
 	assert.NotEmpty(t, token)
 
 	parsedToken, err := jwt.Parse(token, func(token *jwt.Token) (interface{}, error) {
@@ -144,4 +457,665 @@ func TestAuthUC_GenerateJWT(t *testing.T) {
 	})
 	assert.NoError(t, err)
 	assert.True(t, parsedToken.Valid)
+
+	claims, ok := parsedToken.Claims.(jwt.MapClaims)
+	assert.True(t, ok)
+	assert.Equal(t, models.RoleAdmin, claims["role"])
+	assert.Equal(t, "session", claims["sid"])
+	assert.NotEmpty(t, claims["jti"])
+}
+
+func TestAuthUC_ValidateToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRedisRepo := mock_auth.NewMockRedisRepository(ctrl)
+	cfg := &config.Config{
+		App: config.App{
+			JWTAlgorithm: authjwt.AlgorithmHS256,
+			JWTSecretKey: "secret",
+			JWTTokenTTL:  time.Minute * 15,
+		},
+	}
+
+	authUC := NewAuthUseCase(cfg, nil, mockRedisRepo, loadKeys(t, cfg))
+
+	token := generateSessionJWT(t, authUC, &models.User{ID: 1, Role: models.RoleUser}, "session")
+
+	tests := []struct {
+		name          string
+		token         string
+		mockSetup     func()
+		expectedError error
+	}{
+		{
+			name:  "valid session token",
+			token: token,
+			mockSetup: func() {
+				mockRedisRepo.EXPECT().IsTokenRevoked(gomock.Any(), gomock.Not("session:session:revoked")).Return(false, nil)
+				mockRedisRepo.EXPECT().IsTokenRevoked(gomock.Any(), "session:session:revoked").Return(false, nil)
+				mockRedisRepo.EXPECT().GetRevokedBefore(gomock.Any(), "user:1:revoked_before").Return(int64(0), nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:  "session terminated",
+			token: token,
+			mockSetup: func() {
+				mockRedisRepo.EXPECT().IsTokenRevoked(gomock.Any(), gomock.Not("session:session:revoked")).Return(false, nil)
+				mockRedisRepo.EXPECT().IsTokenRevoked(gomock.Any(), "session:session:revoked").Return(true, nil)
+			},
+			expectedError: pkgauth.ErrRevokedToken,
+		},
+		{
+			name:          "malformed token",
+			token:         "not-a-token",
+			mockSetup:     func() {},
+			expectedError: pkgauth.ErrInvalidToken,
+		},
+		{
+			name:  "token in denylist",
+			token: token,
+			mockSetup: func() {
+				mockRedisRepo.EXPECT().IsTokenRevoked(gomock.Any(), gomock.Any()).Return(true, nil)
+			},
+			expectedError: pkgauth.ErrRevokedToken,
+		},
+		{
+			name:  "all user tokens revoked",
+			token: token,
+			mockSetup: func() {
+				mockRedisRepo.EXPECT().IsTokenRevoked(gomock.Any(), gomock.Any()).Return(false, nil).Times(2)
+				mockRedisRepo.EXPECT().GetRevokedBefore(gomock.Any(), "user:1:revoked_before").Return(time.Now().UnixMilli(), nil)
+			},
+			expectedError: pkgauth.ErrRevokedToken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+			claims, err := authUC.ValidateToken(context.Background(), tt.token)
+
+			assert.Equal(t, tt.expectedError, err)
+
+			if tt.expectedError == nil {
+				assert.Equal(t, int64(1), claims.UserID)
+				assert.Equal(t, models.RoleUser, claims.Role)
+			}
+		})
+	}
+}
+
+func TestAuthUC_Logout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_auth.NewMockRepository(ctrl)
+	mockRedisRepo := mock_auth.NewMockRedisRepository(ctrl)
+	cfg := &config.Config{}
+
+	authUC := NewAuthUseCase(cfg, mockRepo, mockRedisRepo, nil)
+
+	claims := &authjwt.Claims{
+		ID:        "jti",
+		UserID:    1,
+		SessionID: "session",
+		ExpiresAt: time.Now().Add(time.Minute * 10),
+	}
+
+	tests := []struct {
+		name          string
+		mockSetup     func()
+		expectedError error
+	}{
+		{
+			name: "success",
+			mockSetup: func() {
+				mockRedisRepo.EXPECT().RevokeToken(gomock.Any(), "token:jti:revoked", gomock.Any()).Return(nil)
+				mockRepo.EXPECT().RevokeTokenFamily(gomock.Any(), "session").Return(nil)
+				mockRedisRepo.EXPECT().RevokeToken(gomock.Any(), "session:session:revoked", cfg.App.JWTTokenTTL).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name: "db error",
+			mockSetup: func() {
+				mockRedisRepo.EXPECT().RevokeToken(gomock.Any(), "token:jti:revoked", gomock.Any()).Return(nil)
+				mockRepo.EXPECT().RevokeTokenFamily(gomock.Any(), "session").Return(ErrRandomDBError)
+			},
+			expectedError: ErrRandomDBError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+			err := authUC.Logout(context.Background(), claims)
+
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
+
+func TestAuthUC_ListSessions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_auth.NewMockRepository(ctrl)
+
+	authUC := NewAuthUseCase(&config.Config{}, mockRepo, nil, nil)
+
+	mockRepo.EXPECT().GetSessions(gomock.Any(), int64(1)).Return([]models.Session{
+		{ID: "first", UserID: 1},
+		{ID: "second", UserID: 1},
+	}, nil)
+
+	sessions, err := authUC.ListSessions(context.Background(), 1, "second")
+	assert.NoError(t, err)
+	assert.Len(t, sessions, 2)
+	assert.False(t, sessions[0].Current)
+	assert.True(t, sessions[1].Current)
+
+	mockRepo.EXPECT().GetSessions(gomock.Any(), int64(1)).Return(nil, ErrRandomDBError)
+
+	_, err = authUC.ListSessions(context.Background(), 1, "second")
+	assert.Equal(t, ErrRandomDBError, err)
+}
+
+func TestAuthUC_TerminateSession(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_auth.NewMockRepository(ctrl)
+	mockRedisRepo := mock_auth.NewMockRedisRepository(ctrl)
+	cfg := &config.Config{
+		App: config.App{
+			JWTTokenTTL: time.Minute * 15,
+		},
+	}
+
+	authUC := NewAuthUseCase(cfg, mockRepo, mockRedisRepo, nil)
+
+	revokedAt := time.Now().Add(-time.Minute)
+
+	tests := []struct {
+		name          string
+		mockSetup     func()
+		expectedError error
+	}{
+		{
+			name: "success",
+			mockSetup: func() {
+				mockRepo.EXPECT().GetSession(gomock.Any(), "session").Return(&models.Session{ID: "session", UserID: 1}, nil)
+				mockRepo.EXPECT().RevokeTokenFamily(gomock.Any(), "session").Return(nil)
+				mockRedisRepo.EXPECT().RevokeToken(gomock.Any(), "session:session:revoked", time.Minute*15).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name: "session not found",
+			mockSetup: func() {
+				mockRepo.EXPECT().GetSession(gomock.Any(), "session").Return(nil, db.ErrSessionNotFound)
+			},
+			expectedError: db.ErrSessionNotFound,
+		},
+		{
+			name: "session of another user",
+			mockSetup: func() {
+				mockRepo.EXPECT().GetSession(gomock.Any(), "session").Return(&models.Session{ID: "session", UserID: 2}, nil)
+			},
+			expectedError: db.ErrSessionNotFound,
+		},
+		{
+			name: "session already ended",
+			mockSetup: func() {
+				mockRepo.EXPECT().GetSession(gomock.Any(), "session").Return(&models.Session{ID: "session", UserID: 1, RevokedAt: &revokedAt}, nil)
+			},
+			expectedError: db.ErrSessionNotFound,
+		},
+		{
+			name: "db error",
+			mockSetup: func() {
+				mockRepo.EXPECT().GetSession(gomock.Any(), "session").Return(&models.Session{ID: "session", UserID: 1}, nil)
+				mockRepo.EXPECT().RevokeTokenFamily(gomock.Any(), "session").Return(ErrRandomDBError)
+			},
+			expectedError: ErrRandomDBError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+			err := authUC.TerminateSession(context.Background(), 1, "session")
+
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
+
+func TestAuthUC_RevokeAllSessions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_auth.NewMockRepository(ctrl)
+	mockRedisRepo := mock_auth.NewMockRedisRepository(ctrl)
+	cfg := &config.Config{}
+
+	authUC := NewAuthUseCase(cfg, mockRepo, mockRedisRepo, nil)
+
+	tests := []struct {
+		name          string
+		userID        int64
+		mockSetup     func()
+		expectedError error
+	}{
+		{
+			name:   "success",
+			userID: 1,
+			mockSetup: func() {
+				mockRepo.EXPECT().GetUserByID(gomock.Any(), int64(1)).Return(&models.User{ID: 1}, nil)
+				mockRepo.EXPECT().RevokeUserTokens(gomock.Any(), int64(1)).Return(nil)
+				mockRedisRepo.EXPECT().SetRevokedBefore(gomock.Any(), "user:1:revoked_before", gomock.Any()).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:   "user not found",
+			userID: 2,
+			mockSetup: func() {
+				mockRepo.EXPECT().GetUserByID(gomock.Any(), int64(2)).Return(nil, db.ErrUserNotFound)
+			},
+			expectedError: db.ErrUserNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+			err := authUC.RevokeAllSessions(context.Background(), tt.userID)
+
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
+
+func TestAuthUC_ChangePassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_auth.NewMockRepository(ctrl)
+	mockRedisRepo := mock_auth.NewMockRedisRepository(ctrl)
+	cfg := &config.Config{}
+
+	authUC := NewAuthUseCase(cfg, mockRepo, mockRedisRepo, nil)
+
+	hashedPassword, err := argon2id.CreateHash("password", argon2id.DefaultParams)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		oldPassword   string
+		mockSetup     func()
+		expectedError error
+	}{
+		{
+			name:        "success",
+			oldPassword: "password",
+			mockSetup: func() {
+				mockRepo.EXPECT().GetUserByID(gomock.Any(), int64(1)).Return(&models.User{ID: 1, PasswordHash: hashedPassword}, nil)
+				mockRepo.EXPECT().UpdatePassword(gomock.Any(), int64(1), gomock.Any()).Return(nil)
+				mockRepo.EXPECT().RevokeUserTokens(gomock.Any(), int64(1)).Return(nil)
+				mockRedisRepo.EXPECT().SetRevokedBefore(gomock.Any(), "user:1:revoked_before", gomock.Any()).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:        "incorrect old password",
+			oldPassword: "wrong_password",
+			mockSetup: func() {
+				mockRepo.EXPECT().GetUserByID(gomock.Any(), int64(1)).Return(&models.User{ID: 1, PasswordHash: hashedPassword}, nil)
+			},
+			expectedError: ErrIncorrectPassword,
+		},
+		{
+			name:        "db error",
+			oldPassword: "password",
+			mockSetup: func() {
+				mockRepo.EXPECT().GetUserByID(gomock.Any(), int64(1)).Return(&models.User{ID: 1, PasswordHash: hashedPassword}, nil)
+				mockRepo.EXPECT().UpdatePassword(gomock.Any(), int64(1), gomock.Any()).Return(ErrRandomDBError)
+			},
+			expectedError: ErrRandomDBError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+			err := authUC.ChangePassword(context.Background(), 1, tt.oldPassword, "new_password")
+
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
+
+func TestAuthUC_CreateResetToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_auth.NewMockRepository(ctrl)
+	mockRedisRepo := mock_auth.NewMockRedisRepository(ctrl)
+	cfg := &config.Config{
+		App: config.App{
+			PasswordResetTTL: time.Hour,
+		},
+	}
+
+	authUC := NewAuthUseCase(cfg, mockRepo, mockRedisRepo, nil)
+
+	tests := []struct {
+		name          string
+		userID        int64
+		mockSetup     func()
+		expectedError error
+	}{
+		{
+			name:   "success",
+			userID: 1,
+			mockSetup: func() {
+				mockRepo.EXPECT().GetUserByID(gomock.Any(), int64(1)).Return(&models.User{ID: 1}, nil)
+				mockRedisRepo.EXPECT().SetResetToken(gomock.Any(), gomock.Any(), int64(1)).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:   "user not found",
+			userID: 2,
+			mockSetup: func() {
+				mockRepo.EXPECT().GetUserByID(gomock.Any(), int64(2)).Return(nil, db.ErrUserNotFound)
+			},
+			expectedError: db.ErrUserNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+			result, err := authUC.CreateResetToken(context.Background(), tt.userID)
+
+			assert.Equal(t, tt.expectedError, err)
+
+			if tt.expectedError == nil {
+				assert.NotEmpty(t, result.ResetToken)
+			}
+		})
+	}
+}
+
+func TestAuthUC_ResetPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_auth.NewMockRepository(ctrl)
+	mockRedisRepo := mock_auth.NewMockRedisRepository(ctrl)
+	cfg := &config.Config{}
+
+	authUC := NewAuthUseCase(cfg, mockRepo, mockRedisRepo, nil)
+
+	tests := []struct {
+		name          string
+		mockSetup     func()
+		expectedError error
+	}{
+		{
+			name: "success",
+			mockSetup: func() {
+				mockRedisRepo.EXPECT().PopResetToken(gomock.Any(), gomock.Any()).Return(int64(1), nil)
+				mockRepo.EXPECT().UpdatePassword(gomock.Any(), int64(1), gomock.Any()).Return(nil)
+				mockRepo.EXPECT().RevokeUserTokens(gomock.Any(), int64(1)).Return(nil)
+				mockRedisRepo.EXPECT().SetRevokedBefore(gomock.Any(), "user:1:revoked_before", gomock.Any()).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name: "unknown or used token",
+			mockSetup: func() {
+				mockRedisRepo.EXPECT().PopResetToken(gomock.Any(), gomock.Any()).Return(int64(0), nil)
+			},
+			expectedError: ErrInvalidResetToken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+			err := authUC.ResetPassword(context.Background(), "reset-token", "new_password")
+
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
+
+func TestAuthUC_Login_Lockout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_auth.NewMockRepository(ctrl)
+	mockRedisRepo := mock_auth.NewMockRedisRepository(ctrl)
+	cfg := &config.Config{
+		App: config.App{
+			JWTAlgorithm:          authjwt.AlgorithmHS256,
+			JWTSecretKey:          "secret",
+			JWTTokenTTL:           time.Minute * 15,
+			MaxLoginAttempts:      3,
+			MaxLoginAttemptsPerIP: 10,
+			LoginLockout:          time.Minute,
+			MaxLoginLockout:       time.Minute * 5,
+		},
+	}
+
+	authUC := NewAuthUseCase(cfg, mockRepo, mockRedisRepo, loadKeys(t, cfg))
+
+	hashedPassword, err := argon2id.CreateHash("password", argon2id.DefaultParams)
+	assert.NoError(t, err)
+	user := &models.User{ID: 1, Username: "user", PasswordHash: hashedPassword}
+
+	notLocked := func() {
+		mockRedisRepo.EXPECT().GetLockout(gomock.Any(), "login:user:user:lockout").Return(time.Duration(0), nil)
+		mockRedisRepo.EXPECT().GetLockout(gomock.Any(), "login:ip:10.0.0.1:lockout").Return(time.Duration(0), nil)
+	}
+
+	tests := []struct {
+		name          string
+		password      string
+		mockSetup     func()
+		expectedError error
+	}{
+		{
+			name:     "username locked",
+			password: "password",
+			mockSetup: func() {
+				mockRedisRepo.EXPECT().GetLockout(gomock.Any(), "login:user:user:lockout").Return(time.Second*30, nil)
+			},
+			expectedError: &LoginLockedError{RetryAfter: time.Second * 30},
+		},
+		{
+			name:     "ip locked",
+			password: "password",
+			mockSetup: func() {
+				mockRedisRepo.EXPECT().GetLockout(gomock.Any(), "login:user:user:lockout").Return(time.Duration(0), nil)
+				mockRedisRepo.EXPECT().GetLockout(gomock.Any(), "login:ip:10.0.0.1:lockout").Return(time.Minute, nil)
+			},
+			expectedError: &LoginLockedError{RetryAfter: time.Minute},
+		},
+		{
+			name:     "failed attempt below limit",
+			password: "wrong_password",
+			mockSetup: func() {
+				notLocked()
+				mockRepo.EXPECT().GetUserByUsername(gomock.Any(), "user").Return(user, nil)
+				mockRedisRepo.EXPECT().IncrFailedAttempts(gomock.Any(), "login:user:user:attempts", time.Minute*5).Return(int64(1), nil)
+				mockRedisRepo.EXPECT().IncrFailedAttempts(gomock.Any(), "login:ip:10.0.0.1:attempts", time.Minute*5).Return(int64(1), nil)
+			},
+			expectedError: ErrIncorrectPassword,
+		},
+		{
+			name:     "failed attempt over limit doubles lockout",
+			password: "wrong_password",
+			mockSetup: func() {
+				notLocked()
+				mockRepo.EXPECT().GetUserByUsername(gomock.Any(), "user").Return(user, nil)
+				mockRedisRepo.EXPECT().IncrFailedAttempts(gomock.Any(), "login:user:user:attempts", time.Minute*5).Return(int64(4), nil)
+				mockRedisRepo.EXPECT().SetLockout(gomock.Any(), "login:user:user:lockout", time.Minute*2).Return(nil)
+				mockRedisRepo.EXPECT().IncrFailedAttempts(gomock.Any(), "login:ip:10.0.0.1:attempts", time.Minute*5).Return(int64(4), nil)
+			},
+			expectedError: ErrIncorrectPassword,
+		},
+		{
+			name:     "lockout capped",
+			password: "wrong_password",
+			mockSetup: func() {
+				notLocked()
+				mockRepo.EXPECT().GetUserByUsername(gomock.Any(), "user").Return(user, nil)
+				mockRedisRepo.EXPECT().IncrFailedAttempts(gomock.Any(), "login:user:user:attempts", time.Minute*5).Return(int64(100), nil)
+				mockRedisRepo.EXPECT().SetLockout(gomock.Any(), "login:user:user:lockout", time.Minute*5).Return(nil)
+				mockRedisRepo.EXPECT().IncrFailedAttempts(gomock.Any(), "login:ip:10.0.0.1:attempts", time.Minute*5).Return(int64(10), nil)
+				mockRedisRepo.EXPECT().SetLockout(gomock.Any(), "login:ip:10.0.0.1:lockout", time.Minute).Return(nil)
+			},
+			expectedError: ErrIncorrectPassword,
+		},
+		{
+			name:     "unknown user counts as failure",
+			password: "password",
+			mockSetup: func() {
+				notLocked()
+				mockRepo.EXPECT().GetUserByUsername(gomock.Any(), "user").Return(nil, db.ErrUserNotFound)
+				mockRedisRepo.EXPECT().IncrFailedAttempts(gomock.Any(), "login:user:user:attempts", time.Minute*5).Return(int64(1), nil)
+				mockRedisRepo.EXPECT().IncrFailedAttempts(gomock.Any(), "login:ip:10.0.0.1:attempts", time.Minute*5).Return(int64(1), nil)
+			},
+			expectedError: db.ErrUserNotFound,
+		},
+		{
+			name:     "success resets username attempts only",
+			password: "password",
+			mockSetup: func() {
+				notLocked()
+				mockRepo.EXPECT().GetUserByUsername(gomock.Any(), "user").Return(user, nil)
+				mockRedisRepo.EXPECT().ResetFailedAttempts(gomock.Any(), "login:user:user:attempts").Return(nil)
+				mockRepo.EXPECT().CreateSession(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+			},
+			expectedError: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+			_, err := authUC.Login(context.Background(), "user", tt.password, models.ClientInfo{IP: "10.0.0.1"})
+
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
+
+func TestDummyPasswordHash(t *testing.T) {
+	params, _, _, err := argon2id.DecodeHash(dummyPasswordHash)
+	assert.NoError(t, err)
+	assert.Equal(t, argon2id.DefaultParams, params)
+}
+
+func writeKeyPair(t *testing.T, key crypto.Signer) (string, string) {
+	dir := t.TempDir()
+
+	privateDER, err := x509.MarshalPKCS8PrivateKey(key)
+	assert.NoError(t, err)
+	publicDER, err := x509.MarshalPKIXPublicKey(key.Public())
+	assert.NoError(t, err)
+
+	privateFile := filepath.Join(dir, "private.pem")
+	publicFile := filepath.Join(dir, "public.pem")
+	assert.NoError(t, os.WriteFile(privateFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER}), 0o600))
+	assert.NoError(t, os.WriteFile(publicFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}), 0o600))
+
+	return privateFile, publicFile
+}
+
+func TestAuthUC_ValidateToken_KeyRotation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRedisRepo := mock_auth.NewMockRedisRepository(ctrl)
+	mockRedisRepo.EXPECT().IsTokenRevoked(gomock.Any(), gomock.Any()).Return(false, nil).AnyTimes()
+	mockRedisRepo.EXPECT().GetRevokedBefore(gomock.Any(), gomock.Any()).Return(int64(0), nil).AnyTimes()
+
+	oldRSA, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	newRSA, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	oldPrivate, oldPublic := writeKeyPair(t, oldRSA)
+	newPrivate, _ := writeKeyPair(t, newRSA)
+	edPrivate, _ := writeKeyPair(t, edKey)
+
+	newUseCase := func(app config.App) auth.UseCase {
+		app.JWTTokenTTL = time.Minute * 15
+		cfg := &config.Config{App: app}
+		return NewAuthUseCase(cfg, nil, mockRedisRepo, loadKeys(t, cfg))
+	}
+
+	user := &models.User{ID: 1, Role: models.RoleUser}
+
+	oldUC := newUseCase(config.App{JWTAlgorithm: authjwt.AlgorithmRS256, JWTPrivateKeyFile: oldPrivate})
+	oldToken := generateSessionJWT(t, oldUC, user, "session")
+
+	hmacUC := newUseCase(config.App{JWTAlgorithm: authjwt.AlgorithmHS256, JWTSecretKey: "secret"})
+	hmacToken := generateSessionJWT(t, hmacUC, user, "session")
+
+	rotatedUC := newUseCase(config.App{
+		JWTAlgorithm:      authjwt.AlgorithmRS256,
+		JWTPrivateKeyFile: newPrivate,
+		JWTPublicKeyFiles: []string{oldPublic},
+	})
+	newOnlyUC := newUseCase(config.App{JWTAlgorithm: authjwt.AlgorithmRS256, JWTPrivateKeyFile: newPrivate})
+	edUC := newUseCase(config.App{JWTAlgorithm: authjwt.AlgorithmEdDSA, JWTPrivateKeyFile: edPrivate})
+
+	newToken := generateSessionJWT(t, rotatedUC, user, "session")
+	edToken := generateSessionJWT(t, edUC, user, "session")
+
+	tests := []struct {
+		name          string
+		authUC        auth.UseCase
+		token         string
+		expectedError error
+	}{
+		{name: "old key still accepted", authUC: rotatedUC, token: oldToken, expectedError: nil},
+		{name: "new key", authUC: rotatedUC, token: newToken, expectedError: nil},
+		{name: "new key verified by new only", authUC: newOnlyUC, token: newToken, expectedError: nil},
+		{name: "retired key", authUC: newOnlyUC, token: oldToken, expectedError: pkgauth.ErrInvalidToken},
+		{name: "hmac token rejected", authUC: rotatedUC, token: hmacToken, expectedError: pkgauth.ErrInvalidToken},
+		{name: "eddsa", authUC: edUC, token: edToken, expectedError: nil},
+		{name: "rsa token rejected by eddsa", authUC: edUC, token: newToken, expectedError: pkgauth.ErrInvalidToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := tt.authUC.ValidateToken(context.Background(), tt.token)
+
+			assert.Equal(t, tt.expectedError, err)
+
+			if tt.expectedError == nil {
+				assert.Equal(t, int64(1), claims.UserID)
+			}
+		})
+	}
+
+	assert.Len(t, rotatedUC.JWKS().Keys, 2)
+	assert.Len(t, hmacUC.JWKS().Keys, 0)
+
+	edKeys := edUC.JWKS().Keys
+	assert.Len(t, edKeys, 1)
+	assert.Equal(t, "OKP", edKeys[0].Kty)
+	assert.Equal(t, "EdDSA", edKeys[0].Alg)
 }
@@ -0,0 +1,11 @@
+package cart
+
+import "github.com/labstack/echo/v4"
+
+// Cart handlers interface
+type Handlers interface {
+	GetCart(c echo.Context) error
+	AddItem(c echo.Context) error
+	RemoveItem(c echo.Context) error
+	Checkout(c echo.Context) error
+}
@@ -0,0 +1,145 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"cyansnbrst/merch-service/internal/cart"
+	"cyansnbrst/merch-service/internal/cart/usecase"
+	"cyansnbrst/merch-service/internal/middleware"
+	m "cyansnbrst/merch-service/internal/models"
+	"cyansnbrst/merch-service/pkg/db"
+	hh "cyansnbrst/merch-service/pkg/http_helpers"
+)
+
+// Cart handlers struct
+type cartHandlers struct {
+	cartUC cart.UseCase
+	logger *zap.Logger
+}
+
+// Cart handlers constructor
+func NewCartHandlers(cartUC cart.UseCase, logger *zap.Logger) cart.Handlers {
+	return &cartHandlers{
+		cartUC: cartUC,
+		logger: logger,
+	}
+}
+
+// @Summary		Get cart
+// @Description	Get items in user's cart.
+// @Tags		cart
+// @Produce		json
+// @Success		200	{object}	models.Cart					"successful"
+// @Failure		401	{object}	httphelpers.ErrorResponse	"authentication required"
+// @Failure		500	{object}	httphelpers.ErrorResponse	"internal server error"
+// @Security 	JWT
+// @Router		/cart [get]
+func (h *cartHandlers) GetCart(c echo.Context) error {
+	userID, err := middleware.ContextGetUserID(c)
+	if err != nil {
+		return hh.ServerErrorResponse(c, h.logger, err)
+	}
+
+	userCart, err := h.cartUC.GetCart(c.Request().Context(), userID)
+	if err != nil {
+		return hh.ServerErrorResponse(c, h.logger, err)
+	}
+
+	return c.JSON(http.StatusOK, userCart)
+}
+
+// @Summary		Add item to cart
+// @Description	Put an item on sale into user's cart, replacing its quantity if it's already there.
+// @Tags		cart
+// @Accept		json
+// @Produce		json
+// @Param input body models.CartItem true "input"
+// @Success		200
+// @Failure		400	{object}	httphelpers.ErrorResponse	"bad request"
+// @Failure		401	{object}	httphelpers.ErrorResponse	"authentication required"
+// @Failure		500	{object}	httphelpers.ErrorResponse	"internal server error"
+// @Security 	JWT
+// @Router		/cart [post]
+func (h *cartHandlers) AddItem(c echo.Context) error {
+	userID, err := middleware.ContextGetUserID(c)
+	if err != nil {
+		return hh.ServerErrorResponse(c, h.logger, err)
+	}
+
+	var input m.CartItem
+	if err := c.Bind(&input); err != nil {
+		return hh.BadRequestResponse(c, err)
+	}
+
+	if err := c.Validate(input); err != nil {
+		return hh.BadRequestResponse(c, err)
+	}
+
+	err = h.cartUC.AddItem(c.Request().Context(), userID, input.Item, input.Quantity)
+	if err != nil {
+		if errors.Is(err, db.ErrItemtNotFound) {
+			return hh.BadRequestResponse(c, err)
+		}
+		return hh.ServerErrorResponse(c, h.logger, err)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+// @Summary		Remove item from cart
+// @Description	Remove an item from user's cart.
+// @Tags		cart
+// @Produce		json
+// @Param   	item  path  string  true  "name of the item to remove"
+// @Success		200
+// @Failure		401	{object}	httphelpers.ErrorResponse	"authentication required"
+// @Failure		500	{object}	httphelpers.ErrorResponse	"internal server error"
+// @Security 	JWT
+// @Router		/cart/{item} [delete]
+func (h *cartHandlers) RemoveItem(c echo.Context) error {
+	userID, err := middleware.ContextGetUserID(c)
+	if err != nil {
+		return hh.ServerErrorResponse(c, h.logger, err)
+	}
+
+	item := c.Param("item")
+
+	err = h.cartUC.RemoveItem(c.Request().Context(), userID, item)
+	if err != nil {
+		return hh.ServerErrorResponse(c, h.logger, err)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+// @Summary		Checkout cart
+// @Description	Buy all items in user's cart at once. Nothing is bought if any item is unavailable or funds are short.
+// @Tags		cart
+// @Produce		json
+// @Success		200
+// @Failure		400	{object}	httphelpers.ErrorResponse	"bad request"
+// @Failure		401	{object}	httphelpers.ErrorResponse	"authentication required"
+// @Failure		500	{object}	httphelpers.ErrorResponse	"internal server error"
+// @Security 	JWT
+// @Router		/cart/checkout [post]
+func (h *cartHandlers) Checkout(c echo.Context) error {
+	userID, err := middleware.ContextGetUserID(c)
+	if err != nil {
+		return hh.ServerErrorResponse(c, h.logger, err)
+	}
+
+	err = h.cartUC.Checkout(c.Request().Context(), userID)
+	if err != nil {
+		if errors.Is(err, usecase.ErrEmptyCart) || errors.Is(err, db.ErrItemtNotFound) ||
+			errors.Is(err, db.ErrInsufficientFunds) || errors.Is(err, db.ErrOutOfStock) {
+			return hh.BadRequestResponse(c, err)
+		}
+		return hh.ServerErrorResponse(c, h.logger, err)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
@@ -0,0 +1,15 @@
+package http
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"cyansnbrst/merch-service/internal/cart"
+)
+
+// Register cart routes
+func RegisterCartRoutes(g *echo.Group, h cart.Handlers) {
+	g.GET("/cart", h.GetCart)
+	g.POST("/cart", h.AddItem)
+	g.DELETE("/cart/:item", h.RemoveItem)
+	g.POST("/cart/checkout", h.Checkout)
+}
@@ -0,0 +1,15 @@
+package cart
+
+import (
+	"context"
+
+	m "cyansnbrst/merch-service/internal/models"
+)
+
+// Cart Redis repository interface
+type RedisRepository interface {
+	GetCart(ctx context.Context, key string) (*m.Cart, error)
+	SetItem(ctx context.Context, key string, item string, quantity int64) error
+	RemoveItem(ctx context.Context, key string, item string) error
+	DeleteCart(ctx context.Context, key string) error
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"strconv"
+
+	"github.com/go-redis/redis/v8"
+
+	"cyansnbrst/merch-service/config"
+	"cyansnbrst/merch-service/internal/cart"
+	m "cyansnbrst/merch-service/internal/models"
+)
+
+// Cart redis repository
+type cartRedisRepo struct {
+	cfg         *config.Config
+	redisClient *redis.Client
+}
+
+// Cart redis repository constructor
+func NewCartRedisRepo(cfg *config.Config, redisClient *redis.Client) cart.RedisRepository {
+	return &cartRedisRepo{
+		cfg:         cfg,
+		redisClient: redisClient,
+	}
+}
+
+// Get user's cart
+func (r *cartRedisRepo) GetCart(ctx context.Context, key string) (*m.Cart, error) {
+	lines, err := r.redisClient.HGetAll(ctx, key).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]m.CartItem, 0, len(lines))
+	for item, value := range lines {
+		quantity, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, m.CartItem{
+			Item:     item,
+			Quantity: quantity,
+		})
+	}
+
+	sort.Slice(items, func(i, j int) bool { return items[i].Item < items[j].Item })
+
+	return &m.Cart{Items: items}, nil
+}
+
+// Set quantity of the item in user's cart
+func (r *cartRedisRepo) SetItem(ctx context.Context, key string, item string, quantity int64) error {
+	pipe := r.redisClient.TxPipeline()
+	pipe.HSet(ctx, key, item, quantity)
+	pipe.Expire(ctx, key, r.cfg.Redis.CartTTL)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Remove the item from user's cart
+func (r *cartRedisRepo) RemoveItem(ctx context.Context, key string, item string) error {
+	if err := r.redisClient.HDel(ctx, key, item).Err(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Delete user's cart
+func (r *cartRedisRepo) DeleteCart(ctx context.Context, key string) error {
+	if err := r.redisClient.Del(ctx, key).Err(); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,15 @@
+package cart
+
+import (
+	"context"
+
+	m "cyansnbrst/merch-service/internal/models"
+)
+
+// Cart usecase interface
+type UseCase interface {
+	GetCart(ctx context.Context, userID int64) (*m.Cart, error)
+	AddItem(ctx context.Context, userID int64, item string, quantity int64) error
+	RemoveItem(ctx context.Context, userID int64, item string) error
+	Checkout(ctx context.Context, userID int64) error
+}
@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"cyansnbrst/merch-service/internal/cart"
+	"cyansnbrst/merch-service/internal/catalog"
+	"cyansnbrst/merch-service/internal/merch"
+	m "cyansnbrst/merch-service/internal/models"
+	"cyansnbrst/merch-service/pkg/db/redis"
+)
+
+var ErrEmptyCart = errors.New("cart is empty")
+
+// Cart usecase struct
+type cartUC struct {
+	cartRedisRepo cart.RedisRepository
+	catalogUC     catalog.UseCase
+	merchUC       merch.UseCase
+}
+
+// Cart usecase constructor
+func NewCartUseCase(cartRedisRepo cart.RedisRepository, catalogUC catalog.UseCase, merchUC merch.UseCase) cart.UseCase {
+	return &cartUC{
+		cartRedisRepo: cartRedisRepo,
+		catalogUC:     catalogUC,
+		merchUC:       merchUC,
+	}
+}
+
+// Get user's cart
+func (u *cartUC) GetCart(ctx context.Context, userID int64) (*m.Cart, error) {
+	return u.cartRedisRepo.GetCart(ctx, redis.GetUserCartKey(userID))
+}
+
+// Put the item on sale into user's cart
+func (u *cartUC) AddItem(ctx context.Context, userID int64, item string, quantity int64) error {
+	if _, err := u.catalogUC.GetItem(ctx, item); err != nil {
+		return err
+	}
+
+	return u.cartRedisRepo.SetItem(ctx, redis.GetUserCartKey(userID), item, quantity)
+}
+
+// Remove the item from user's cart
+func (u *cartUC) RemoveItem(ctx context.Context, userID int64, item string) error {
+	return u.cartRedisRepo.RemoveItem(ctx, redis.GetUserCartKey(userID), item)
+}
+
+// Buy everything in user's cart
+func (u *cartUC) Checkout(ctx context.Context, userID int64) error {
+	key := redis.GetUserCartKey(userID)
+
+	userCart, err := u.cartRedisRepo.GetCart(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	if len(userCart.Items) == 0 {
+		return ErrEmptyCart
+	}
+
+	if err := u.merchUC.BuyItems(ctx, userID, userCart.Items); err != nil {
+		return err
+	}
+
+	return u.cartRedisRepo.DeleteCart(ctx, key)
+}
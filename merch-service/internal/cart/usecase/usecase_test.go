@@ -0,0 +1,270 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	mock_cart "cyansnbrst/merch-service/internal/cart/mock"
+	"cyansnbrst/merch-service/internal/cart/usecase"
+	mock_catalog "cyansnbrst/merch-service/internal/catalog/mock"
+	mock_merch "cyansnbrst/merch-service/internal/merch/mock"
+	m "cyansnbrst/merch-service/internal/models"
+	"cyansnbrst/merch-service/pkg/db"
+	"cyansnbrst/merch-service/pkg/db/redis"
+)
+
+var ErrRandomDBError = errors.New("db error")
+
+func TestCartUC_GetCart(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRedisRepo := mock_cart.NewMockRedisRepository(ctrl)
+	mockCatalogUC := mock_catalog.NewMockUseCase(ctrl)
+	mockMerchUC := mock_merch.NewMockUseCase(ctrl)
+
+	cartUC := usecase.NewCartUseCase(mockRedisRepo, mockCatalogUC, mockMerchUC)
+
+	tests := []struct {
+		name          string
+		userID        int64
+		mockSetup     func()
+		expectedResp  *m.Cart
+		expectedError error
+	}{
+		{
+			name:   "success",
+			userID: 1,
+			mockSetup: func() {
+				mockRedisRepo.EXPECT().GetCart(gomock.Any(), redis.GetUserCartKey(int64(1))).Return(&m.Cart{
+					Items: []m.CartItem{
+						{Item: "cup", Quantity: 1},
+						{Item: "t-shirt", Quantity: 2},
+					},
+				}, nil)
+			},
+			expectedResp: &m.Cart{
+				Items: []m.CartItem{
+					{Item: "cup", Quantity: 1},
+					{Item: "t-shirt", Quantity: 2},
+				},
+			},
+			expectedError: nil,
+		},
+		{
+			name:   "error redis error",
+			userID: 2,
+			mockSetup: func() {
+				mockRedisRepo.EXPECT().GetCart(gomock.Any(), redis.GetUserCartKey(int64(2))).Return(nil, ErrRandomDBError)
+			},
+			expectedResp:  nil,
+			expectedError: ErrRandomDBError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			resp, err := cartUC.GetCart(context.Background(), tt.userID)
+
+			assert.Equal(t, tt.expectedResp, resp)
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
+
+func TestCartUC_AddItem(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRedisRepo := mock_cart.NewMockRedisRepository(ctrl)
+	mockCatalogUC := mock_catalog.NewMockUseCase(ctrl)
+	mockMerchUC := mock_merch.NewMockUseCase(ctrl)
+
+	cartUC := usecase.NewCartUseCase(mockRedisRepo, mockCatalogUC, mockMerchUC)
+
+	tests := []struct {
+		name          string
+		userID        int64
+		item          string
+		quantity      int64
+		mockSetup     func()
+		expectedError error
+	}{
+		{
+			name:     "success",
+			userID:   1,
+			item:     "socks",
+			quantity: 3,
+			mockSetup: func() {
+				mockCatalogUC.EXPECT().GetItem(gomock.Any(), "socks").Return(&m.CatalogItem{Name: "socks", Price: 10}, nil)
+				mockRedisRepo.EXPECT().SetItem(gomock.Any(), redis.GetUserCartKey(int64(1)), "socks", int64(3)).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:     "error item not on sale",
+			userID:   1,
+			item:     "rocket",
+			quantity: 1,
+			mockSetup: func() {
+				mockCatalogUC.EXPECT().GetItem(gomock.Any(), "rocket").Return(nil, db.ErrItemtNotFound)
+			},
+			expectedError: db.ErrItemtNotFound,
+		},
+		{
+			name:     "error redis error",
+			userID:   1,
+			item:     "socks",
+			quantity: 3,
+			mockSetup: func() {
+				mockCatalogUC.EXPECT().GetItem(gomock.Any(), "socks").Return(&m.CatalogItem{Name: "socks", Price: 10}, nil)
+				mockRedisRepo.EXPECT().SetItem(gomock.Any(), redis.GetUserCartKey(int64(1)), "socks", int64(3)).Return(ErrRandomDBError)
+			},
+			expectedError: ErrRandomDBError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			err := cartUC.AddItem(context.Background(), tt.userID, tt.item, tt.quantity)
+
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
+
+func TestCartUC_RemoveItem(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRedisRepo := mock_cart.NewMockRedisRepository(ctrl)
+	mockCatalogUC := mock_catalog.NewMockUseCase(ctrl)
+	mockMerchUC := mock_merch.NewMockUseCase(ctrl)
+
+	cartUC := usecase.NewCartUseCase(mockRedisRepo, mockCatalogUC, mockMerchUC)
+
+	tests := []struct {
+		name          string
+		userID        int64
+		item          string
+		mockSetup     func()
+		expectedError error
+	}{
+		{
+			name:   "success",
+			userID: 1,
+			item:   "socks",
+			mockSetup: func() {
+				mockRedisRepo.EXPECT().RemoveItem(gomock.Any(), redis.GetUserCartKey(int64(1)), "socks").Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:   "error redis error",
+			userID: 1,
+			item:   "socks",
+			mockSetup: func() {
+				mockRedisRepo.EXPECT().RemoveItem(gomock.Any(), redis.GetUserCartKey(int64(1)), "socks").Return(ErrRandomDBError)
+			},
+			expectedError: ErrRandomDBError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			err := cartUC.RemoveItem(context.Background(), tt.userID, tt.item)
+
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
+
+func TestCartUC_Checkout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRedisRepo := mock_cart.NewMockRedisRepository(ctrl)
+	mockCatalogUC := mock_catalog.NewMockUseCase(ctrl)
+	mockMerchUC := mock_merch.NewMockUseCase(ctrl)
+
+	cartUC := usecase.NewCartUseCase(mockRedisRepo, mockCatalogUC, mockMerchUC)
+
+	items := []m.CartItem{
+		{Item: "cup", Quantity: 1},
+		{Item: "socks", Quantity: 2},
+		{Item: "t-shirt", Quantity: 1},
+	}
+
+	tests := []struct {
+		name          string
+		userID        int64
+		mockSetup     func()
+		expectedError error
+	}{
+		{
+			name:   "success",
+			userID: 1,
+			mockSetup: func() {
+				mockRedisRepo.EXPECT().GetCart(gomock.Any(), redis.GetUserCartKey(int64(1))).Return(&m.Cart{Items: items}, nil)
+				mockMerchUC.EXPECT().BuyItems(gomock.Any(), int64(1), items).Return(nil)
+				mockRedisRepo.EXPECT().DeleteCart(gomock.Any(), redis.GetUserCartKey(int64(1))).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:   "error empty cart",
+			userID: 2,
+			mockSetup: func() {
+				mockRedisRepo.EXPECT().GetCart(gomock.Any(), redis.GetUserCartKey(int64(2))).Return(&m.Cart{Items: []m.CartItem{}}, nil)
+			},
+			expectedError: usecase.ErrEmptyCart,
+		},
+		{
+			name:   "error insufficient funds",
+			userID: 3,
+			mockSetup: func() {
+				mockRedisRepo.EXPECT().GetCart(gomock.Any(), redis.GetUserCartKey(int64(3))).Return(&m.Cart{Items: items}, nil)
+				mockMerchUC.EXPECT().BuyItems(gomock.Any(), int64(3), items).Return(db.ErrInsufficientFunds)
+			},
+			expectedError: db.ErrInsufficientFunds,
+		},
+		{
+			name:   "error get cart",
+			userID: 4,
+			mockSetup: func() {
+				mockRedisRepo.EXPECT().GetCart(gomock.Any(), redis.GetUserCartKey(int64(4))).Return(nil, ErrRandomDBError)
+			},
+			expectedError: ErrRandomDBError,
+		},
+		{
+			name:   "error delete cart",
+			userID: 5,
+			mockSetup: func() {
+				mockRedisRepo.EXPECT().GetCart(gomock.Any(), redis.GetUserCartKey(int64(5))).Return(&m.Cart{Items: items}, nil)
+				mockMerchUC.EXPECT().BuyItems(gomock.Any(), int64(5), items).Return(nil)
+				mockRedisRepo.EXPECT().DeleteCart(gomock.Any(), redis.GetUserCartKey(int64(5))).Return(ErrRandomDBError)
+			},
+			expectedError: ErrRandomDBError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			err := cartUC.Checkout(context.Background(), tt.userID)
+
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
@@ -0,0 +1,14 @@
+package catalog
+
+import "github.com/labstack/echo/v4"
+
+// Catalog handlers interface
+type Handlers interface {
+	CreateItem(c echo.Context) error
+	ListItems(c echo.Context) error
+	UpdateItemPrice(c echo.Context) error
+	UpdateItemStock(c echo.Context) error
+	RenameItem(c echo.Context) error
+	RetireItem(c echo.Context) error
+	GetItems(c echo.Context) error
+}
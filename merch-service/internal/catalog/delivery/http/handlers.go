@@ -0,0 +1,275 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"cyansnbrst/merch-service/internal/catalog"
+	"cyansnbrst/merch-service/internal/middleware"
+	m "cyansnbrst/merch-service/internal/models"
+	"cyansnbrst/merch-service/pkg/db"
+	hh "cyansnbrst/merch-service/pkg/http_helpers"
+)
+
+// Catalog handlers struct
+type catalogHandlers struct {
+	catalogUC catalog.UseCase
+	logger    *zap.Logger
+}
+
+// Catalog handlers constructor
+func NewCatalogHandlers(catalogUC catalog.UseCase, logger *zap.Logger) catalog.Handlers {
+	return &catalogHandlers{
+		catalogUC: catalogUC,
+		logger:    logger,
+	}
+}
+
+// @Summary		Create item
+// @Description	Add a new item to the store catalog.
+// @Tags		admin
+// @Accept		json
+// @Produce		json
+// @Param input body models.CreateProductRequest true "input"
+// @Success		201	{object}	models.Product				"created"
+// @Failure		400	{object}	httphelpers.ErrorResponse	"bad request"
+// @Failure		401	{object}	httphelpers.ErrorResponse	"authentication required"
+// @Failure		403	{object}	httphelpers.ErrorResponse	"not permitted"
+// @Failure		409	{object}	httphelpers.ErrorResponse	"item already exists"
+// @Failure		500	{object}	httphelpers.ErrorResponse	"internal server error"
+// @Security 	JWT
+// @Router		/admin/items [post]
+func (h *catalogHandlers) CreateItem(c echo.Context) error {
+	var input m.CreateProductRequest
+	if err := c.Bind(&input); err != nil {
+		return hh.BadRequestResponse(c, err)
+	}
+
+	if err := c.Validate(input); err != nil {
+		return hh.BadRequestResponse(c, err)
+	}
+
+	product, err := h.catalogUC.CreateItem(c.Request().Context(), input.Name, input.Price, input.Stock)
+	if err != nil {
+		if errors.Is(err, db.ErrItemAlreadyExists) {
+			return hh.ConflictResponse(c, err)
+		}
+		return hh.ServerErrorResponse(c, h.logger, err)
+	}
+
+	return c.JSON(http.StatusCreated, product)
+}
+
+// @Summary		List items
+// @Description	Get all catalog items including retired ones.
+// @Tags		admin
+// @Produce		json
+// @Success		200	{array}		models.Product				"successful"
+// @Failure		401	{object}	httphelpers.ErrorResponse	"authentication required"
+// @Failure		403	{object}	httphelpers.ErrorResponse	"not permitted"
+// @Failure		500	{object}	httphelpers.ErrorResponse	"internal server error"
+// @Security 	JWT
+// @Router		/admin/items [get]
+func (h *catalogHandlers) ListItems(c echo.Context) error {
+	products, err := h.catalogUC.ListItems(c.Request().Context())
+	if err != nil {
+		return hh.ServerErrorResponse(c, h.logger, err)
+	}
+
+	return c.JSON(http.StatusOK, products)
+}
+
+// @Summary		Update item price
+// @Description	Change the price of a catalog item.
+// @Tags		admin
+// @Accept		json
+// @Produce		json
+// @Param		id		path	int							true	"item id"
+// @Param		input	body	models.UpdatePriceRequest	true	"input"
+// @Success		200	{object}	models.Product				"successful"
+// @Failure		400	{object}	httphelpers.ErrorResponse	"bad request"
+// @Failure		401	{object}	httphelpers.ErrorResponse	"authentication required"
+// @Failure		403	{object}	httphelpers.ErrorResponse	"not permitted"
+// @Failure		404	{object}	httphelpers.ErrorResponse	"item not found"
+// @Failure		500	{object}	httphelpers.ErrorResponse	"internal server error"
+// @Security 	JWT
+// @Router		/admin/items/{id}/price [patch]
+func (h *catalogHandlers) UpdateItemPrice(c echo.Context) error {
+	id, err := hh.ReadIDParam(c)
+	if err != nil {
+		return hh.BadRequestResponse(c, err)
+	}
+
+	var input m.UpdatePriceRequest
+	if err := c.Bind(&input); err != nil {
+		return hh.BadRequestResponse(c, err)
+	}
+
+	if err := c.Validate(input); err != nil {
+		return hh.BadRequestResponse(c, err)
+	}
+
+	product, err := h.catalogUC.UpdateItemPrice(c.Request().Context(), id, input.Price)
+	if err != nil {
+		if errors.Is(err, db.ErrItemtNotFound) {
+			return hh.NotFoundResponse(c, err)
+		}
+		return hh.ServerErrorResponse(c, h.logger, err)
+	}
+
+	return c.JSON(http.StatusOK, product)
+}
+
+// @Summary		Update item stock
+// @Description	Set the number of units left for sale. Null stock makes the item unlimited.
+// @Tags		admin
+// @Accept		json
+// @Produce		json
+// @Param		id		path	int							true	"item id"
+// @Param		input	body	models.UpdateStockRequest	true	"input"
+// @Success		200	{object}	models.Product				"successful"
+// @Failure		400	{object}	httphelpers.ErrorResponse	"bad request"
+// @Failure		401	{object}	httphelpers.ErrorResponse	"authentication required"
+// @Failure		403	{object}	httphelpers.ErrorResponse	"not permitted"
+// @Failure		404	{object}	httphelpers.ErrorResponse	"item not found"
+// @Failure		500	{object}	httphelpers.ErrorResponse	"internal server error"
+// @Security 	JWT
+// @Router		/admin/items/{id}/stock [patch]
+func (h *catalogHandlers) UpdateItemStock(c echo.Context) error {
+	id, err := hh.ReadIDParam(c)
+	if err != nil {
+		return hh.BadRequestResponse(c, err)
+	}
+
+	var input m.UpdateStockRequest
+	if err := c.Bind(&input); err != nil {
+		return hh.BadRequestResponse(c, err)
+	}
+
+	if err := c.Validate(input); err != nil {
+		return hh.BadRequestResponse(c, err)
+	}
+
+	product, err := h.catalogUC.UpdateItemStock(c.Request().Context(), id, input.Stock)
+	if err != nil {
+		if errors.Is(err, db.ErrItemtNotFound) {
+			return hh.NotFoundResponse(c, err)
+		}
+		return hh.ServerErrorResponse(c, h.logger, err)
+	}
+
+	return c.JSON(http.StatusOK, product)
+}
+
+// @Summary		Rename item
+// @Description	Change the name of a catalog item.
+// @Tags		admin
+// @Accept		json
+// @Produce		json
+// @Param		id		path	int							true	"item id"
+// @Param		input	body	models.RenameProductRequest	true	"input"
+// @Success		200	{object}	models.Product				"successful"
+// @Failure		400	{object}	httphelpers.ErrorResponse	"bad request"
+// @Failure		401	{object}	httphelpers.ErrorResponse	"authentication required"
+// @Failure		403	{object}	httphelpers.ErrorResponse	"not permitted"
+// @Failure		404	{object}	httphelpers.ErrorResponse	"item not found"
+// @Failure		409	{object}	httphelpers.ErrorResponse	"item already exists"
+// @Failure		500	{object}	httphelpers.ErrorResponse	"internal server error"
+// @Security 	JWT
+// @Router		/admin/items/{id}/name [patch]
+func (h *catalogHandlers) RenameItem(c echo.Context) error {
+	id, err := hh.ReadIDParam(c)
+	if err != nil {
+		return hh.BadRequestResponse(c, err)
+	}
+
+	var input m.RenameProductRequest
+	if err := c.Bind(&input); err != nil {
+		return hh.BadRequestResponse(c, err)
+	}
+
+	if err := c.Validate(input); err != nil {
+		return hh.BadRequestResponse(c, err)
+	}
+
+	product, err := h.catalogUC.RenameItem(c.Request().Context(), id, input.Name)
+	if err != nil {
+		switch {
+		case errors.Is(err, db.ErrItemtNotFound):
+			return hh.NotFoundResponse(c, err)
+		case errors.Is(err, db.ErrItemAlreadyExists):
+			return hh.ConflictResponse(c, err)
+		}
+		return hh.ServerErrorResponse(c, h.logger, err)
+	}
+
+	return c.JSON(http.StatusOK, product)
+}
+
+// @Summary		Retire item
+// @Description	Remove an item from sale. Already bought items stay in users' inventories.
+// @Tags		admin
+// @Produce		json
+// @Param		id	path	int	true	"item id"
+// @Success		200	{object}	models.Product				"successful"
+// @Failure		400	{object}	httphelpers.ErrorResponse	"bad request"
+// @Failure		401	{object}	httphelpers.ErrorResponse	"authentication required"
+// @Failure		403	{object}	httphelpers.ErrorResponse	"not permitted"
+// @Failure		404	{object}	httphelpers.ErrorResponse	"item not found"
+// @Failure		500	{object}	httphelpers.ErrorResponse	"internal server error"
+// @Security 	JWT
+// @Router		/admin/items/{id} [delete]
+func (h *catalogHandlers) RetireItem(c echo.Context) error {
+	id, err := hh.ReadIDParam(c)
+	if err != nil {
+		return hh.BadRequestResponse(c, err)
+	}
+
+	product, err := h.catalogUC.RetireItem(c.Request().Context(), id)
+	if err != nil {
+		if errors.Is(err, db.ErrItemtNotFound) {
+			return hh.NotFoundResponse(c, err)
+		}
+		return hh.ServerErrorResponse(c, h.logger, err)
+	}
+
+	return c.JSON(http.StatusOK, product)
+}
+
+// @Summary		Get catalog
+// @Description	Get items on sale with prices and availability for the current user's balance.
+// @Tags		merch
+// @Produce		json
+// @Param		sort		query	string	false	"sort by price"	Enums(price_asc, price_desc)
+// @Param		affordable	query	bool	false	"only items the user can afford"
+// @Success		200	{array}		models.CatalogItem			"successful"
+// @Failure		400	{object}	httphelpers.ErrorResponse	"bad request"
+// @Failure		401	{object}	httphelpers.ErrorResponse	"authentication required"
+// @Failure		500	{object}	httphelpers.ErrorResponse	"internal server error"
+// @Security 	JWT
+// @Router		/items [get]
+func (h *catalogHandlers) GetItems(c echo.Context) error {
+	userID, err := middleware.ContextGetUserID(c)
+	if err != nil {
+		return hh.ServerErrorResponse(c, h.logger, err)
+	}
+
+	var filter m.CatalogFilter
+	if err := c.Bind(&filter); err != nil {
+		return hh.BadRequestResponse(c, err)
+	}
+
+	if err := c.Validate(filter); err != nil {
+		return hh.BadRequestResponse(c, err)
+	}
+
+	items, err := h.catalogUC.GetItems(c.Request().Context(), userID, filter)
+	if err != nil {
+		return hh.ServerErrorResponse(c, h.logger, err)
+	}
+
+	return c.JSON(http.StatusOK, items)
+}
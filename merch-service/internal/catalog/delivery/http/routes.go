@@ -0,0 +1,22 @@
+package http
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"cyansnbrst/merch-service/internal/catalog"
+)
+
+// Register catalog routes
+func RegisterCatalogRoutes(g *echo.Group, h catalog.Handlers) {
+	g.GET("/items", h.GetItems)
+}
+
+// Register catalog admin routes
+func RegisterCatalogAdminRoutes(g *echo.Group, h catalog.Handlers) {
+	g.GET("/items", h.ListItems)
+	g.POST("/items", h.CreateItem)
+	g.PATCH("/items/:id/price", h.UpdateItemPrice)
+	g.PATCH("/items/:id/stock", h.UpdateItemStock)
+	g.PATCH("/items/:id/name", h.RenameItem)
+	g.DELETE("/items/:id", h.RetireItem)
+}
@@ -0,0 +1,172 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/catalog/pg_repository.go
+
+// Package mock_catalog is a generated GoMock package.
+package mock_catalog
+
+import (
+	context "context"
+	models "cyansnbrst/merch-service/internal/models"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateItem mocks base method.
+func (m *MockRepository) CreateItem(ctx context.Context, name string, price int64, stock *int64) (*models.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateItem", ctx, name, price, stock)
+	ret0, _ := ret[0].(*models.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateItem indicates an expected call of CreateItem.
+func (mr *MockRepositoryMockRecorder) CreateItem(ctx, name, price, stock interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateItem", reflect.TypeOf((*MockRepository)(nil).CreateItem), ctx, name, price, stock)
+}
+
+// GetAvailableItems mocks base method.
+func (m *MockRepository) GetAvailableItems(ctx context.Context) ([]models.CatalogItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAvailableItems", ctx)
+	ret0, _ := ret[0].([]models.CatalogItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAvailableItems indicates an expected call of GetAvailableItems.
+func (mr *MockRepositoryMockRecorder) GetAvailableItems(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAvailableItems", reflect.TypeOf((*MockRepository)(nil).GetAvailableItems), ctx)
+}
+
+// GetItemHolders mocks base method.
+func (m *MockRepository) GetItemHolders(ctx context.Context, id int64) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetItemHolders", ctx, id)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetItemHolders indicates an expected call of GetItemHolders.
+func (mr *MockRepositoryMockRecorder) GetItemHolders(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItemHolders", reflect.TypeOf((*MockRepository)(nil).GetItemHolders), ctx, id)
+}
+
+// GetUserBalance mocks base method.
+func (m *MockRepository) GetUserBalance(ctx context.Context, userID int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserBalance", ctx, userID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserBalance indicates an expected call of GetUserBalance.
+func (mr *MockRepositoryMockRecorder) GetUserBalance(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserBalance", reflect.TypeOf((*MockRepository)(nil).GetUserBalance), ctx, userID)
+}
+
+// ListItems mocks base method.
+func (m *MockRepository) ListItems(ctx context.Context) ([]models.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListItems", ctx)
+	ret0, _ := ret[0].([]models.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListItems indicates an expected call of ListItems.
+func (mr *MockRepositoryMockRecorder) ListItems(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListItems", reflect.TypeOf((*MockRepository)(nil).ListItems), ctx)
+}
+
+// RenameItem mocks base method.
+func (m *MockRepository) RenameItem(ctx context.Context, id int64, name string) (*models.Product, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameItem", ctx, id, name)
+	ret0, _ := ret[0].(*models.Product)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// RenameItem indicates an expected call of RenameItem.
+func (mr *MockRepositoryMockRecorder) RenameItem(ctx, id, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameItem", reflect.TypeOf((*MockRepository)(nil).RenameItem), ctx, id, name)
+}
+
+// RetireItem mocks base method.
+func (m *MockRepository) RetireItem(ctx context.Context, id int64) (*models.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetireItem", ctx, id)
+	ret0, _ := ret[0].(*models.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RetireItem indicates an expected call of RetireItem.
+func (mr *MockRepositoryMockRecorder) RetireItem(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetireItem", reflect.TypeOf((*MockRepository)(nil).RetireItem), ctx, id)
+}
+
+// UpdateItemPrice mocks base method.
+func (m *MockRepository) UpdateItemPrice(ctx context.Context, id, price int64) (*models.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateItemPrice", ctx, id, price)
+	ret0, _ := ret[0].(*models.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateItemPrice indicates an expected call of UpdateItemPrice.
+func (mr *MockRepositoryMockRecorder) UpdateItemPrice(ctx, id, price interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateItemPrice", reflect.TypeOf((*MockRepository)(nil).UpdateItemPrice), ctx, id, price)
+}
+
+// UpdateItemStock mocks base method.
+func (m *MockRepository) UpdateItemStock(ctx context.Context, id int64, stock *int64) (*models.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateItemStock", ctx, id, stock)
+	ret0, _ := ret[0].(*models.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateItemStock indicates an expected call of UpdateItemStock.
+func (mr *MockRepositoryMockRecorder) UpdateItemStock(ctx, id, stock interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateItemStock", reflect.TypeOf((*MockRepository)(nil).UpdateItemStock), ctx, id, stock)
+}
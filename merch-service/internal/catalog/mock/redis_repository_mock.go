@@ -0,0 +1,107 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/catalog/redis_repository.go
+
+// Package mock_catalog is a generated GoMock package.
+package mock_catalog
+
+import (
+	context "context"
+	models "cyansnbrst/merch-service/internal/models"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockRedisRepository is a mock of RedisRepository interface.
+type MockRedisRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRedisRepositoryMockRecorder
+}
+
+// MockRedisRepositoryMockRecorder is the mock recorder for MockRedisRepository.
+type MockRedisRepositoryMockRecorder struct {
+	mock *MockRedisRepository
+}
+
+// NewMockRedisRepository creates a new mock instance.
+func NewMockRedisRepository(ctrl *gomock.Controller) *MockRedisRepository {
+	mock := &MockRedisRepository{ctrl: ctrl}
+	mock.recorder = &MockRedisRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRedisRepository) EXPECT() *MockRedisRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteItems mocks base method.
+func (m *MockRedisRepository) DeleteItems(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteItems", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteItems indicates an expected call of DeleteItems.
+func (mr *MockRedisRepositoryMockRecorder) DeleteItems(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteItems", reflect.TypeOf((*MockRedisRepository)(nil).DeleteItems), ctx, key)
+}
+
+// DeleteUserInfo mocks base method.
+func (m *MockRedisRepository) DeleteUserInfo(ctx context.Context, keys []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserInfo", ctx, keys)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserInfo indicates an expected call of DeleteUserInfo.
+func (mr *MockRedisRepositoryMockRecorder) DeleteUserInfo(ctx, keys interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserInfo", reflect.TypeOf((*MockRedisRepository)(nil).DeleteUserInfo), ctx, keys)
+}
+
+// GetItems mocks base method.
+func (m *MockRedisRepository) GetItems(ctx context.Context, key string) ([]models.CatalogItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetItems", ctx, key)
+	ret0, _ := ret[0].([]models.CatalogItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetItems indicates an expected call of GetItems.
+func (mr *MockRedisRepositoryMockRecorder) GetItems(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItems", reflect.TypeOf((*MockRedisRepository)(nil).GetItems), ctx, key)
+}
+
+// RenameCartItem mocks base method.
+func (m *MockRedisRepository) RenameCartItem(ctx context.Context, pattern, oldName, newName string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameCartItem", ctx, pattern, oldName, newName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RenameCartItem indicates an expected call of RenameCartItem.
+func (mr *MockRedisRepositoryMockRecorder) RenameCartItem(ctx, pattern, oldName, newName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameCartItem", reflect.TypeOf((*MockRedisRepository)(nil).RenameCartItem), ctx, pattern, oldName, newName)
+}
+
+// SetItems mocks base method.
+func (m *MockRedisRepository) SetItems(ctx context.Context, key string, items []models.CatalogItem) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetItems", ctx, key, items)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetItems indicates an expected call of SetItems.
+func (mr *MockRedisRepositoryMockRecorder) SetItems(ctx, key, items interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetItems", reflect.TypeOf((*MockRedisRepository)(nil).SetItems), ctx, key, items)
+}
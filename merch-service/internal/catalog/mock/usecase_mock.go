@@ -0,0 +1,156 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/catalog/usecase.go
+
+// Package mock_catalog is a generated GoMock package.
+package mock_catalog
+
+import (
+	context "context"
+	models "cyansnbrst/merch-service/internal/models"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockUseCase is a mock of UseCase interface.
+type MockUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockUseCaseMockRecorder
+}
+
+// MockUseCaseMockRecorder is the mock recorder for MockUseCase.
+type MockUseCaseMockRecorder struct {
+	mock *MockUseCase
+}
+
+// NewMockUseCase creates a new mock instance.
+func NewMockUseCase(ctrl *gomock.Controller) *MockUseCase {
+	mock := &MockUseCase{ctrl: ctrl}
+	mock.recorder = &MockUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUseCase) EXPECT() *MockUseCaseMockRecorder {
+	return m.recorder
+}
+
+// CreateItem mocks base method.
+func (m *MockUseCase) CreateItem(ctx context.Context, name string, price int64, stock *int64) (*models.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateItem", ctx, name, price, stock)
+	ret0, _ := ret[0].(*models.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateItem indicates an expected call of CreateItem.
+func (mr *MockUseCaseMockRecorder) CreateItem(ctx, name, price, stock interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateItem", reflect.TypeOf((*MockUseCase)(nil).CreateItem), ctx, name, price, stock)
+}
+
+// GetItem mocks base method.
+func (m *MockUseCase) GetItem(ctx context.Context, name string) (*models.CatalogItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetItem", ctx, name)
+	ret0, _ := ret[0].(*models.CatalogItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetItem indicates an expected call of GetItem.
+func (mr *MockUseCaseMockRecorder) GetItem(ctx, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItem", reflect.TypeOf((*MockUseCase)(nil).GetItem), ctx, name)
+}
+
+// GetItems mocks base method.
+func (m *MockUseCase) GetItems(ctx context.Context, userID int64, filter models.CatalogFilter) ([]models.CatalogItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetItems", ctx, userID, filter)
+	ret0, _ := ret[0].([]models.CatalogItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetItems indicates an expected call of GetItems.
+func (mr *MockUseCaseMockRecorder) GetItems(ctx, userID, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItems", reflect.TypeOf((*MockUseCase)(nil).GetItems), ctx, userID, filter)
+}
+
+// ListItems mocks base method.
+func (m *MockUseCase) ListItems(ctx context.Context) ([]models.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListItems", ctx)
+	ret0, _ := ret[0].([]models.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListItems indicates an expected call of ListItems.
+func (mr *MockUseCaseMockRecorder) ListItems(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListItems", reflect.TypeOf((*MockUseCase)(nil).ListItems), ctx)
+}
+
+// RenameItem mocks base method.
+func (m *MockUseCase) RenameItem(ctx context.Context, id int64, name string) (*models.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameItem", ctx, id, name)
+	ret0, _ := ret[0].(*models.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RenameItem indicates an expected call of RenameItem.
+func (mr *MockUseCaseMockRecorder) RenameItem(ctx, id, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameItem", reflect.TypeOf((*MockUseCase)(nil).RenameItem), ctx, id, name)
+}
+
+// RetireItem mocks base method.
+func (m *MockUseCase) RetireItem(ctx context.Context, id int64) (*models.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetireItem", ctx, id)
+	ret0, _ := ret[0].(*models.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RetireItem indicates an expected call of RetireItem.
+func (mr *MockUseCaseMockRecorder) RetireItem(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetireItem", reflect.TypeOf((*MockUseCase)(nil).RetireItem), ctx, id)
+}
+
+// UpdateItemPrice mocks base method.
+func (m *MockUseCase) UpdateItemPrice(ctx context.Context, id, price int64) (*models.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateItemPrice", ctx, id, price)
+	ret0, _ := ret[0].(*models.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateItemPrice indicates an expected call of UpdateItemPrice.
+func (mr *MockUseCaseMockRecorder) UpdateItemPrice(ctx, id, price interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateItemPrice", reflect.TypeOf((*MockUseCase)(nil).UpdateItemPrice), ctx, id, price)
+}
+
+// UpdateItemStock mocks base method.
+func (m *MockUseCase) UpdateItemStock(ctx context.Context, id int64, stock *int64) (*models.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateItemStock", ctx, id, stock)
+	ret0, _ := ret[0].(*models.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateItemStock indicates an expected call of UpdateItemStock.
+func (mr *MockUseCaseMockRecorder) UpdateItemStock(ctx, id, stock interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateItemStock", reflect.TypeOf((*MockUseCase)(nil).UpdateItemStock), ctx, id, stock)
+}
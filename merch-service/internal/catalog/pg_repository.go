@@ -0,0 +1,20 @@
+package catalog
+
+import (
+	"context"
+
+	m "cyansnbrst/merch-service/internal/models"
+)
+
+// Catalog repository interface
+type Repository interface {
+	CreateItem(ctx context.Context, name string, price int64, stock *int64) (*m.Product, error)
+	ListItems(ctx context.Context) ([]m.Product, error)
+	UpdateItemPrice(ctx context.Context, id, price int64) (*m.Product, error)
+	UpdateItemStock(ctx context.Context, id int64, stock *int64) (*m.Product, error)
+	RenameItem(ctx context.Context, id int64, name string) (*m.Product, string, error)
+	GetItemHolders(ctx context.Context, id int64) ([]int64, error)
+	RetireItem(ctx context.Context, id int64) (*m.Product, error)
+	GetAvailableItems(ctx context.Context) ([]m.CatalogItem, error)
+	GetUserBalance(ctx context.Context, userID int64) (int64, error)
+}
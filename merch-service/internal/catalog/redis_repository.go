@@ -0,0 +1,16 @@
+package catalog
+
+import (
+	"context"
+
+	m "cyansnbrst/merch-service/internal/models"
+)
+
+// Catalog Redis repository interface
+type RedisRepository interface {
+	GetItems(ctx context.Context, key string) ([]m.CatalogItem, error)
+	SetItems(ctx context.Context, key string, items []m.CatalogItem) error
+	DeleteItems(ctx context.Context, key string) error
+	DeleteUserInfo(ctx context.Context, keys []string) error
+	RenameCartItem(ctx context.Context, pattern, oldName, newName string) error
+}
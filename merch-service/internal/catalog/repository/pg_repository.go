@@ -0,0 +1,257 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"cyansnbrst/merch-service/internal/catalog"
+	m "cyansnbrst/merch-service/internal/models"
+	"cyansnbrst/merch-service/pkg/db"
+	"cyansnbrst/merch-service/pkg/db/postgres"
+)
+
+// Catalog repository struct
+type catalogRepo struct {
+	db *pgxpool.Pool
+}
+
+// Catalog repository constructor
+func NewCatalogRepo(db *pgxpool.Pool) catalog.Repository {
+	return &catalogRepo{db: db}
+}
+
+// Create a new item
+func (r *catalogRepo) CreateItem(ctx context.Context, name string, price int64, stock *int64) (*m.Product, error) {
+	query := `
+		INSERT INTO items (name, price, stock)
+		VALUES ($1, $2, $3)
+		RETURNING id, name, price, stock, created_at, retired_at
+	`
+
+	product, err := scanProduct(r.db.QueryRow(ctx, query, name, price, stock))
+	if err != nil {
+		if postgres.IsUniqueViolation(err) {
+			return nil, db.ErrItemAlreadyExists
+		}
+		return nil, fmt.Errorf("repo - failed to create item: %w", err)
+	}
+
+	return product, nil
+}
+
+// Get all items including retired ones
+func (r *catalogRepo) ListItems(ctx context.Context) ([]m.Product, error) {
+	query := `
+		SELECT id, name, price, stock, created_at, retired_at
+		FROM items
+		ORDER BY id
+	`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("repo - failed to get items: %w", err)
+	}
+	defer rows.Close()
+
+	products := make([]m.Product, 0)
+	for rows.Next() {
+		product, err := scanProduct(rows)
+		if err != nil {
+			return nil, fmt.Errorf("repo - failed to scan item: %w", err)
+		}
+		products = append(products, *product)
+	}
+
+	return products, nil
+}
+
+// Update item's price
+func (r *catalogRepo) UpdateItemPrice(ctx context.Context, id, price int64) (*m.Product, error) {
+	query := `
+		UPDATE items
+		SET price = $1
+		WHERE id = $2
+		RETURNING id, name, price, stock, created_at, retired_at
+	`
+
+	product, err := scanProduct(r.db.QueryRow(ctx, query, price, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, db.ErrItemtNotFound
+		}
+		return nil, fmt.Errorf("repo - failed to update item price: %w", err)
+	}
+
+	return product, nil
+}
+
+// Update item's stock
+func (r *catalogRepo) UpdateItemStock(ctx context.Context, id int64, stock *int64) (*m.Product, error) {
+	query := `
+		UPDATE items
+		SET stock = $1
+		WHERE id = $2
+		RETURNING id, name, price, stock, created_at, retired_at
+	`
+
+	product, err := scanProduct(r.db.QueryRow(ctx, query, stock, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, db.ErrItemtNotFound
+		}
+		return nil, fmt.Errorf("repo - failed to update item stock: %w", err)
+	}
+
+	return product, nil
+}
+
+// Rename an item
+func (r *catalogRepo) RenameItem(ctx context.Context, id int64, name string) (*m.Product, string, error) {
+	query := `
+		WITH old AS (
+			SELECT id, name FROM items WHERE id = $2 FOR UPDATE
+		)
+		UPDATE items i
+		SET name = $1
+		FROM old
+		WHERE i.id = old.id
+		RETURNING i.id, i.name, i.price, i.stock, i.created_at, i.retired_at, old.name
+	`
+
+	var product m.Product
+	var oldName string
+	err := r.db.QueryRow(ctx, query, name, id).Scan(
+		&product.ID,
+		&product.Name,
+		&product.Price,
+		&product.Stock,
+		&product.CreatedAt,
+		&product.RetiredAt,
+		&oldName,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, "", db.ErrItemtNotFound
+		}
+		if postgres.IsUniqueViolation(err) {
+			return nil, "", db.ErrItemAlreadyExists
+		}
+		return nil, "", fmt.Errorf("repo - failed to rename item: %w", err)
+	}
+
+	return &product, oldName, nil
+}
+
+// Get users who own or ordered the item
+func (r *catalogRepo) GetItemHolders(ctx context.Context, id int64) ([]int64, error) {
+	query := `
+		SELECT user_id FROM inventory_items WHERE item_id = $1
+		UNION
+		SELECT user_id FROM orders WHERE item_id = $1 AND user_id IS NOT NULL
+	`
+
+	rows, err := r.db.Query(ctx, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("repo - failed to get item holders: %w", err)
+	}
+	defer rows.Close()
+
+	userIDs := make([]int64, 0)
+	for rows.Next() {
+		var userID int64
+		if err := rows.Scan(&userID); err != nil {
+			return nil, fmt.Errorf("repo - failed to scan row: %w", err)
+		}
+		userIDs = append(userIDs, userID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("repo - rows iteration error: %w", err)
+	}
+
+	return userIDs, nil
+}
+
+// Retire an item, so it can't be bought anymore
+func (r *catalogRepo) RetireItem(ctx context.Context, id int64) (*m.Product, error) {
+	query := `
+		UPDATE items
+		SET retired_at = COALESCE(retired_at, CURRENT_TIMESTAMP)
+		WHERE id = $1
+		RETURNING id, name, price, stock, created_at, retired_at
+	`
+
+	product, err := scanProduct(r.db.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, db.ErrItemtNotFound
+		}
+		return nil, fmt.Errorf("repo - failed to retire item: %w", err)
+	}
+
+	return product, nil
+}
+
+// Get items that are currently on sale
+func (r *catalogRepo) GetAvailableItems(ctx context.Context) ([]m.CatalogItem, error) {
+	query := `
+		SELECT name, price, stock
+		FROM items
+		WHERE retired_at IS NULL
+		ORDER BY name
+	`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("repo - failed to get catalog: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]m.CatalogItem, 0)
+	for rows.Next() {
+		var item m.CatalogItem
+		if err := rows.Scan(&item.Name, &item.Price, &item.Stock); err != nil {
+			return nil, fmt.Errorf("repo - failed to scan catalog item: %w", err)
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// Get user's balance
+func (r *catalogRepo) GetUserBalance(ctx context.Context, userID int64) (int64, error) {
+	var balance int64
+
+	query := `SELECT balance FROM users WHERE id = $1`
+	err := r.db.QueryRow(ctx, query, userID).Scan(&balance)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, db.ErrUserNotFound
+		}
+		return 0, fmt.Errorf("repo - failed to get balance: %w", err)
+	}
+
+	return balance, nil
+}
+
+// Scan item row into the product model
+func scanProduct(row pgx.Row) (*m.Product, error) {
+	var product m.Product
+	err := row.Scan(
+		&product.ID,
+		&product.Name,
+		&product.Price,
+		&product.Stock,
+		&product.CreatedAt,
+		&product.RetiredAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/go-redis/redis/v8"
+
+	"cyansnbrst/merch-service/config"
+	"cyansnbrst/merch-service/internal/catalog"
+	m "cyansnbrst/merch-service/internal/models"
+)
+
+// Move cart quantity to the new item name, merging with existing one
+var renameCartItemScript = redis.NewScript(`
+	local quantity = redis.call("HGET", KEYS[1], ARGV[1])
+	if not quantity then
+		return 0
+	end
+	redis.call("HDEL", KEYS[1], ARGV[1])
+	redis.call("HINCRBY", KEYS[1], ARGV[2], quantity)
+	return 1
+`)
+
+// Catalog redis repository
+type catalogRedisRepo struct {
+	cfg         *config.Config
+	redisClient *redis.Client
+}
+
+// Catalog redis repository constructor
+func NewCatalogRedisRepo(cfg *config.Config, redisClient *redis.Client) catalog.RedisRepository {
+	return &catalogRedisRepo{
+		cfg:         cfg,
+		redisClient: redisClient,
+	}
+}
+
+// Get cached catalog items
+func (r *catalogRedisRepo) GetItems(ctx context.Context, key string) ([]m.CatalogItem, error) {
+	itemsBytes, err := r.redisClient.Get(ctx, key).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var items []m.CatalogItem
+	if err = json.Unmarshal(itemsBytes, &items); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// Cache catalog items
+func (r *catalogRedisRepo) SetItems(ctx context.Context, key string, items []m.CatalogItem) error {
+	itemsBytes, err := json.Marshal(items)
+	if err != nil {
+		return err
+	}
+
+	if err = r.redisClient.Set(ctx, key, itemsBytes, r.cfg.Redis.CacheTTL).Err(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Delete cached catalog items
+func (r *catalogRedisRepo) DeleteItems(ctx context.Context, key string) error {
+	if err := r.redisClient.Del(ctx, key).Err(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Delete cached info of the users
+func (r *catalogRedisRepo) DeleteUserInfo(ctx context.Context, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	if err := r.redisClient.Del(ctx, keys...).Err(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Rename the item in every cart matching the pattern
+func (r *catalogRedisRepo) RenameCartItem(ctx context.Context, pattern, oldName, newName string) error {
+	iter := r.redisClient.Scan(ctx, 0, pattern, 0).Iterator()
+	for iter.Next(ctx) {
+		err := renameCartItemScript.Run(ctx, r.redisClient, []string{iter.Val()}, oldName, newName).Err()
+		if err != nil && err != redis.Nil {
+			return err
+		}
+	}
+	return iter.Err()
+}
@@ -0,0 +1,19 @@
+package catalog
+
+import (
+	"context"
+
+	m "cyansnbrst/merch-service/internal/models"
+)
+
+// Catalog usecase interface
+type UseCase interface {
+	CreateItem(ctx context.Context, name string, price int64, stock *int64) (*m.Product, error)
+	ListItems(ctx context.Context) ([]m.Product, error)
+	UpdateItemPrice(ctx context.Context, id, price int64) (*m.Product, error)
+	UpdateItemStock(ctx context.Context, id int64, stock *int64) (*m.Product, error)
+	RenameItem(ctx context.Context, id int64, name string) (*m.Product, error)
+	RetireItem(ctx context.Context, id int64) (*m.Product, error)
+	GetItems(ctx context.Context, userID int64, filter m.CatalogFilter) ([]m.CatalogItem, error)
+	GetItem(ctx context.Context, name string) (*m.CatalogItem, error)
+}
@@ -0,0 +1,204 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+
+	"cyansnbrst/merch-service/internal/catalog"
+	m "cyansnbrst/merch-service/internal/models"
+	"cyansnbrst/merch-service/pkg/db"
+	"cyansnbrst/merch-service/pkg/db/redis"
+)
+
+const (
+	sortPriceAsc  = "price_asc"
+	sortPriceDesc = "price_desc"
+)
+
+// Catalog usecase struct
+type catalogUC struct {
+	catalogRepo      catalog.Repository
+	catalogRedisRepo catalog.RedisRepository
+}
+
+// Catalog usecase constructor
+func NewCatalogUseCase(catalogRepo catalog.Repository, catalogRedisRepo catalog.RedisRepository) catalog.UseCase {
+	return &catalogUC{
+		catalogRepo:      catalogRepo,
+		catalogRedisRepo: catalogRedisRepo,
+	}
+}
+
+// Create a new item
+func (u *catalogUC) CreateItem(ctx context.Context, name string, price int64, stock *int64) (*m.Product, error) {
+	product, err := u.catalogRepo.CreateItem(ctx, name, price, stock)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.invalidateCatalog(ctx); err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
+
+// Get all items
+func (u *catalogUC) ListItems(ctx context.Context) ([]m.Product, error) {
+	return u.catalogRepo.ListItems(ctx)
+}
+
+// Update item's price
+func (u *catalogUC) UpdateItemPrice(ctx context.Context, id, price int64) (*m.Product, error) {
+	product, err := u.catalogRepo.UpdateItemPrice(ctx, id, price)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.invalidateCatalog(ctx); err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
+
+// Update item's stock
+func (u *catalogUC) UpdateItemStock(ctx context.Context, id int64, stock *int64) (*m.Product, error) {
+	product, err := u.catalogRepo.UpdateItemStock(ctx, id, stock)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.invalidateCatalog(ctx); err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
+
+// Rename an item, moving it to the new name in carts and dropping stale user info
+func (u *catalogUC) RenameItem(ctx context.Context, id int64, name string) (*m.Product, error) {
+	product, oldName, err := u.catalogRepo.RenameItem(ctx, id, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.invalidateCatalog(ctx); err != nil {
+		return nil, err
+	}
+
+	if oldName == product.Name {
+		return product, nil
+	}
+
+	holders, err := u.catalogRepo.GetItemHolders(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(holders))
+	for _, userID := range holders {
+		keys = append(keys, redis.GetUserInfoCacheKey(userID))
+	}
+	if err := u.catalogRedisRepo.DeleteUserInfo(ctx, keys); err != nil {
+		return nil, err
+	}
+
+	if err := u.catalogRedisRepo.RenameCartItem(ctx, redis.GetUserCartKeyPattern(), oldName, product.Name); err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
+
+// Retire an item
+func (u *catalogUC) RetireItem(ctx context.Context, id int64) (*m.Product, error) {
+	product, err := u.catalogRepo.RetireItem(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.invalidateCatalog(ctx); err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
+
+// Get items on sale with availability for the user
+func (u *catalogUC) GetItems(ctx context.Context, userID int64, filter m.CatalogFilter) ([]m.CatalogItem, error) {
+	items, err := u.getCachedItems(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	balance, err := u.catalogRepo.GetUserBalance(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]m.CatalogItem, 0, len(items))
+	for _, item := range items {
+		affordable := item.Price <= balance
+		if filter.Affordable && !affordable {
+			continue
+		}
+		item.Available = affordable && (item.Stock == nil || *item.Stock > 0)
+		result = append(result, item)
+	}
+
+	switch filter.Sort {
+	case sortPriceAsc:
+		sort.SliceStable(result, func(i, j int) bool { return result[i].Price < result[j].Price })
+	case sortPriceDesc:
+		sort.SliceStable(result, func(i, j int) bool { return result[i].Price > result[j].Price })
+	}
+
+	return result, nil
+}
+
+// Get item on sale by its name
+func (u *catalogUC) GetItem(ctx context.Context, name string) (*m.CatalogItem, error) {
+	items, err := u.getCachedItems(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range items {
+		if item.Name == name {
+			return &item, nil
+		}
+	}
+
+	return nil, db.ErrItemtNotFound
+}
+
+// Get items on sale from cache, loading them on miss
+func (u *catalogUC) getCachedItems(ctx context.Context) ([]m.CatalogItem, error) {
+	key := redis.GetCatalogCacheKey()
+
+	items, err := u.catalogRedisRepo.GetItems(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	if items != nil {
+		return items, nil
+	}
+
+	items, err = u.catalogRepo.GetAvailableItems(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.catalogRedisRepo.SetItems(ctx, key, items); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// Drop cached catalog after it was changed
+func (u *catalogUC) invalidateCatalog(ctx context.Context) error {
+	return u.catalogRedisRepo.DeleteItems(ctx, redis.GetCatalogCacheKey())
+}
@@ -0,0 +1,630 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	mock_catalog "cyansnbrst/merch-service/internal/catalog/mock"
+	"cyansnbrst/merch-service/internal/catalog/usecase"
+	m "cyansnbrst/merch-service/internal/models"
+	"cyansnbrst/merch-service/pkg/db"
+	"cyansnbrst/merch-service/pkg/db/redis"
+)
+
+var ErrRandomDBError = errors.New("db error")
+
+func TestCatalogUC_CreateItem(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_catalog.NewMockRepository(ctrl)
+	mockRedisRepo := mock_catalog.NewMockRedisRepository(ctrl)
+
+	catalogUC := usecase.NewCatalogUseCase(mockRepo, mockRedisRepo)
+
+	tests := []struct {
+		name          string
+		itemName      string
+		price         int64
+		mockSetup     func()
+		expectedResp  *m.Product
+		expectedError error
+	}{
+		{
+			name:     "success",
+			itemName: "sticker",
+			price:    5,
+			mockSetup: func() {
+				mockRepo.EXPECT().CreateItem(gomock.Any(), "sticker", int64(5), gomock.Nil()).Return(&m.Product{
+					ID:    11,
+					Name:  "sticker",
+					Price: 5,
+				}, nil)
+				mockRedisRepo.EXPECT().DeleteItems(gomock.Any(), redis.GetCatalogCacheKey()).Return(nil)
+			},
+			expectedResp: &m.Product{
+				ID:    11,
+				Name:  "sticker",
+				Price: 5,
+			},
+			expectedError: nil,
+		},
+		{
+			name:     "error delete cache",
+			itemName: "sticker",
+			price:    5,
+			mockSetup: func() {
+				mockRepo.EXPECT().CreateItem(gomock.Any(), "sticker", int64(5), gomock.Nil()).Return(&m.Product{
+					ID:    11,
+					Name:  "sticker",
+					Price: 5,
+				}, nil)
+				mockRedisRepo.EXPECT().DeleteItems(gomock.Any(), redis.GetCatalogCacheKey()).Return(ErrRandomDBError)
+			},
+			expectedResp:  nil,
+			expectedError: ErrRandomDBError,
+		},
+		{
+			name:     "error item already exists",
+			itemName: "cup",
+			price:    20,
+			mockSetup: func() {
+				mockRepo.EXPECT().CreateItem(gomock.Any(), "cup", int64(20), gomock.Nil()).Return(nil, db.ErrItemAlreadyExists)
+			},
+			expectedResp:  nil,
+			expectedError: db.ErrItemAlreadyExists,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			resp, err := catalogUC.CreateItem(context.Background(), tt.itemName, tt.price, nil)
+
+			assert.Equal(t, tt.expectedResp, resp)
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
+
+func TestCatalogUC_ListItems(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_catalog.NewMockRepository(ctrl)
+	mockRedisRepo := mock_catalog.NewMockRedisRepository(ctrl)
+
+	catalogUC := usecase.NewCatalogUseCase(mockRepo, mockRedisRepo)
+
+	retiredAt := time.Now()
+
+	tests := []struct {
+		name          string
+		mockSetup     func()
+		expectedResp  []m.Product
+		expectedError error
+	}{
+		{
+			name: "success",
+			mockSetup: func() {
+				mockRepo.EXPECT().ListItems(gomock.Any()).Return([]m.Product{
+					{ID: 1, Name: "t-shirt", Price: 80},
+					{ID: 2, Name: "cup", Price: 20, RetiredAt: &retiredAt},
+				}, nil)
+			},
+			expectedResp: []m.Product{
+				{ID: 1, Name: "t-shirt", Price: 80},
+				{ID: 2, Name: "cup", Price: 20, RetiredAt: &retiredAt},
+			},
+			expectedError: nil,
+		},
+		{
+			name: "error db error",
+			mockSetup: func() {
+				mockRepo.EXPECT().ListItems(gomock.Any()).Return(nil, ErrRandomDBError)
+			},
+			expectedResp:  nil,
+			expectedError: ErrRandomDBError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			resp, err := catalogUC.ListItems(context.Background())
+
+			assert.Equal(t, tt.expectedResp, resp)
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
+
+func TestCatalogUC_UpdateItemPrice(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_catalog.NewMockRepository(ctrl)
+	mockRedisRepo := mock_catalog.NewMockRedisRepository(ctrl)
+
+	catalogUC := usecase.NewCatalogUseCase(mockRepo, mockRedisRepo)
+
+	tests := []struct {
+		name          string
+		id            int64
+		price         int64
+		mockSetup     func()
+		expectedResp  *m.Product
+		expectedError error
+	}{
+		{
+			name:  "success",
+			id:    1,
+			price: 100,
+			mockSetup: func() {
+				mockRepo.EXPECT().UpdateItemPrice(gomock.Any(), int64(1), int64(100)).Return(&m.Product{
+					ID:    1,
+					Name:  "t-shirt",
+					Price: 100,
+				}, nil)
+				mockRedisRepo.EXPECT().DeleteItems(gomock.Any(), redis.GetCatalogCacheKey()).Return(nil)
+			},
+			expectedResp: &m.Product{
+				ID:    1,
+				Name:  "t-shirt",
+				Price: 100,
+			},
+			expectedError: nil,
+		},
+		{
+			name:  "error item not found",
+			id:    42,
+			price: 100,
+			mockSetup: func() {
+				mockRepo.EXPECT().UpdateItemPrice(gomock.Any(), int64(42), int64(100)).Return(nil, db.ErrItemtNotFound)
+			},
+			expectedResp:  nil,
+			expectedError: db.ErrItemtNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			resp, err := catalogUC.UpdateItemPrice(context.Background(), tt.id, tt.price)
+
+			assert.Equal(t, tt.expectedResp, resp)
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
+
+func TestCatalogUC_UpdateItemStock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_catalog.NewMockRepository(ctrl)
+	mockRedisRepo := mock_catalog.NewMockRedisRepository(ctrl)
+
+	catalogUC := usecase.NewCatalogUseCase(mockRepo, mockRedisRepo)
+
+	stock := int64(50)
+
+	tests := []struct {
+		name          string
+		id            int64
+		stock         *int64
+		mockSetup     func()
+		expectedResp  *m.Product
+		expectedError error
+	}{
+		{
+			name:  "success limited stock",
+			id:    10,
+			stock: &stock,
+			mockSetup: func() {
+				mockRepo.EXPECT().UpdateItemStock(gomock.Any(), int64(10), &stock).Return(&m.Product{
+					ID:    10,
+					Name:  "pink-hoody",
+					Price: 500,
+					Stock: &stock,
+				}, nil)
+				mockRedisRepo.EXPECT().DeleteItems(gomock.Any(), redis.GetCatalogCacheKey()).Return(nil)
+			},
+			expectedResp: &m.Product{
+				ID:    10,
+				Name:  "pink-hoody",
+				Price: 500,
+				Stock: &stock,
+			},
+			expectedError: nil,
+		},
+		{
+			name:  "success unlimited stock",
+			id:    10,
+			stock: nil,
+			mockSetup: func() {
+				mockRepo.EXPECT().UpdateItemStock(gomock.Any(), int64(10), gomock.Nil()).Return(&m.Product{
+					ID:    10,
+					Name:  "pink-hoody",
+					Price: 500,
+				}, nil)
+				mockRedisRepo.EXPECT().DeleteItems(gomock.Any(), redis.GetCatalogCacheKey()).Return(nil)
+			},
+			expectedResp: &m.Product{
+				ID:    10,
+				Name:  "pink-hoody",
+				Price: 500,
+			},
+			expectedError: nil,
+		},
+		{
+			name:  "error item not found",
+			id:    42,
+			stock: &stock,
+			mockSetup: func() {
+				mockRepo.EXPECT().UpdateItemStock(gomock.Any(), int64(42), &stock).Return(nil, db.ErrItemtNotFound)
+			},
+			expectedResp:  nil,
+			expectedError: db.ErrItemtNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			resp, err := catalogUC.UpdateItemStock(context.Background(), tt.id, tt.stock)
+
+			assert.Equal(t, tt.expectedResp, resp)
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
+
+func TestCatalogUC_RenameItem(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_catalog.NewMockRepository(ctrl)
+	mockRedisRepo := mock_catalog.NewMockRedisRepository(ctrl)
+
+	catalogUC := usecase.NewCatalogUseCase(mockRepo, mockRedisRepo)
+
+	tests := []struct {
+		name          string
+		id            int64
+		newName       string
+		mockSetup     func()
+		expectedResp  *m.Product
+		expectedError error
+	}{
+		{
+			name:    "success",
+			id:      6,
+			newName: "hoodie",
+			mockSetup: func() {
+				mockRepo.EXPECT().RenameItem(gomock.Any(), int64(6), "hoodie").Return(&m.Product{
+					ID:    6,
+					Name:  "hoodie",
+					Price: 300,
+				}, "hoody", nil)
+				mockRedisRepo.EXPECT().DeleteItems(gomock.Any(), redis.GetCatalogCacheKey()).Return(nil)
+				mockRepo.EXPECT().GetItemHolders(gomock.Any(), int64(6)).Return([]int64{1, 2}, nil)
+				mockRedisRepo.EXPECT().DeleteUserInfo(gomock.Any(), []string{
+					redis.GetUserInfoCacheKey(1),
+					redis.GetUserInfoCacheKey(2),
+				}).Return(nil)
+				mockRedisRepo.EXPECT().RenameCartItem(gomock.Any(), redis.GetUserCartKeyPattern(), "hoody", "hoodie").Return(nil)
+			},
+			expectedResp: &m.Product{
+				ID:    6,
+				Name:  "hoodie",
+				Price: 300,
+			},
+			expectedError: nil,
+		},
+		{
+			name:    "same name",
+			id:      6,
+			newName: "hoody",
+			mockSetup: func() {
+				mockRepo.EXPECT().RenameItem(gomock.Any(), int64(6), "hoody").Return(&m.Product{
+					ID:    6,
+					Name:  "hoody",
+					Price: 300,
+				}, "hoody", nil)
+				mockRedisRepo.EXPECT().DeleteItems(gomock.Any(), redis.GetCatalogCacheKey()).Return(nil)
+			},
+			expectedResp: &m.Product{
+				ID:    6,
+				Name:  "hoody",
+				Price: 300,
+			},
+			expectedError: nil,
+		},
+		{
+			name:    "error item already exists",
+			id:      6,
+			newName: "cup",
+			mockSetup: func() {
+				mockRepo.EXPECT().RenameItem(gomock.Any(), int64(6), "cup").Return(nil, "", db.ErrItemAlreadyExists)
+			},
+			expectedResp:  nil,
+			expectedError: db.ErrItemAlreadyExists,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			resp, err := catalogUC.RenameItem(context.Background(), tt.id, tt.newName)
+
+			assert.Equal(t, tt.expectedResp, resp)
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
+
+func TestCatalogUC_RetireItem(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_catalog.NewMockRepository(ctrl)
+	mockRedisRepo := mock_catalog.NewMockRedisRepository(ctrl)
+
+	catalogUC := usecase.NewCatalogUseCase(mockRepo, mockRedisRepo)
+
+	retiredAt := time.Now()
+
+	tests := []struct {
+		name          string
+		id            int64
+		mockSetup     func()
+		expectedResp  *m.Product
+		expectedError error
+	}{
+		{
+			name: "success",
+			id:   2,
+			mockSetup: func() {
+				mockRepo.EXPECT().RetireItem(gomock.Any(), int64(2)).Return(&m.Product{
+					ID:        2,
+					Name:      "cup",
+					Price:     20,
+					RetiredAt: &retiredAt,
+				}, nil)
+				mockRedisRepo.EXPECT().DeleteItems(gomock.Any(), redis.GetCatalogCacheKey()).Return(nil)
+			},
+			expectedResp: &m.Product{
+				ID:        2,
+				Name:      "cup",
+				Price:     20,
+				RetiredAt: &retiredAt,
+			},
+			expectedError: nil,
+		},
+		{
+			name: "error item not found",
+			id:   42,
+			mockSetup: func() {
+				mockRepo.EXPECT().RetireItem(gomock.Any(), int64(42)).Return(nil, db.ErrItemtNotFound)
+			},
+			expectedResp:  nil,
+			expectedError: db.ErrItemtNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			resp, err := catalogUC.RetireItem(context.Background(), tt.id)
+
+			assert.Equal(t, tt.expectedResp, resp)
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
+
+func TestCatalogUC_GetItems(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_catalog.NewMockRepository(ctrl)
+	mockRedisRepo := mock_catalog.NewMockRedisRepository(ctrl)
+
+	catalogUC := usecase.NewCatalogUseCase(mockRepo, mockRedisRepo)
+
+	var noStock int64
+
+	catalogItems := []m.CatalogItem{
+		{Name: "cup", Price: 20},
+		{Name: "hoody", Price: 300},
+		{Name: "pen", Price: 10},
+	}
+
+	tests := []struct {
+		name          string
+		userID        int64
+		filter        m.CatalogFilter
+		mockSetup     func()
+		expectedResp  []m.CatalogItem
+		expectedError error
+	}{
+		{
+			name:   "success data from DB",
+			userID: 1,
+			filter: m.CatalogFilter{},
+			mockSetup: func() {
+				mockRedisRepo.EXPECT().GetItems(gomock.Any(), redis.GetCatalogCacheKey()).Return(nil, nil)
+				mockRepo.EXPECT().GetAvailableItems(gomock.Any()).Return(catalogItems, nil)
+				mockRedisRepo.EXPECT().SetItems(gomock.Any(), redis.GetCatalogCacheKey(), catalogItems).Return(nil)
+				mockRepo.EXPECT().GetUserBalance(gomock.Any(), int64(1)).Return(int64(100), nil)
+			},
+			expectedResp: []m.CatalogItem{
+				{Name: "cup", Price: 20, Available: true},
+				{Name: "hoody", Price: 300, Available: false},
+				{Name: "pen", Price: 10, Available: true},
+			},
+			expectedError: nil,
+		},
+		{
+			name:   "success sorted by price descending",
+			userID: 2,
+			filter: m.CatalogFilter{Sort: "price_desc"},
+			mockSetup: func() {
+				mockRedisRepo.EXPECT().GetItems(gomock.Any(), redis.GetCatalogCacheKey()).Return(catalogItems, nil)
+				mockRepo.EXPECT().GetUserBalance(gomock.Any(), int64(2)).Return(int64(1000), nil)
+			},
+			expectedResp: []m.CatalogItem{
+				{Name: "hoody", Price: 300, Available: true},
+				{Name: "cup", Price: 20, Available: true},
+				{Name: "pen", Price: 10, Available: true},
+			},
+			expectedError: nil,
+		},
+		{
+			name:   "success only affordable sorted by price ascending",
+			userID: 3,
+			filter: m.CatalogFilter{Sort: "price_asc", Affordable: true},
+			mockSetup: func() {
+				mockRedisRepo.EXPECT().GetItems(gomock.Any(), redis.GetCatalogCacheKey()).Return(catalogItems, nil)
+				mockRepo.EXPECT().GetUserBalance(gomock.Any(), int64(3)).Return(int64(20), nil)
+			},
+			expectedResp: []m.CatalogItem{
+				{Name: "pen", Price: 10, Available: true},
+				{Name: "cup", Price: 20, Available: true},
+			},
+			expectedError: nil,
+		},
+		{
+			name:   "success out of stock item is unavailable",
+			userID: 7,
+			mockSetup: func() {
+				mockRedisRepo.EXPECT().GetItems(gomock.Any(), redis.GetCatalogCacheKey()).Return([]m.CatalogItem{
+					{Name: "pink-hoody", Price: 500, Stock: &noStock},
+					{Name: "socks", Price: 10},
+				}, nil)
+				mockRepo.EXPECT().GetUserBalance(gomock.Any(), int64(7)).Return(int64(1000), nil)
+			},
+			expectedResp: []m.CatalogItem{
+				{Name: "pink-hoody", Price: 500, Stock: &noStock, Available: false},
+				{Name: "socks", Price: 10, Available: true},
+			},
+			expectedError: nil,
+		},
+		{
+			name:   "error redis error",
+			userID: 4,
+			mockSetup: func() {
+				mockRedisRepo.EXPECT().GetItems(gomock.Any(), redis.GetCatalogCacheKey()).Return(nil, ErrRandomDBError)
+			},
+			expectedResp:  nil,
+			expectedError: ErrRandomDBError,
+		},
+		{
+			name:   "error db error in GetAvailableItems",
+			userID: 5,
+			mockSetup: func() {
+				mockRedisRepo.EXPECT().GetItems(gomock.Any(), redis.GetCatalogCacheKey()).Return(nil, nil)
+				mockRepo.EXPECT().GetAvailableItems(gomock.Any()).Return(nil, ErrRandomDBError)
+			},
+			expectedResp:  nil,
+			expectedError: ErrRandomDBError,
+		},
+		{
+			name:   "error user not found",
+			userID: 6,
+			mockSetup: func() {
+				mockRedisRepo.EXPECT().GetItems(gomock.Any(), redis.GetCatalogCacheKey()).Return(catalogItems, nil)
+				mockRepo.EXPECT().GetUserBalance(gomock.Any(), int64(6)).Return(int64(0), db.ErrUserNotFound)
+			},
+			expectedResp:  nil,
+			expectedError: db.ErrUserNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			resp, err := catalogUC.GetItems(context.Background(), tt.userID, tt.filter)
+
+			assert.Equal(t, tt.expectedResp, resp)
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
+
+func TestCatalogUC_GetItem(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_catalog.NewMockRepository(ctrl)
+	mockRedisRepo := mock_catalog.NewMockRedisRepository(ctrl)
+
+	catalogUC := usecase.NewCatalogUseCase(mockRepo, mockRedisRepo)
+
+	catalogItems := []m.CatalogItem{
+		{Name: "cup", Price: 20},
+		{Name: "pen", Price: 10},
+	}
+
+	tests := []struct {
+		name          string
+		itemName      string
+		mockSetup     func()
+		expectedResp  *m.CatalogItem
+		expectedError error
+	}{
+		{
+			name:     "success data from cache",
+			itemName: "pen",
+			mockSetup: func() {
+				mockRedisRepo.EXPECT().GetItems(gomock.Any(), redis.GetCatalogCacheKey()).Return(catalogItems, nil)
+			},
+			expectedResp:  &m.CatalogItem{Name: "pen", Price: 10},
+			expectedError: nil,
+		},
+		{
+			name:     "success data from DB",
+			itemName: "cup",
+			mockSetup: func() {
+				mockRedisRepo.EXPECT().GetItems(gomock.Any(), redis.GetCatalogCacheKey()).Return(nil, nil)
+				mockRepo.EXPECT().GetAvailableItems(gomock.Any()).Return(catalogItems, nil)
+				mockRedisRepo.EXPECT().SetItems(gomock.Any(), redis.GetCatalogCacheKey(), catalogItems).Return(nil)
+			},
+			expectedResp:  &m.CatalogItem{Name: "cup", Price: 20},
+			expectedError: nil,
+		},
+		{
+			name:     "error item not on sale",
+			itemName: "rocket",
+			mockSetup: func() {
+				mockRedisRepo.EXPECT().GetItems(gomock.Any(), redis.GetCatalogCacheKey()).Return(catalogItems, nil)
+			},
+			expectedResp:  nil,
+			expectedError: db.ErrItemtNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			resp, err := catalogUC.GetItem(context.Background(), tt.itemName)
+
+			assert.Equal(t, tt.expectedResp, resp)
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
@@ -0,0 +1,11 @@
+package invoice
+
+import "github.com/labstack/echo/v4"
+
+// Invoice handlers interface
+type Handlers interface {
+	CreateInvoice(c echo.Context) error
+	ListInvoices(c echo.Context) error
+	AcceptInvoice(c echo.Context) error
+	DeclineInvoice(c echo.Context) error
+}
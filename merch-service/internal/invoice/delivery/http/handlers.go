@@ -0,0 +1,178 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"cyansnbrst/merch-service/internal/invoice"
+	"cyansnbrst/merch-service/internal/middleware"
+	m "cyansnbrst/merch-service/internal/models"
+	"cyansnbrst/merch-service/pkg/db"
+	hh "cyansnbrst/merch-service/pkg/http_helpers"
+)
+
+// Invoice handlers struct
+type invoiceHandlers struct {
+	invoiceUC invoice.UseCase
+	logger    *zap.Logger
+}
+
+// Invoice handlers constructor
+func NewInvoiceHandlers(invoiceUC invoice.UseCase, logger *zap.Logger) invoice.Handlers {
+	return &invoiceHandlers{
+		invoiceUC: invoiceUC,
+		logger:    logger,
+	}
+}
+
+// @Summary		Create invoice
+// @Description	Ask another user for coins
+// @Tags		invoices
+// @Accept		json
+// @Produce		json
+// @Param input body models.CreateInvoiceRequest true "input"
+// @Success		201	{object}	models.Invoice				"created"
+// @Failure		400	{object}	httphelpers.ErrorResponse	"bad request"
+// @Failure		401	{object}	httphelpers.ErrorResponse	"authentication required"
+// @Failure		500	{object}	httphelpers.ErrorResponse	"internal server error"
+// @Security 	JWT
+// @Router		/invoices [post]
+func (h *invoiceHandlers) CreateInvoice(c echo.Context) error {
+	userID, err := middleware.ContextGetUserID(c)
+	if err != nil {
+		return hh.ServerErrorResponse(c, h.logger, err)
+	}
+
+	var input m.CreateInvoiceRequest
+	if err := c.Bind(&input); err != nil {
+		return hh.BadRequestResponse(c, err)
+	}
+
+	if err := c.Validate(input); err != nil {
+		return hh.BadRequestResponse(c, err)
+	}
+
+	inv, err := h.invoiceUC.CreateInvoice(c.Request().Context(), userID, input)
+	if err != nil {
+		if errors.Is(err, db.ErrUserNotFound) || errors.Is(err, db.ErrSelfInvoice) {
+			return hh.BadRequestResponse(c, err)
+		}
+		return hh.ServerErrorResponse(c, h.logger, err)
+	}
+
+	return c.JSON(http.StatusCreated, inv)
+}
+
+// @Summary		List invoices
+// @Description	Get invoices the user has to pay (incoming) or created by the user (outgoing), newest first.
+// @Tags		invoices
+// @Produce		json
+// @Param		direction	query	string	true	"incoming or outgoing"
+// @Success		200	{array}		models.Invoice				"successful"
+// @Failure		400	{object}	httphelpers.ErrorResponse	"bad request"
+// @Failure		401	{object}	httphelpers.ErrorResponse	"authentication required"
+// @Failure		500	{object}	httphelpers.ErrorResponse	"internal server error"
+// @Security 	JWT
+// @Router		/invoices [get]
+func (h *invoiceHandlers) ListInvoices(c echo.Context) error {
+	userID, err := middleware.ContextGetUserID(c)
+	if err != nil {
+		return hh.ServerErrorResponse(c, h.logger, err)
+	}
+
+	var filter m.InvoiceFilter
+	if err := c.Bind(&filter); err != nil {
+		return hh.BadRequestResponse(c, err)
+	}
+
+	if err := c.Validate(filter); err != nil {
+		return hh.BadRequestResponse(c, err)
+	}
+
+	invoices, err := h.invoiceUC.ListInvoices(c.Request().Context(), userID, filter)
+	if err != nil {
+		return hh.ServerErrorResponse(c, h.logger, err)
+	}
+
+	return c.JSON(http.StatusOK, invoices)
+}
+
+// @Summary		Accept invoice
+// @Description	Pay the invoice addressed to the user
+// @Tags		invoices
+// @Produce		json
+// @Param		id	path	int	true	"invoice id"
+// @Success		200
+// @Failure		400	{object}	httphelpers.ErrorResponse	"bad request"
+// @Failure		401	{object}	httphelpers.ErrorResponse	"authentication required"
+// @Failure		404	{object}	httphelpers.ErrorResponse	"invoice not found"
+// @Failure		500	{object}	httphelpers.ErrorResponse	"internal server error"
+// @Security 	JWT
+// @Router		/invoices/{id}/accept [post]
+func (h *invoiceHandlers) AcceptInvoice(c echo.Context) error {
+	userID, err := middleware.ContextGetUserID(c)
+	if err != nil {
+		return hh.ServerErrorResponse(c, h.logger, err)
+	}
+
+	invoiceID, err := hh.ReadIDParam(c)
+	if err != nil {
+		return hh.BadRequestResponse(c, err)
+	}
+
+	err = h.invoiceUC.AcceptInvoice(c.Request().Context(), userID, invoiceID)
+	if err != nil {
+		var limitErr *db.LimitExceededError
+		if errors.Is(err, db.ErrInsufficientFunds) || errors.Is(err, db.ErrUserNotFound) || errors.Is(err, db.ErrUserDeactivated) || errors.As(err, &limitErr) {
+			return hh.BadRequestResponse(c, err)
+		}
+		return h.invoiceErrorResponse(c, err)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+// @Summary		Decline invoice
+// @Description	Refuse to pay the invoice addressed to the user
+// @Tags		invoices
+// @Produce		json
+// @Param		id	path	int	true	"invoice id"
+// @Success		200
+// @Failure		400	{object}	httphelpers.ErrorResponse	"bad request"
+// @Failure		401	{object}	httphelpers.ErrorResponse	"authentication required"
+// @Failure		404	{object}	httphelpers.ErrorResponse	"invoice not found"
+// @Failure		500	{object}	httphelpers.ErrorResponse	"internal server error"
+// @Security 	JWT
+// @Router		/invoices/{id}/decline [post]
+func (h *invoiceHandlers) DeclineInvoice(c echo.Context) error {
+	userID, err := middleware.ContextGetUserID(c)
+	if err != nil {
+		return hh.ServerErrorResponse(c, h.logger, err)
+	}
+
+	invoiceID, err := hh.ReadIDParam(c)
+	if err != nil {
+		return hh.BadRequestResponse(c, err)
+	}
+
+	err = h.invoiceUC.DeclineInvoice(c.Request().Context(), userID, invoiceID)
+	if err != nil {
+		return h.invoiceErrorResponse(c, err)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+// Map invoice errors to responses
+func (h *invoiceHandlers) invoiceErrorResponse(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, db.ErrInvoiceNotFound):
+		return hh.NotFoundResponse(c, err)
+	case errors.Is(err, db.ErrInvoiceNotPending):
+		return hh.BadRequestResponse(c, err)
+	}
+	return hh.ServerErrorResponse(c, h.logger, err)
+}
@@ -0,0 +1,15 @@
+package http
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"cyansnbrst/merch-service/internal/invoice"
+)
+
+// Register invoice routes
+func RegisterInvoiceRoutes(g *echo.Group, h invoice.Handlers) {
+	g.POST("/invoices", h.CreateInvoice)
+	g.GET("/invoices", h.ListInvoices)
+	g.POST("/invoices/:id/accept", h.AcceptInvoice)
+	g.POST("/invoices/:id/decline", h.DeclineInvoice)
+}
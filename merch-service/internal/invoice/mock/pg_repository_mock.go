@@ -0,0 +1,110 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/invoice/pg_repository.go
+
+// Package mock_invoice is a generated GoMock package.
+package mock_invoice
+
+import (
+	context "context"
+	models "cyansnbrst/merch-service/internal/models"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateInvoice mocks base method.
+func (m *MockRepository) CreateInvoice(ctx context.Context, toUserID int64, input models.CreateInvoiceRequest) (*models.Invoice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateInvoice", ctx, toUserID, input)
+	ret0, _ := ret[0].(*models.Invoice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateInvoice indicates an expected call of CreateInvoice.
+func (mr *MockRepositoryMockRecorder) CreateInvoice(ctx, toUserID, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateInvoice", reflect.TypeOf((*MockRepository)(nil).CreateInvoice), ctx, toUserID, input)
+}
+
+// GetInvoice mocks base method.
+func (m *MockRepository) GetInvoice(ctx context.Context, invoiceID int64) (*models.Invoice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInvoice", ctx, invoiceID)
+	ret0, _ := ret[0].(*models.Invoice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInvoice indicates an expected call of GetInvoice.
+func (mr *MockRepositoryMockRecorder) GetInvoice(ctx, invoiceID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInvoice", reflect.TypeOf((*MockRepository)(nil).GetInvoice), ctx, invoiceID)
+}
+
+// ListIncoming mocks base method.
+func (m *MockRepository) ListIncoming(ctx context.Context, userID int64) ([]models.Invoice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListIncoming", ctx, userID)
+	ret0, _ := ret[0].([]models.Invoice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListIncoming indicates an expected call of ListIncoming.
+func (mr *MockRepositoryMockRecorder) ListIncoming(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListIncoming", reflect.TypeOf((*MockRepository)(nil).ListIncoming), ctx, userID)
+}
+
+// ListOutgoing mocks base method.
+func (m *MockRepository) ListOutgoing(ctx context.Context, userID int64) ([]models.Invoice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOutgoing", ctx, userID)
+	ret0, _ := ret[0].([]models.Invoice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOutgoing indicates an expected call of ListOutgoing.
+func (mr *MockRepositoryMockRecorder) ListOutgoing(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOutgoing", reflect.TypeOf((*MockRepository)(nil).ListOutgoing), ctx, userID)
+}
+
+// ResolveInvoice mocks base method.
+func (m *MockRepository) ResolveInvoice(ctx context.Context, invoiceID int64, status string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveInvoice", ctx, invoiceID, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResolveInvoice indicates an expected call of ResolveInvoice.
+func (mr *MockRepositoryMockRecorder) ResolveInvoice(ctx, invoiceID, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveInvoice", reflect.TypeOf((*MockRepository)(nil).ResolveInvoice), ctx, invoiceID, status)
+}
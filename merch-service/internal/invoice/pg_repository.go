@@ -0,0 +1,16 @@
+package invoice
+
+import (
+	"context"
+
+	m "cyansnbrst/merch-service/internal/models"
+)
+
+// Invoice repository interface
+type Repository interface {
+	CreateInvoice(ctx context.Context, toUserID int64, input m.CreateInvoiceRequest) (*m.Invoice, error)
+	GetInvoice(ctx context.Context, invoiceID int64) (*m.Invoice, error)
+	ListIncoming(ctx context.Context, userID int64) ([]m.Invoice, error)
+	ListOutgoing(ctx context.Context, userID int64) ([]m.Invoice, error)
+	ResolveInvoice(ctx context.Context, invoiceID int64, status string) error
+}
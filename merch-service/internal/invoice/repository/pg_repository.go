@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"cyansnbrst/merch-service/internal/invoice"
+	m "cyansnbrst/merch-service/internal/models"
+	"cyansnbrst/merch-service/pkg/db"
+)
+
+// Counterparty shown for deleted users
+const deletedCounterparty = "deleted user"
+
+// Invoice repository struct
+type invoiceRepo struct {
+	db *pgxpool.Pool
+}
+
+// Invoice repository constructor
+func NewInvoiceRepo(db *pgxpool.Pool) invoice.Repository {
+	return &invoiceRepo{db: db}
+}
+
+// Create invoice, asking the user with the given username for coins
+func (r *invoiceRepo) CreateInvoice(ctx context.Context, toUserID int64, input m.CreateInvoiceRequest) (*m.Invoice, error) {
+	var fromUserID int64
+	userQuery := `SELECT id FROM users WHERE username = $1`
+	err := r.db.QueryRow(ctx, userQuery, input.FromUser).Scan(&fromUserID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, db.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("repo - failed to get user id: %w", err)
+	}
+
+	if fromUserID == toUserID {
+		return nil, db.ErrSelfInvoice
+	}
+
+	var invoiceID int64
+	query := `
+		INSERT INTO invoices (from_id, to_id, amount, comment)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`
+	err = r.db.QueryRow(ctx, query, fromUserID, toUserID, input.Amount, input.Comment).Scan(&invoiceID)
+	if err != nil {
+		return nil, fmt.Errorf("repo - failed to create invoice: %w", err)
+	}
+
+	return r.GetInvoice(ctx, invoiceID)
+}
+
+// Get invoice by ID
+func (r *invoiceRepo) GetInvoice(ctx context.Context, invoiceID int64) (*m.Invoice, error) {
+	query := `
+		SELECT i.id, COALESCE(i.from_id, 0), COALESCE(uf.username, $2), COALESCE(i.to_id, 0), COALESCE(ut.username, $2),
+			i.amount, i.comment, i.status, i.created_at, i.resolved_at
+		FROM invoices i
+		LEFT JOIN users uf ON i.from_id = uf.id
+		LEFT JOIN users ut ON i.to_id = ut.id
+		WHERE i.id = $1
+	`
+
+	inv, err := scanInvoice(r.db.QueryRow(ctx, query, invoiceID, deletedCounterparty))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, db.ErrInvoiceNotFound
+		}
+		return nil, fmt.Errorf("repo - failed to get invoice: %w", err)
+	}
+
+	return inv, nil
+}
+
+// Get invoices the user has to pay
+func (r *invoiceRepo) ListIncoming(ctx context.Context, userID int64) ([]m.Invoice, error) {
+	query := `
+		SELECT i.id, COALESCE(i.from_id, 0), COALESCE(uf.username, $2), COALESCE(i.to_id, 0), COALESCE(ut.username, $2),
+			i.amount, i.comment, i.status, i.created_at, i.resolved_at
+		FROM invoices i
+		LEFT JOIN users uf ON i.from_id = uf.id
+		LEFT JOIN users ut ON i.to_id = ut.id
+		WHERE i.from_id = $1
+		ORDER BY i.id DESC
+	`
+	return r.listInvoices(ctx, query, userID)
+}
+
+// Get invoices created by the user
+func (r *invoiceRepo) ListOutgoing(ctx context.Context, userID int64) ([]m.Invoice, error) {
+	query := `
+		SELECT i.id, COALESCE(i.from_id, 0), COALESCE(uf.username, $2), COALESCE(i.to_id, 0), COALESCE(ut.username, $2),
+			i.amount, i.comment, i.status, i.created_at, i.resolved_at
+		FROM invoices i
+		LEFT JOIN users uf ON i.from_id = uf.id
+		LEFT JOIN users ut ON i.to_id = ut.id
+		WHERE i.to_id = $1
+		ORDER BY i.id DESC
+	`
+	return r.listInvoices(ctx, query, userID)
+}
+
+// Move pending invoice to the final status
+func (r *invoiceRepo) ResolveInvoice(ctx context.Context, invoiceID int64, status string) error {
+	query := `
+		UPDATE invoices
+		SET status = $1, resolved_at = CURRENT_TIMESTAMP
+		WHERE id = $2 AND status = 'pending'
+	`
+
+	tag, err := r.db.Exec(ctx, query, status, invoiceID)
+	if err != nil {
+		return fmt.Errorf("repo - failed to resolve invoice: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return db.ErrInvoiceNotPending
+	}
+
+	return nil
+}
+
+// Query invoices for the user
+func (r *invoiceRepo) listInvoices(ctx context.Context, query string, userID int64) ([]m.Invoice, error) {
+	rows, err := r.db.Query(ctx, query, userID, deletedCounterparty)
+	if err != nil {
+		return nil, fmt.Errorf("repo - failed to get invoices: %w", err)
+	}
+	defer rows.Close()
+
+	invoices := make([]m.Invoice, 0)
+	for rows.Next() {
+		inv, err := scanInvoice(rows)
+		if err != nil {
+			return nil, fmt.Errorf("repo - failed to scan invoice: %w", err)
+		}
+		invoices = append(invoices, *inv)
+	}
+
+	return invoices, nil
+}
+
+// Scan invoice row into the invoice model
+func scanInvoice(row pgx.Row) (*m.Invoice, error) {
+	var inv m.Invoice
+	err := row.Scan(
+		&inv.ID,
+		&inv.FromID,
+		&inv.FromUser,
+		&inv.ToID,
+		&inv.ToUser,
+		&inv.Amount,
+		&inv.Comment,
+		&inv.Status,
+		&inv.CreatedAt,
+		&inv.ResolvedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &inv, nil
+}
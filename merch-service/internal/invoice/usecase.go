@@ -0,0 +1,15 @@
+package invoice
+
+import (
+	"context"
+
+	m "cyansnbrst/merch-service/internal/models"
+)
+
+// Invoice usecase interface
+type UseCase interface {
+	CreateInvoice(ctx context.Context, userID int64, input m.CreateInvoiceRequest) (*m.Invoice, error)
+	ListInvoices(ctx context.Context, userID int64, filter m.InvoiceFilter) ([]m.Invoice, error)
+	AcceptInvoice(ctx context.Context, userID, invoiceID int64) error
+	DeclineInvoice(ctx context.Context, userID, invoiceID int64) error
+}
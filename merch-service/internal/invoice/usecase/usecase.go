@@ -0,0 +1,79 @@
+package usecase
+
+import (
+	"context"
+
+	"cyansnbrst/merch-service/internal/invoice"
+	"cyansnbrst/merch-service/internal/merch"
+	m "cyansnbrst/merch-service/internal/models"
+	"cyansnbrst/merch-service/pkg/db"
+)
+
+const directionIncoming = "incoming"
+
+// Invoice usecase struct
+type invoiceUC struct {
+	invoiceRepo invoice.Repository
+	merchUC     merch.UseCase
+}
+
+// Invoice usecase constructor
+func NewInvoiceUseCase(invoiceRepo invoice.Repository, merchUC merch.UseCase) invoice.UseCase {
+	return &invoiceUC{
+		invoiceRepo: invoiceRepo,
+		merchUC:     merchUC,
+	}
+}
+
+// Ask another user for coins
+func (u *invoiceUC) CreateInvoice(ctx context.Context, userID int64, input m.CreateInvoiceRequest) (*m.Invoice, error) {
+	return u.invoiceRepo.CreateInvoice(ctx, userID, input)
+}
+
+// Get user's incoming or outgoing invoices
+func (u *invoiceUC) ListInvoices(ctx context.Context, userID int64, filter m.InvoiceFilter) ([]m.Invoice, error) {
+	if filter.Direction == directionIncoming {
+		return u.invoiceRepo.ListIncoming(ctx, userID)
+	}
+	return u.invoiceRepo.ListOutgoing(ctx, userID)
+}
+
+// Pay the invoice
+func (u *invoiceUC) AcceptInvoice(ctx context.Context, userID, invoiceID int64) error {
+	inv, err := u.getPendingInvoice(ctx, userID, invoiceID)
+	if err != nil {
+		return err
+	}
+
+	// Invoice is accepted in the same transaction as the transfer,
+	// so it's paid only once even if accepted or declined concurrently
+	return u.merchUC.PayInvoice(ctx, userID, inv.ID)
+}
+
+// Refuse to pay the invoice
+func (u *invoiceUC) DeclineInvoice(ctx context.Context, userID, invoiceID int64) error {
+	inv, err := u.getPendingInvoice(ctx, userID, invoiceID)
+	if err != nil {
+		return err
+	}
+
+	return u.invoiceRepo.ResolveInvoice(ctx, inv.ID, m.InvoiceStatusDeclined)
+}
+
+// Get invoice addressed to the user which is still waiting for an answer
+func (u *invoiceUC) getPendingInvoice(ctx context.Context, userID, invoiceID int64) (*m.Invoice, error) {
+	inv, err := u.invoiceRepo.GetInvoice(ctx, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if inv.FromID != userID {
+		return nil, db.ErrInvoiceNotFound
+	}
+
+	if inv.Status != m.InvoiceStatusPending {
+		return nil, db.ErrInvoiceNotPending
+	}
+
+	return inv, nil
+}
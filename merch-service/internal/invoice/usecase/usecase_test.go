@@ -0,0 +1,342 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	mock_invoice "cyansnbrst/merch-service/internal/invoice/mock"
+	"cyansnbrst/merch-service/internal/invoice/usecase"
+	mock_merch "cyansnbrst/merch-service/internal/merch/mock"
+	m "cyansnbrst/merch-service/internal/models"
+	"cyansnbrst/merch-service/pkg/db"
+)
+
+var ErrRandomDBError = errors.New("db error")
+
+func TestInvoiceUC_CreateInvoice(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_invoice.NewMockRepository(ctrl)
+	mockMerchUC := mock_merch.NewMockUseCase(ctrl)
+
+	invoiceUC := usecase.NewInvoiceUseCase(mockRepo, mockMerchUC)
+
+	createdAt := time.Now()
+
+	tests := []struct {
+		name          string
+		userID        int64
+		input         m.CreateInvoiceRequest
+		mockSetup     func()
+		expectedResp  *m.Invoice
+		expectedError error
+	}{
+		{
+			name:   "success",
+			userID: 2,
+			input:  m.CreateInvoiceRequest{FromUser: "user1", Amount: 100, Comment: "lunch"},
+			mockSetup: func() {
+				mockRepo.EXPECT().CreateInvoice(gomock.Any(), int64(2), m.CreateInvoiceRequest{FromUser: "user1", Amount: 100, Comment: "lunch"}).Return(&m.Invoice{
+					ID:        1,
+					FromID:    1,
+					FromUser:  "user1",
+					ToID:      2,
+					ToUser:    "user2",
+					Amount:    100,
+					Comment:   "lunch",
+					Status:    m.InvoiceStatusPending,
+					CreatedAt: createdAt,
+				}, nil)
+			},
+			expectedResp: &m.Invoice{
+				ID:        1,
+				FromID:    1,
+				FromUser:  "user1",
+				ToID:      2,
+				ToUser:    "user2",
+				Amount:    100,
+				Comment:   "lunch",
+				Status:    m.InvoiceStatusPending,
+				CreatedAt: createdAt,
+			},
+			expectedError: nil,
+		},
+		{
+			name:   "error self invoice",
+			userID: 2,
+			input:  m.CreateInvoiceRequest{FromUser: "user2", Amount: 100},
+			mockSetup: func() {
+				mockRepo.EXPECT().CreateInvoice(gomock.Any(), int64(2), m.CreateInvoiceRequest{FromUser: "user2", Amount: 100}).Return(nil, db.ErrSelfInvoice)
+			},
+			expectedResp:  nil,
+			expectedError: db.ErrSelfInvoice,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			resp, err := invoiceUC.CreateInvoice(context.Background(), tt.userID, tt.input)
+
+			assert.Equal(t, tt.expectedResp, resp)
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
+
+func TestInvoiceUC_ListInvoices(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_invoice.NewMockRepository(ctrl)
+	mockMerchUC := mock_merch.NewMockUseCase(ctrl)
+
+	invoiceUC := usecase.NewInvoiceUseCase(mockRepo, mockMerchUC)
+
+	tests := []struct {
+		name          string
+		userID        int64
+		filter        m.InvoiceFilter
+		mockSetup     func()
+		expectedResp  []m.Invoice
+		expectedError error
+	}{
+		{
+			name:   "success incoming",
+			userID: 1,
+			filter: m.InvoiceFilter{Direction: "incoming"},
+			mockSetup: func() {
+				mockRepo.EXPECT().ListIncoming(gomock.Any(), int64(1)).Return([]m.Invoice{{ID: 1, FromID: 1, ToID: 2}}, nil)
+			},
+			expectedResp:  []m.Invoice{{ID: 1, FromID: 1, ToID: 2}},
+			expectedError: nil,
+		},
+		{
+			name:   "success outgoing",
+			userID: 2,
+			filter: m.InvoiceFilter{Direction: "outgoing"},
+			mockSetup: func() {
+				mockRepo.EXPECT().ListOutgoing(gomock.Any(), int64(2)).Return([]m.Invoice{{ID: 1, FromID: 1, ToID: 2}}, nil)
+			},
+			expectedResp:  []m.Invoice{{ID: 1, FromID: 1, ToID: 2}},
+			expectedError: nil,
+		},
+		{
+			name:   "error db error",
+			userID: 3,
+			filter: m.InvoiceFilter{Direction: "incoming"},
+			mockSetup: func() {
+				mockRepo.EXPECT().ListIncoming(gomock.Any(), int64(3)).Return(nil, ErrRandomDBError)
+			},
+			expectedResp:  nil,
+			expectedError: ErrRandomDBError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			resp, err := invoiceUC.ListInvoices(context.Background(), tt.userID, tt.filter)
+
+			assert.Equal(t, tt.expectedResp, resp)
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
+
+func TestInvoiceUC_AcceptInvoice(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_invoice.NewMockRepository(ctrl)
+	mockMerchUC := mock_merch.NewMockUseCase(ctrl)
+
+	invoiceUC := usecase.NewInvoiceUseCase(mockRepo, mockMerchUC)
+
+	tests := []struct {
+		name          string
+		userID        int64
+		invoiceID     int64
+		mockSetup     func()
+		expectedError error
+	}{
+		{
+			name:      "success",
+			userID:    1,
+			invoiceID: 10,
+			mockSetup: func() {
+				mockRepo.EXPECT().GetInvoice(gomock.Any(), int64(10)).Return(&m.Invoice{
+					ID:      10,
+					FromID:  1,
+					ToID:    2,
+					ToUser:  "user2",
+					Amount:  100,
+					Comment: "lunch",
+					Status:  m.InvoiceStatusPending,
+				}, nil)
+				mockMerchUC.EXPECT().PayInvoice(gomock.Any(), int64(1), int64(10)).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:      "error not the payer",
+			userID:    2,
+			invoiceID: 10,
+			mockSetup: func() {
+				mockRepo.EXPECT().GetInvoice(gomock.Any(), int64(10)).Return(&m.Invoice{
+					ID:     10,
+					FromID: 1,
+					ToID:   2,
+					Status: m.InvoiceStatusPending,
+				}, nil)
+			},
+			expectedError: db.ErrInvoiceNotFound,
+		},
+		{
+			name:      "error already declined",
+			userID:    1,
+			invoiceID: 11,
+			mockSetup: func() {
+				mockRepo.EXPECT().GetInvoice(gomock.Any(), int64(11)).Return(&m.Invoice{
+					ID:     11,
+					FromID: 1,
+					ToID:   2,
+					Status: m.InvoiceStatusDeclined,
+				}, nil)
+			},
+			expectedError: db.ErrInvoiceNotPending,
+		},
+		{
+			name:      "error insufficient funds",
+			userID:    1,
+			invoiceID: 12,
+			mockSetup: func() {
+				mockRepo.EXPECT().GetInvoice(gomock.Any(), int64(12)).Return(&m.Invoice{
+					ID:     12,
+					FromID: 1,
+					ToID:   2,
+					ToUser: "user2",
+					Amount: 5000,
+					Status: m.InvoiceStatusPending,
+				}, nil)
+				mockMerchUC.EXPECT().PayInvoice(gomock.Any(), int64(1), int64(12)).Return(db.ErrInsufficientFunds)
+			},
+			expectedError: db.ErrInsufficientFunds,
+		},
+		{
+			name:      "error declined concurrently",
+			userID:    1,
+			invoiceID: 14,
+			mockSetup: func() {
+				mockRepo.EXPECT().GetInvoice(gomock.Any(), int64(14)).Return(&m.Invoice{
+					ID:     14,
+					FromID: 1,
+					ToID:   2,
+					Status: m.InvoiceStatusPending,
+				}, nil)
+				mockMerchUC.EXPECT().PayInvoice(gomock.Any(), int64(1), int64(14)).Return(db.ErrInvoiceNotPending)
+			},
+			expectedError: db.ErrInvoiceNotPending,
+		},
+		{
+			name:      "error invoice not found",
+			userID:    1,
+			invoiceID: 13,
+			mockSetup: func() {
+				mockRepo.EXPECT().GetInvoice(gomock.Any(), int64(13)).Return(nil, db.ErrInvoiceNotFound)
+			},
+			expectedError: db.ErrInvoiceNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			err := invoiceUC.AcceptInvoice(context.Background(), tt.userID, tt.invoiceID)
+
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
+
+func TestInvoiceUC_DeclineInvoice(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_invoice.NewMockRepository(ctrl)
+	mockMerchUC := mock_merch.NewMockUseCase(ctrl)
+
+	invoiceUC := usecase.NewInvoiceUseCase(mockRepo, mockMerchUC)
+
+	tests := []struct {
+		name          string
+		userID        int64
+		invoiceID     int64
+		mockSetup     func()
+		expectedError error
+	}{
+		{
+			name:      "success",
+			userID:    1,
+			invoiceID: 10,
+			mockSetup: func() {
+				mockRepo.EXPECT().GetInvoice(gomock.Any(), int64(10)).Return(&m.Invoice{
+					ID:     10,
+					FromID: 1,
+					ToID:   2,
+					Status: m.InvoiceStatusPending,
+				}, nil)
+				mockRepo.EXPECT().ResolveInvoice(gomock.Any(), int64(10), m.InvoiceStatusDeclined).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:      "error already accepted",
+			userID:    1,
+			invoiceID: 11,
+			mockSetup: func() {
+				mockRepo.EXPECT().GetInvoice(gomock.Any(), int64(11)).Return(&m.Invoice{
+					ID:     11,
+					FromID: 1,
+					ToID:   2,
+					Status: m.InvoiceStatusAccepted,
+				}, nil)
+			},
+			expectedError: db.ErrInvoiceNotPending,
+		},
+		{
+			name:      "error resolved concurrently",
+			userID:    1,
+			invoiceID: 12,
+			mockSetup: func() {
+				mockRepo.EXPECT().GetInvoice(gomock.Any(), int64(12)).Return(&m.Invoice{
+					ID:     12,
+					FromID: 1,
+					ToID:   2,
+					Status: m.InvoiceStatusPending,
+				}, nil)
+				mockRepo.EXPECT().ResolveInvoice(gomock.Any(), int64(12), m.InvoiceStatusDeclined).Return(db.ErrInvoiceNotPending)
+			},
+			expectedError: db.ErrInvoiceNotPending,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			err := invoiceUC.DeclineInvoice(context.Background(), tt.userID, tt.invoiceID)
+
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
@@ -5,6 +5,12 @@ import "github.com/labstack/echo/v4"
 // Merch handlers interface
 type Handlers interface {
 	GetInfo(c echo.Context) error
+	GetHistory(c echo.Context) error
 	SendCoins(c echo.Context) error
+	SendCoinBatch(c echo.Context) error
+	GrantCoins(c echo.Context) error
+	GiftItem(c echo.Context) error
 	BuyItem(c echo.Context) error
+	RefundOrder(c echo.Context) error
+	AdminRefundOrder(c echo.Context) error
 }
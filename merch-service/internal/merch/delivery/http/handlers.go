@@ -8,6 +8,7 @@ import (
 	"go.uber.org/zap"
 
 	"cyansnbrst/merch-service/internal/merch"
+	"cyansnbrst/merch-service/internal/merch/usecase"
 	"cyansnbrst/merch-service/internal/middleware"
 	m "cyansnbrst/merch-service/internal/models"
 	"cyansnbrst/merch-service/pkg/db"
@@ -29,7 +30,7 @@ func NewMerchHandlers(merchUC merch.UseCase, logger *zap.Logger) merch.Handlers
 }
 
 // @Summary		Get user's info
-// @Description	Get user's balance and transactions history.
+// @Description	Get user's balance, inventory, transactions and purchase history.
 // @Tags		merch
 // @Produce		json
 // @Success		200	{object}	models.InfoResponse			"successful"
@@ -52,15 +53,56 @@ func (h *merchHandlers) GetInfo(c echo.Context) error {
 	return c.JSON(http.StatusOK, userInfo)
 }
 
+// @Summary		Get transaction history
+// @Description	Get user's coin transactions, newest first. Use next_cursor from the response to get the next page.
+// @Tags		merch
+// @Produce		json
+// @Param		cursor			query	int		false	"return transactions older than this id"
+// @Param		direction		query	string	false	"sent or received"
+// @Param		counterparty	query	string	false	"username of the other side"
+// @Param		from			query	string	false	"RFC 3339 start of the date range, inclusive"
+// @Param		to				query	string	false	"RFC 3339 end of the date range, exclusive"
+// @Param		limit			query	int		false	"page size, 20 by default"
+// @Success		200	{object}	models.HistoryPage			"successful"
+// @Failure		400	{object}	httphelpers.ErrorResponse	"bad request"
+// @Failure		401	{object}	httphelpers.ErrorResponse	"authentication required"
+// @Failure		500	{object}	httphelpers.ErrorResponse	"internal server error"
+// @Security 	JWT
+// @Router		/history [get]
+func (h *merchHandlers) GetHistory(c echo.Context) error {
+	userID, err := middleware.ContextGetUserID(c)
+	if err != nil {
+		return hh.ServerErrorResponse(c, h.logger, err)
+	}
+
+	var filter m.HistoryFilter
+	if err := c.Bind(&filter); err != nil {
+		return hh.BadRequestResponse(c, err)
+	}
+
+	if err := c.Validate(filter); err != nil {
+		return hh.BadRequestResponse(c, err)
+	}
+
+	page, err := h.merchUC.GetHistory(c.Request().Context(), userID, filter)
+	if err != nil {
+		return hh.ServerErrorResponse(c, h.logger, err)
+	}
+
+	return c.JSON(http.StatusOK, page)
+}
+
 // @Summary		Send coins
 // @Description	Send coins to another user
 // @Tags		merch
 // @Accept 		json
 // @Produce		json
-// @Param input body models.SendTransaction true "input"
+// @Param input body models.SendCoinRequest true "input"
+// @Param		Idempotency-Key	header	string	false	"unique key to safely retry the request"
 // @Success		200
 // @Failure		400	{object}	httphelpers.ErrorResponse	"bad request"
 // @Failure		401	{object}	httphelpers.ErrorResponse	"authentication required"
+// @Failure		409	{object}	httphelpers.ErrorResponse	"idempotency key reused"
 // @Failure		500	{object}	httphelpers.ErrorResponse	"internal server error"
 // @Security 	JWT
 // @Router		/sendCoin [post]
@@ -70,7 +112,54 @@ func (h *merchHandlers) SendCoins(c echo.Context) error {
 		return hh.ServerErrorResponse(c, h.logger, err)
 	}
 
-	var input m.SendTransaction
+	var input m.SendCoinRequest
+	if err := c.Bind(&input); err != nil {
+		return hh.BadRequestResponse(c, err)
+	}
+
+	if err := c.Validate(input); err != nil {
+		return hh.BadRequestResponse(c, err)
+	}
+
+	idempotencyKey, err := hh.ReadIdempotencyKey(c)
+	if err != nil {
+		return hh.BadRequestResponse(c, err)
+	}
+
+	err = h.merchUC.SendCoins(c.Request().Context(), userID, input, idempotencyKey)
+	if err != nil {
+		var limitErr *db.LimitExceededError
+		if errors.Is(err, db.ErrInsufficientFunds) || errors.Is(err, db.ErrIncorrectReciever) || errors.Is(err, db.ErrUserNotFound) || errors.Is(err, db.ErrUserDeactivated) || errors.As(err, &limitErr) {
+			return hh.BadRequestResponse(c, err)
+		}
+		if errors.Is(err, usecase.ErrIdempotencyKeyReused) {
+			return hh.ConflictResponse(c, err)
+		}
+		return hh.ServerErrorResponse(c, h.logger, err)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+// @Summary		Send coins to several users
+// @Description	Send coins to several users at once. Either all transfers succeed or none of them.
+// @Tags		merch
+// @Accept 		json
+// @Produce		json
+// @Param input body models.SendCoinBatchRequest true "input"
+// @Success		200
+// @Failure		400	{object}	httphelpers.ErrorResponse	"bad request"
+// @Failure		401	{object}	httphelpers.ErrorResponse	"authentication required"
+// @Failure		500	{object}	httphelpers.ErrorResponse	"internal server error"
+// @Security 	JWT
+// @Router		/sendCoinBatch [post]
+func (h *merchHandlers) SendCoinBatch(c echo.Context) error {
+	userID, err := middleware.ContextGetUserID(c)
+	if err != nil {
+		return hh.ServerErrorResponse(c, h.logger, err)
+	}
+
+	var input m.SendCoinBatchRequest
 	if err := c.Bind(&input); err != nil {
 		return hh.BadRequestResponse(c, err)
 	}
@@ -79,9 +168,86 @@ func (h *merchHandlers) SendCoins(c echo.Context) error {
 		return hh.BadRequestResponse(c, err)
 	}
 
-	err = h.merchUC.SendCoins(c.Request().Context(), userID, input.ToUser, input.Amount)
+	err = h.merchUC.SendCoinBatch(c.Request().Context(), userID, input.Transfers)
+	if err != nil {
+		var limitErr *db.LimitExceededError
+		if errors.Is(err, db.ErrInsufficientFunds) || errors.Is(err, db.ErrIncorrectReciever) || errors.Is(err, db.ErrUserNotFound) || errors.Is(err, db.ErrUserDeactivated) || errors.Is(err, db.ErrDuplicateReciever) || errors.As(err, &limitErr) {
+			return hh.BadRequestResponse(c, err)
+		}
+		return hh.ServerErrorResponse(c, h.logger, err)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+// @Summary		Grant coins
+// @Description	Give new coins to a user. Available to admins and to service accounts with the coins:grant scope.
+// @Tags		merch
+// @Accept 		json
+// @Param input body models.GrantCoinsRequest true "input"
+// @Success		200
+// @Failure		400	{object}	httphelpers.ErrorResponse	"bad request"
+// @Failure		401	{object}	httphelpers.ErrorResponse	"authentication required"
+// @Failure		403	{object}	httphelpers.ErrorResponse	"not permitted"
+// @Failure		500	{object}	httphelpers.ErrorResponse	"internal server error"
+// @Security 	JWT
+// @Security 	APIKey
+// @Router		/grants [post]
+func (h *merchHandlers) GrantCoins(c echo.Context) error {
+	actor, err := middleware.ContextGetActor(c)
 	if err != nil {
-		if errors.Is(err, db.ErrInsufficientFunds) || errors.Is(err, db.ErrIncorrectReciever) || errors.Is(err, db.ErrUserNotFound) {
+		return hh.ServerErrorResponse(c, h.logger, err)
+	}
+
+	var input m.GrantCoinsRequest
+	if err := c.Bind(&input); err != nil {
+		return hh.BadRequestResponse(c, err)
+	}
+
+	if err := c.Validate(input); err != nil {
+		return hh.BadRequestResponse(c, err)
+	}
+
+	if err := h.merchUC.GrantCoins(c.Request().Context(), actor, input); err != nil {
+		if errors.Is(err, db.ErrUserNotFound) || errors.Is(err, db.ErrUserDeactivated) {
+			return hh.BadRequestResponse(c, err)
+		}
+		return hh.ServerErrorResponse(c, h.logger, err)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+// @Summary		Gift item
+// @Description	Give items from own inventory to another user
+// @Tags		merch
+// @Accept 		json
+// @Produce		json
+// @Param input body models.GiftItemRequest true "input"
+// @Success		200
+// @Failure		400	{object}	httphelpers.ErrorResponse	"bad request"
+// @Failure		401	{object}	httphelpers.ErrorResponse	"authentication required"
+// @Failure		500	{object}	httphelpers.ErrorResponse	"internal server error"
+// @Security 	JWT
+// @Router		/giftItem [post]
+func (h *merchHandlers) GiftItem(c echo.Context) error {
+	userID, err := middleware.ContextGetUserID(c)
+	if err != nil {
+		return hh.ServerErrorResponse(c, h.logger, err)
+	}
+
+	var input m.GiftItemRequest
+	if err := c.Bind(&input); err != nil {
+		return hh.BadRequestResponse(c, err)
+	}
+
+	if err := c.Validate(input); err != nil {
+		return hh.BadRequestResponse(c, err)
+	}
+
+	err = h.merchUC.GiftItem(c.Request().Context(), userID, input.ToUser, input.Item, input.Quantity)
+	if err != nil {
+		if errors.Is(err, db.ErrIncorrectReciever) || errors.Is(err, db.ErrUserNotFound) || errors.Is(err, db.ErrUserDeactivated) || errors.Is(err, db.ErrItemtNotFound) || errors.Is(err, db.ErrNotEnoughItems) {
 			return hh.BadRequestResponse(c, err)
 		}
 		return hh.ServerErrorResponse(c, h.logger, err)
@@ -94,10 +260,13 @@ func (h *merchHandlers) SendCoins(c echo.Context) error {
 // @Description	Buy an item from the store
 // @Tags		merch
 // @Produce		json
-// @Param   	item  path  string  true  "name of the item to buy"
+// @Param   	item  		path  	string  true  	"name of the item to buy"
+// @Param   	quantity  	query  	int  	false  	"number of units to buy, 1 by default"
+// @Param		Idempotency-Key	header	string	false	"unique key to safely retry the request"
 // @Success		200
 // @Failure		400	{object}	httphelpers.ErrorResponse	"bad request"
 // @Failure		401	{object}	httphelpers.ErrorResponse	"authentication required"
+// @Failure		409	{object}	httphelpers.ErrorResponse	"idempotency key reused"
 // @Failure		500	{object}	httphelpers.ErrorResponse	"internal server error"
 // @Security 	JWT
 // @Router		/buy/{item} [get]
@@ -107,15 +276,109 @@ func (h *merchHandlers) BuyItem(c echo.Context) error {
 		return hh.ServerErrorResponse(c, h.logger, err)
 	}
 
-	item := c.Param("item")
+	var input m.BuyItemRequest
+	if err := c.Bind(&input); err != nil {
+		return hh.BadRequestResponse(c, err)
+	}
+
+	if err := c.Validate(input); err != nil {
+		return hh.BadRequestResponse(c, err)
+	}
+
+	if input.Quantity == 0 {
+		input.Quantity = 1
+	}
 
-	err = h.merchUC.BuyItem(c.Request().Context(), userID, item)
+	idempotencyKey, err := hh.ReadIdempotencyKey(c)
 	if err != nil {
-		if errors.Is(err, db.ErrItemtNotFound) || errors.Is(err, db.ErrInsufficientFunds) {
+		return hh.BadRequestResponse(c, err)
+	}
+
+	err = h.merchUC.BuyItem(c.Request().Context(), userID, input.Item, input.Quantity, idempotencyKey)
+	if err != nil {
+		if errors.Is(err, db.ErrItemtNotFound) || errors.Is(err, db.ErrInsufficientFunds) || errors.Is(err, db.ErrOutOfStock) {
 			return hh.BadRequestResponse(c, err)
 		}
+		if errors.Is(err, usecase.ErrIdempotencyKeyReused) {
+			return hh.ConflictResponse(c, err)
+		}
 		return hh.ServerErrorResponse(c, h.logger, err)
 	}
 
 	return c.NoContent(http.StatusOK)
 }
+
+// @Summary		Refund order
+// @Description	Return items of user's own order and get the paid coins back. Only possible within the refund window.
+// @Tags		merch
+// @Produce		json
+// @Param		id	path	int	true	"order id"
+// @Success		200
+// @Failure		400	{object}	httphelpers.ErrorResponse	"bad request"
+// @Failure		401	{object}	httphelpers.ErrorResponse	"authentication required"
+// @Failure		404	{object}	httphelpers.ErrorResponse	"order not found"
+// @Failure		500	{object}	httphelpers.ErrorResponse	"internal server error"
+// @Security 	JWT
+// @Router		/orders/{id}/refund [post]
+func (h *merchHandlers) RefundOrder(c echo.Context) error {
+	userID, err := middleware.ContextGetUserID(c)
+	if err != nil {
+		return hh.ServerErrorResponse(c, h.logger, err)
+	}
+
+	orderID, err := hh.ReadIDParam(c)
+	if err != nil {
+		return hh.BadRequestResponse(c, err)
+	}
+
+	err = h.merchUC.RefundOrder(c.Request().Context(), userID, orderID)
+	if err != nil {
+		return h.refundErrorResponse(c, err)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+// @Summary		Refund any order
+// @Description	Return items of any user's order and give the paid coins back regardless of the refund window. Orders of deleted users can't be refunded.
+// @Tags		admin
+// @Produce		json
+// @Param		id	path	int	true	"order id"
+// @Success		200
+// @Failure		400	{object}	httphelpers.ErrorResponse	"bad request"
+// @Failure		401	{object}	httphelpers.ErrorResponse	"authentication required"
+// @Failure		403	{object}	httphelpers.ErrorResponse	"not permitted"
+// @Failure		404	{object}	httphelpers.ErrorResponse	"order not found"
+// @Failure		500	{object}	httphelpers.ErrorResponse	"internal server error"
+// @Security 	JWT
+// @Router		/admin/orders/{id}/refund [post]
+func (h *merchHandlers) AdminRefundOrder(c echo.Context) error {
+	adminID, err := middleware.ContextGetUserID(c)
+	if err != nil {
+		return hh.ServerErrorResponse(c, h.logger, err)
+	}
+
+	orderID, err := hh.ReadIDParam(c)
+	if err != nil {
+		return hh.BadRequestResponse(c, err)
+	}
+
+	err = h.merchUC.AdminRefundOrder(c.Request().Context(), adminID, orderID)
+	if err != nil {
+		return h.refundErrorResponse(c, err)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+// Map refund errors to responses
+func (h *merchHandlers) refundErrorResponse(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, db.ErrOrderNotFound):
+		return hh.NotFoundResponse(c, err)
+	case errors.Is(err, db.ErrAlreadyRefunded), errors.Is(err, db.ErrNotEnoughItems), errors.Is(err, db.ErrOrderOwnerDeleted),
+		errors.Is(err, usecase.ErrRefundWindowExpired):
+		return hh.BadRequestResponse(c, err)
+	}
+	return hh.ServerErrorResponse(c, h.logger, err)
+}
@@ -9,6 +9,20 @@ import (
 // Register merch routes
 func RegisterMerchRoutes(g *echo.Group, h merch.Handlers) {
 	g.GET("/info", h.GetInfo)
+	g.GET("/history", h.GetHistory)
 	g.POST("/sendCoin", h.SendCoins)
+	g.POST("/sendCoinBatch", h.SendCoinBatch)
+	g.POST("/giftItem", h.GiftItem)
 	g.GET("/buy/:item", h.BuyItem)
+	g.POST("/orders/:id/refund", h.RefundOrder)
+}
+
+// Register merch admin routes
+func RegisterMerchAdminRoutes(g *echo.Group, h merch.Handlers) {
+	g.POST("/orders/:id/refund", h.AdminRefundOrder)
+}
+
+// Register coin grant routes
+func RegisterMerchGrantRoutes(g *echo.Group, h merch.Handlers) {
+	g.POST("", h.GrantCoins)
 }
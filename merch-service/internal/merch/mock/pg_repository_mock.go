@@ -36,17 +36,31 @@ func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
 }
 
 // BuyItem mocks base method.
-func (m *MockRepository) BuyItem(ctx context.Context, userID int64, itemName string) error {
+func (m *MockRepository) BuyItem(ctx context.Context, userID int64, itemName string, quantity int64, key *models.IdempotencyKey) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "BuyItem", ctx, userID, itemName)
+	ret := m.ctrl.Call(m, "BuyItem", ctx, userID, itemName, quantity, key)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // BuyItem indicates an expected call of BuyItem.
-func (mr *MockRepositoryMockRecorder) BuyItem(ctx, userID, itemName interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) BuyItem(ctx, userID, itemName, quantity, key interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BuyItem", reflect.TypeOf((*MockRepository)(nil).BuyItem), ctx, userID, itemName)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BuyItem", reflect.TypeOf((*MockRepository)(nil).BuyItem), ctx, userID, itemName, quantity, key)
+}
+
+// BuyItems mocks base method.
+func (m *MockRepository) BuyItems(ctx context.Context, userID int64, items []models.CartItem) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BuyItems", ctx, userID, items)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BuyItems indicates an expected call of BuyItems.
+func (mr *MockRepositoryMockRecorder) BuyItems(ctx, userID, items interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BuyItems", reflect.TypeOf((*MockRepository)(nil).BuyItems), ctx, userID, items)
 }
 
 // GetCoinsAndInventory mocks base method.
@@ -64,19 +78,95 @@ func (mr *MockRepositoryMockRecorder) GetCoinsAndInventory(ctx, userID interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCoinsAndInventory", reflect.TypeOf((*MockRepository)(nil).GetCoinsAndInventory), ctx, userID)
 }
 
+// GetIdempotencyKey mocks base method.
+func (m *MockRepository) GetIdempotencyKey(ctx context.Context, userID int64, key string) (*models.IdempotencyRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIdempotencyKey", ctx, userID, key)
+	ret0, _ := ret[0].(*models.IdempotencyRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIdempotencyKey indicates an expected call of GetIdempotencyKey.
+func (mr *MockRepositoryMockRecorder) GetIdempotencyKey(ctx, userID, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIdempotencyKey", reflect.TypeOf((*MockRepository)(nil).GetIdempotencyKey), ctx, userID, key)
+}
+
+// GetOrder mocks base method.
+func (m *MockRepository) GetOrder(ctx context.Context, orderID int64) (*models.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrder", ctx, orderID)
+	ret0, _ := ret[0].(*models.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrder indicates an expected call of GetOrder.
+func (mr *MockRepositoryMockRecorder) GetOrder(ctx, orderID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrder", reflect.TypeOf((*MockRepository)(nil).GetOrder), ctx, orderID)
+}
+
+// GetPurchaseHistory mocks base method.
+func (m *MockRepository) GetPurchaseHistory(ctx context.Context, userID int64) ([]models.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPurchaseHistory", ctx, userID)
+	ret0, _ := ret[0].([]models.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPurchaseHistory indicates an expected call of GetPurchaseHistory.
+func (mr *MockRepositoryMockRecorder) GetPurchaseHistory(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPurchaseHistory", reflect.TypeOf((*MockRepository)(nil).GetPurchaseHistory), ctx, userID)
+}
+
 // GetTransactionHistory mocks base method.
-func (m *MockRepository) GetTransactionHistory(ctx context.Context, userID int64) (*models.TransactionHistory, error) {
+func (m *MockRepository) GetTransactionHistory(ctx context.Context, userID, limit int64) (*models.TransactionHistory, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTransactionHistory", ctx, userID)
+	ret := m.ctrl.Call(m, "GetTransactionHistory", ctx, userID, limit)
 	ret0, _ := ret[0].(*models.TransactionHistory)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetTransactionHistory indicates an expected call of GetTransactionHistory.
-func (mr *MockRepositoryMockRecorder) GetTransactionHistory(ctx, userID interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) GetTransactionHistory(ctx, userID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionHistory", reflect.TypeOf((*MockRepository)(nil).GetTransactionHistory), ctx, userID, limit)
+}
+
+// GetTransactions mocks base method.
+func (m *MockRepository) GetTransactions(ctx context.Context, userID int64, filter models.HistoryFilter, limit int64) ([]models.HistoryEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransactions", ctx, userID, filter, limit)
+	ret0, _ := ret[0].([]models.HistoryEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransactions indicates an expected call of GetTransactions.
+func (mr *MockRepositoryMockRecorder) GetTransactions(ctx, userID, filter, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactions", reflect.TypeOf((*MockRepository)(nil).GetTransactions), ctx, userID, filter, limit)
+}
+
+// GetTransferredToday mocks base method.
+func (m *MockRepository) GetTransferredToday(ctx context.Context, userID int64) (int64, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransferredToday", ctx, userID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetTransferredToday indicates an expected call of GetTransferredToday.
+func (mr *MockRepositoryMockRecorder) GetTransferredToday(ctx, userID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionHistory", reflect.TypeOf((*MockRepository)(nil).GetTransactionHistory), ctx, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransferredToday", reflect.TypeOf((*MockRepository)(nil).GetTransferredToday), ctx, userID)
 }
 
 // GetUserIDByUsername mocks base method.
@@ -94,16 +184,103 @@ func (mr *MockRepositoryMockRecorder) GetUserIDByUsername(ctx, username interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserIDByUsername", reflect.TypeOf((*MockRepository)(nil).GetUserIDByUsername), ctx, username)
 }
 
+// GiftItem mocks base method.
+func (m *MockRepository) GiftItem(ctx context.Context, fromUser int64, toUser, itemName string, quantity int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GiftItem", ctx, fromUser, toUser, itemName, quantity)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GiftItem indicates an expected call of GiftItem.
+func (mr *MockRepositoryMockRecorder) GiftItem(ctx, fromUser, toUser, itemName, quantity interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GiftItem", reflect.TypeOf((*MockRepository)(nil).GiftItem), ctx, fromUser, toUser, itemName, quantity)
+}
+
+// GrantCoins mocks base method.
+func (m *MockRepository) GrantCoins(ctx context.Context, actor models.Actor, grant models.GrantCoinsRequest) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GrantCoins", ctx, actor, grant)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GrantCoins indicates an expected call of GrantCoins.
+func (mr *MockRepositoryMockRecorder) GrantCoins(ctx, actor, grant interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GrantCoins", reflect.TypeOf((*MockRepository)(nil).GrantCoins), ctx, actor, grant)
+}
+
+// PayInvoice mocks base method.
+func (m *MockRepository) PayInvoice(ctx context.Context, fromUser, invoiceID int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PayInvoice", ctx, fromUser, invoiceID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PayInvoice indicates an expected call of PayInvoice.
+func (mr *MockRepositoryMockRecorder) PayInvoice(ctx, fromUser, invoiceID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PayInvoice", reflect.TypeOf((*MockRepository)(nil).PayInvoice), ctx, fromUser, invoiceID)
+}
+
+// RefundOrder mocks base method.
+func (m *MockRepository) RefundOrder(ctx context.Context, orderID, refundedBy int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefundOrder", ctx, orderID, refundedBy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RefundOrder indicates an expected call of RefundOrder.
+func (mr *MockRepositoryMockRecorder) RefundOrder(ctx, orderID, refundedBy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefundOrder", reflect.TypeOf((*MockRepository)(nil).RefundOrder), ctx, orderID, refundedBy)
+}
+
+// SaveIdempotencyFailure mocks base method.
+func (m *MockRepository) SaveIdempotencyFailure(ctx context.Context, userID int64, key *models.IdempotencyKey, failure *models.IdempotencyFailure) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveIdempotencyFailure", ctx, userID, key, failure)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveIdempotencyFailure indicates an expected call of SaveIdempotencyFailure.
+func (mr *MockRepositoryMockRecorder) SaveIdempotencyFailure(ctx, userID, key, failure interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveIdempotencyFailure", reflect.TypeOf((*MockRepository)(nil).SaveIdempotencyFailure), ctx, userID, key, failure)
+}
+
+// SendCoinBatch mocks base method.
+func (m *MockRepository) SendCoinBatch(ctx context.Context, fromUser int64, transfers []models.SendCoinRequest) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendCoinBatch", ctx, fromUser, transfers)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SendCoinBatch indicates an expected call of SendCoinBatch.
+func (mr *MockRepositoryMockRecorder) SendCoinBatch(ctx, fromUser, transfers interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendCoinBatch", reflect.TypeOf((*MockRepository)(nil).SendCoinBatch), ctx, fromUser, transfers)
+}
+
 // SendCoins mocks base method.
-func (m *MockRepository) SendCoins(ctx context.Context, fromUser int64, toUser string, amount int64) error {
+func (m *MockRepository) SendCoins(ctx context.Context, fromUser int64, transfer models.SendCoinRequest, key *models.IdempotencyKey) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SendCoins", ctx, fromUser, toUser, amount)
+	ret := m.ctrl.Call(m, "SendCoins", ctx, fromUser, transfer, key)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // SendCoins indicates an expected call of SendCoins.
-func (mr *MockRepositoryMockRecorder) SendCoins(ctx, fromUser, toUser, amount interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) SendCoins(ctx, fromUser, transfer, key interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendCoins", reflect.TypeOf((*MockRepository)(nil).SendCoins), ctx, fromUser, toUser, amount)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendCoins", reflect.TypeOf((*MockRepository)(nil).SendCoins), ctx, fromUser, transfer, key)
 }
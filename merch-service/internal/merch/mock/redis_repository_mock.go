@@ -49,6 +49,21 @@ func (mr *MockRedisRepositoryMockRecorder) DeleteInfo(ctx, key interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteInfo", reflect.TypeOf((*MockRedisRepository)(nil).DeleteInfo), ctx, key)
 }
 
+// GetIdempotencyKey mocks base method.
+func (m *MockRedisRepository) GetIdempotencyKey(ctx context.Context, key string) (*models.IdempotencyRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIdempotencyKey", ctx, key)
+	ret0, _ := ret[0].(*models.IdempotencyRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIdempotencyKey indicates an expected call of GetIdempotencyKey.
+func (mr *MockRedisRepositoryMockRecorder) GetIdempotencyKey(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIdempotencyKey", reflect.TypeOf((*MockRedisRepository)(nil).GetIdempotencyKey), ctx, key)
+}
+
 // GetInfo mocks base method.
 func (m *MockRedisRepository) GetInfo(ctx context.Context, key string) (*models.InfoResponse, error) {
 	m.ctrl.T.Helper()
@@ -64,6 +79,20 @@ func (mr *MockRedisRepositoryMockRecorder) GetInfo(ctx, key interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInfo", reflect.TypeOf((*MockRedisRepository)(nil).GetInfo), ctx, key)
 }
 
+// SetIdempotencyKey mocks base method.
+func (m *MockRedisRepository) SetIdempotencyKey(ctx context.Context, key string, record *models.IdempotencyRecord) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetIdempotencyKey", ctx, key, record)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetIdempotencyKey indicates an expected call of SetIdempotencyKey.
+func (mr *MockRedisRepositoryMockRecorder) SetIdempotencyKey(ctx, key, record interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetIdempotencyKey", reflect.TypeOf((*MockRedisRepository)(nil).SetIdempotencyKey), ctx, key, record)
+}
+
 // SetInfo mocks base method.
 func (m *MockRedisRepository) SetInfo(ctx context.Context, key string, info *models.InfoResponse) error {
 	m.ctrl.T.Helper()
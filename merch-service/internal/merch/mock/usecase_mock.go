@@ -0,0 +1,192 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/merch/usecase.go
+
+// Package mock_merch is a generated GoMock package.
+package mock_merch
+
+import (
+	context "context"
+	models "cyansnbrst/merch-service/internal/models"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockUseCase is a mock of UseCase interface.
+type MockUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockUseCaseMockRecorder
+}
+
+// MockUseCaseMockRecorder is the mock recorder for MockUseCase.
+type MockUseCaseMockRecorder struct {
+	mock *MockUseCase
+}
+
+// NewMockUseCase creates a new mock instance.
+func NewMockUseCase(ctrl *gomock.Controller) *MockUseCase {
+	mock := &MockUseCase{ctrl: ctrl}
+	mock.recorder = &MockUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUseCase) EXPECT() *MockUseCaseMockRecorder {
+	return m.recorder
+}
+
+// AdminRefundOrder mocks base method.
+func (m *MockUseCase) AdminRefundOrder(ctx context.Context, adminID, orderID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdminRefundOrder", ctx, adminID, orderID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AdminRefundOrder indicates an expected call of AdminRefundOrder.
+func (mr *MockUseCaseMockRecorder) AdminRefundOrder(ctx, adminID, orderID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdminRefundOrder", reflect.TypeOf((*MockUseCase)(nil).AdminRefundOrder), ctx, adminID, orderID)
+}
+
+// BuyItem mocks base method.
+func (m *MockUseCase) BuyItem(ctx context.Context, userID int64, item string, quantity int64, idempotencyKey string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BuyItem", ctx, userID, item, quantity, idempotencyKey)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BuyItem indicates an expected call of BuyItem.
+func (mr *MockUseCaseMockRecorder) BuyItem(ctx, userID, item, quantity, idempotencyKey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BuyItem", reflect.TypeOf((*MockUseCase)(nil).BuyItem), ctx, userID, item, quantity, idempotencyKey)
+}
+
+// BuyItems mocks base method.
+func (m *MockUseCase) BuyItems(ctx context.Context, userID int64, items []models.CartItem) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BuyItems", ctx, userID, items)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BuyItems indicates an expected call of BuyItems.
+func (mr *MockUseCaseMockRecorder) BuyItems(ctx, userID, items interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BuyItems", reflect.TypeOf((*MockUseCase)(nil).BuyItems), ctx, userID, items)
+}
+
+// GetHistory mocks base method.
+func (m *MockUseCase) GetHistory(ctx context.Context, userID int64, filter models.HistoryFilter) (*models.HistoryPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHistory", ctx, userID, filter)
+	ret0, _ := ret[0].(*models.HistoryPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHistory indicates an expected call of GetHistory.
+func (mr *MockUseCaseMockRecorder) GetHistory(ctx, userID, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHistory", reflect.TypeOf((*MockUseCase)(nil).GetHistory), ctx, userID, filter)
+}
+
+// GetInfo mocks base method.
+func (m *MockUseCase) GetInfo(ctx context.Context, userID int64) (*models.InfoResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInfo", ctx, userID)
+	ret0, _ := ret[0].(*models.InfoResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInfo indicates an expected call of GetInfo.
+func (mr *MockUseCaseMockRecorder) GetInfo(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInfo", reflect.TypeOf((*MockUseCase)(nil).GetInfo), ctx, userID)
+}
+
+// GiftItem mocks base method.
+func (m *MockUseCase) GiftItem(ctx context.Context, fromUserID int64, toUser, item string, quantity int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GiftItem", ctx, fromUserID, toUser, item, quantity)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GiftItem indicates an expected call of GiftItem.
+func (mr *MockUseCaseMockRecorder) GiftItem(ctx, fromUserID, toUser, item, quantity interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GiftItem", reflect.TypeOf((*MockUseCase)(nil).GiftItem), ctx, fromUserID, toUser, item, quantity)
+}
+
+// GrantCoins mocks base method.
+func (m *MockUseCase) GrantCoins(ctx context.Context, actor models.Actor, grant models.GrantCoinsRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GrantCoins", ctx, actor, grant)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GrantCoins indicates an expected call of GrantCoins.
+func (mr *MockUseCaseMockRecorder) GrantCoins(ctx, actor, grant interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GrantCoins", reflect.TypeOf((*MockUseCase)(nil).GrantCoins), ctx, actor, grant)
+}
+
+// PayInvoice mocks base method.
+func (m *MockUseCase) PayInvoice(ctx context.Context, fromUserID, invoiceID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PayInvoice", ctx, fromUserID, invoiceID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PayInvoice indicates an expected call of PayInvoice.
+func (mr *MockUseCaseMockRecorder) PayInvoice(ctx, fromUserID, invoiceID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PayInvoice", reflect.TypeOf((*MockUseCase)(nil).PayInvoice), ctx, fromUserID, invoiceID)
+}
+
+// RefundOrder mocks base method.
+func (m *MockUseCase) RefundOrder(ctx context.Context, userID, orderID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefundOrder", ctx, userID, orderID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RefundOrder indicates an expected call of RefundOrder.
+func (mr *MockUseCaseMockRecorder) RefundOrder(ctx, userID, orderID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefundOrder", reflect.TypeOf((*MockUseCase)(nil).RefundOrder), ctx, userID, orderID)
+}
+
+// SendCoinBatch mocks base method.
+func (m *MockUseCase) SendCoinBatch(ctx context.Context, fromUserID int64, transfers []models.SendCoinRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendCoinBatch", ctx, fromUserID, transfers)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendCoinBatch indicates an expected call of SendCoinBatch.
+func (mr *MockUseCaseMockRecorder) SendCoinBatch(ctx, fromUserID, transfers interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendCoinBatch", reflect.TypeOf((*MockUseCase)(nil).SendCoinBatch), ctx, fromUserID, transfers)
+}
+
+// SendCoins mocks base method.
+func (m *MockUseCase) SendCoins(ctx context.Context, fromUserID int64, transfer models.SendCoinRequest, idempotencyKey string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendCoins", ctx, fromUserID, transfer, idempotencyKey)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendCoins indicates an expected call of SendCoins.
+func (mr *MockUseCaseMockRecorder) SendCoins(ctx, fromUserID, transfer, idempotencyKey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendCoins", reflect.TypeOf((*MockUseCase)(nil).SendCoins), ctx, fromUserID, transfer, idempotencyKey)
+}
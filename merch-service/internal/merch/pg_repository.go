@@ -9,8 +9,20 @@ import (
 // Merch repository interface
 type Repository interface {
 	GetCoinsAndInventory(ctx context.Context, userID int64) (*m.CoinsInventory, error)
-	GetTransactionHistory(ctx context.Context, userID int64) (*m.TransactionHistory, error)
-	SendCoins(ctx context.Context, fromUser int64, toUser string, amount int64) error
-	BuyItem(ctx context.Context, userID int64, itemName string) error
+	GetTransactionHistory(ctx context.Context, userID, limit int64) (*m.TransactionHistory, error)
+	GetTransactions(ctx context.Context, userID int64, filter m.HistoryFilter, limit int64) ([]m.HistoryEntry, error)
+	GetPurchaseHistory(ctx context.Context, userID int64) ([]m.Order, error)
+	SendCoins(ctx context.Context, fromUser int64, transfer m.SendCoinRequest, key *m.IdempotencyKey) error
+	SendCoinBatch(ctx context.Context, fromUser int64, transfers []m.SendCoinRequest) ([]int64, error)
+	PayInvoice(ctx context.Context, fromUser, invoiceID int64) (int64, error)
+	GrantCoins(ctx context.Context, actor m.Actor, grant m.GrantCoinsRequest) (int64, error)
+	GiftItem(ctx context.Context, fromUser int64, toUser, itemName string, quantity int64) error
+	BuyItem(ctx context.Context, userID int64, itemName string, quantity int64, key *m.IdempotencyKey) error
+	BuyItems(ctx context.Context, userID int64, items []m.CartItem) error
 	GetUserIDByUsername(ctx context.Context, username string) (int64, error)
+	GetOrder(ctx context.Context, orderID int64) (*m.Order, error)
+	RefundOrder(ctx context.Context, orderID, refundedBy int64) error
+	GetTransferredToday(ctx context.Context, userID int64) (int64, int64, error)
+	GetIdempotencyKey(ctx context.Context, userID int64, key string) (*m.IdempotencyRecord, error)
+	SaveIdempotencyFailure(ctx context.Context, userID int64, key *m.IdempotencyKey, failure *m.IdempotencyFailure) error
 }
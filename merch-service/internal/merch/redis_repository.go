@@ -11,4 +11,6 @@ type RedisRepository interface {
 	GetInfo(ctx context.Context, key string) (*m.InfoResponse, error)
 	SetInfo(ctx context.Context, key string, info *m.InfoResponse) error
 	DeleteInfo(ctx context.Context, key string) error
+	GetIdempotencyKey(ctx context.Context, key string) (*m.IdempotencyRecord, error)
+	SetIdempotencyKey(ctx context.Context, key string, record *m.IdempotencyRecord) error
 }
@@ -4,23 +4,42 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
+	"cyansnbrst/merch-service/config"
 	"cyansnbrst/merch-service/internal/merch"
 	m "cyansnbrst/merch-service/internal/models"
 	"cyansnbrst/merch-service/pkg/db"
 )
 
+// Counterparties shown in history for granted coins and for deleted users
+const (
+	grantCounterparty   = "system"
+	deletedCounterparty = "deleted user"
+)
+
+// Runs queries both inside and outside of a transaction
+type querier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
 // Merch repository struct
 type merchRepo struct {
-	db *pgxpool.Pool
+	cfg *config.Config
+	db  *pgxpool.Pool
 }
 
 // Merch repository constructor
-func NewMerchRepo(db *pgxpool.Pool) merch.Repository {
-	return &merchRepo{db: db}
+func NewMerchRepo(cfg *config.Config, db *pgxpool.Pool) merch.Repository {
+	return &merchRepo{
+		cfg: cfg,
+		db:  db,
+	}
 }
 
 // Get user's main info
@@ -62,21 +81,23 @@ func (r *merchRepo) GetCoinsAndInventory(ctx context.Context, userID int64) (*m.
 	}, nil
 }
 
-// Get transaction history
-func (r *merchRepo) GetTransactionHistory(ctx context.Context, userID int64) (*m.TransactionHistory, error) {
+// Get most recent transactions
+func (r *merchRepo) GetTransactionHistory(ctx context.Context, userID, limit int64) (*m.TransactionHistory, error) {
 	query := `
-		SELECT 'received' as type, u.username, t.amount 
+		SELECT 'received' AS type, t.id, CASE WHEN t.kind = 'grant' THEN $3 ELSE COALESCE(u.username, $4) END, t.amount, t.comment, t.transaction_date
 		FROM transactions t 
-		JOIN users u ON t.from_id = u.id 
+		LEFT JOIN users u ON t.from_id = u.id 
 		WHERE t.to_id = $1
 		UNION ALL
-		SELECT 'sent', u.username, t.amount 
+		SELECT 'sent', t.id, COALESCE(u.username, $4), t.amount, t.comment, t.transaction_date
 		FROM transactions t 
-		JOIN users u ON t.to_id = u.id 
+		LEFT JOIN users u ON t.to_id = u.id 
 		WHERE t.from_id = $1
+		ORDER BY transaction_date DESC, id DESC
+		LIMIT $2
 	`
 
-	rows, err := r.db.Query(ctx, query, userID)
+	rows, err := r.db.Query(ctx, query, userID, limit, grantCounterparty, deletedCounterparty)
 	if err != nil {
 		return nil, fmt.Errorf("repo - failed to get transactions: %w", err)
 	}
@@ -90,24 +111,33 @@ func (r *merchRepo) GetTransactionHistory(ctx context.Context, userID int64) (*m
 	for rows.Next() {
 		var (
 			txType   string
+			id       int64
 			username string
 			amount   int64
+			comment  string
+			date     time.Time
 		)
 
-		if err := rows.Scan(&txType, &username, &amount); err != nil {
+		if err := rows.Scan(&txType, &id, &username, &amount, &comment, &date); err != nil {
 			return nil, fmt.Errorf("repo - failed to scan transaction: %w", err)
 		}
 
 		switch txType {
 		case "received":
 			history.Received = append(history.Received, m.ReceiveTransaction{
+				ID:       id,
 				FromUser: username,
 				Amount:   amount,
+				Comment:  comment,
+				Date:     date,
 			})
 		case "sent":
 			history.Sent = append(history.Sent, m.SendTransaction{
-				ToUser: username,
-				Amount: amount,
+				ID:      id,
+				ToUser:  username,
+				Amount:  amount,
+				Comment: comment,
+				Date:    date,
 			})
 		}
 	}
@@ -115,41 +145,240 @@ func (r *merchRepo) GetTransactionHistory(ctx context.Context, userID int64) (*m
 	return history, nil
 }
 
+// Get a page of transactions matching the filter, newest first
+func (r *merchRepo) GetTransactions(ctx context.Context, userID int64, filter m.HistoryFilter, limit int64) ([]m.HistoryEntry, error) {
+	query := `
+		SELECT id, direction, counterparty, amount, comment, transaction_date
+		FROM (
+			SELECT t.id, 'received' AS direction, CASE WHEN t.kind = 'grant' THEN $8 ELSE COALESCE(u.username, $9) END AS counterparty, t.amount, t.comment, t.transaction_date
+			FROM transactions t
+			LEFT JOIN users u ON t.from_id = u.id
+			WHERE t.to_id = $1
+			UNION ALL
+			SELECT t.id, 'sent', COALESCE(u.username, $9), t.amount, t.comment, t.transaction_date
+			FROM transactions t
+			LEFT JOIN users u ON t.to_id = u.id
+			WHERE t.from_id = $1
+		) h
+		WHERE ($2::bigint = 0 OR h.id < $2)
+			AND ($3::text = '' OR h.direction = $3)
+			AND ($4::text = '' OR h.counterparty = $4)
+			AND ($5::timestamptz IS NULL OR h.transaction_date >= $5)
+			AND ($6::timestamptz IS NULL OR h.transaction_date < $6)
+		ORDER BY h.id DESC
+		LIMIT $7
+	`
+
+	rows, err := r.db.Query(ctx, query,
+		userID,
+		filter.Cursor,
+		filter.Direction,
+		filter.Counterparty,
+		filter.From,
+		filter.To,
+		limit,
+		grantCounterparty,
+		deletedCounterparty,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("repo - failed to get transactions: %w", err)
+	}
+	defer rows.Close()
+
+	entries := make([]m.HistoryEntry, 0)
+	for rows.Next() {
+		var entry m.HistoryEntry
+		if err := rows.Scan(&entry.ID, &entry.Direction, &entry.Counterparty, &entry.Amount, &entry.Comment, &entry.Date); err != nil {
+			return nil, fmt.Errorf("repo - failed to scan transaction: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// Get purchase history
+func (r *merchRepo) GetPurchaseHistory(ctx context.Context, userID int64) ([]m.Order, error) {
+	query := `
+		SELECT o.id, i.name, o.price, o.quantity, o.created_at, o.refunded_at
+		FROM orders o
+		JOIN items i ON o.item_id = i.id
+		WHERE o.user_id = $1
+		ORDER BY o.created_at DESC, o.id DESC
+	`
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("repo - failed to get orders: %w", err)
+	}
+	defer rows.Close()
+
+	orders := make([]m.Order, 0)
+	for rows.Next() {
+		var order m.Order
+		if err := rows.Scan(&order.ID, &order.Item, &order.Price, &order.Quantity, &order.CreatedAt, &order.RefundedAt); err != nil {
+			return nil, fmt.Errorf("repo - failed to scan order: %w", err)
+		}
+		orders = append(orders, order)
+	}
+
+	return orders, nil
+}
+
 // Send coins to other user
-func (r *merchRepo) SendCoins(ctx context.Context, fromUser int64, toUser string, amount int64) error {
+func (r *merchRepo) SendCoins(ctx context.Context, fromUser int64, transfer m.SendCoinRequest, key *m.IdempotencyKey) error {
 	return r.execTx(ctx, func(tx pgx.Tx) error {
-		balanceQuery := `
-			SELECT u1.balance, u2.id
-			FROM users u1
-			JOIN users u2 ON u2.username = $1
-			WHERE u1.id = $2
+		if err := r.saveIdempotencyKey(ctx, tx, fromUser, key, nil); err != nil {
+			return err
+		}
+
+		_, err := r.sendCoins(ctx, tx, fromUser, transfer)
+		return err
+	})
+}
+
+// Pay pending invoice addressed to the user, marking it as accepted in the same transaction.
+// Returns ID of the user who requested the coins
+func (r *merchRepo) PayInvoice(ctx context.Context, fromUser, invoiceID int64) (int64, error) {
+	var toUserID int64
+	err := r.execTx(ctx, func(tx pgx.Tx) error {
+		query := `
+			UPDATE invoices i
+			SET status = 'accepted', resolved_at = CURRENT_TIMESTAMP
+			FROM users u
+			WHERE i.id = $1 AND i.from_id = $2 AND i.status = 'pending' AND u.id = i.to_id
+			RETURNING u.username, i.amount, i.comment
 		`
-		var currentBalance, toUserID int64
-		err := tx.QueryRow(ctx, balanceQuery, toUser, fromUser).Scan(&currentBalance, &toUserID)
+
+		var transfer m.SendCoinRequest
+		err := tx.QueryRow(ctx, query, invoiceID, fromUser).Scan(&transfer.ToUser, &transfer.Amount, &transfer.Comment)
 		if err != nil {
 			if err == pgx.ErrNoRows {
-				return db.ErrUserNotFound
+				return db.ErrInvoiceNotPending
 			}
-			return fmt.Errorf("repo - failed to get balance and recipient: %w", err)
+			return fmt.Errorf("repo - failed to accept invoice: %w", err)
 		}
 
-		if fromUser == toUserID {
-			return db.ErrIncorrectReciever
+		toUserID, err = r.sendCoins(ctx, tx, fromUser, transfer)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return toUserID, nil
+}
+
+// Send coins to several users at once, returning their IDs
+func (r *merchRepo) SendCoinBatch(ctx context.Context, fromUser int64, transfers []m.SendCoinRequest) ([]int64, error) {
+	toUserIDs := make([]int64, len(transfers))
+	err := r.execTx(ctx, func(tx pgx.Tx) error {
+		var err error
+		var total int64
+		for i, transfer := range transfers {
+			toUserIDs[i], err = r.getRecipientID(ctx, tx, fromUser, transfer.ToUser)
+			if err != nil {
+				return err
+			}
+			total += transfer.Amount
+		}
+
+		if err := r.lockUsers(ctx, tx, append([]int64{fromUser}, toUserIDs...)...); err != nil {
+			return err
+		}
+
+		var currentBalance int64
+		balanceQuery := `SELECT balance FROM users WHERE id = $1`
+		err = tx.QueryRow(ctx, balanceQuery, fromUser).Scan(&currentBalance)
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return db.ErrUserNotFound
+			}
+			return fmt.Errorf("repo - failed to get balance: %w", err)
 		}
 
-		if currentBalance < amount {
+		if currentBalance < total {
 			return db.ErrInsufficientFunds
 		}
 
-		if err := r.updateBalance(ctx, tx, fromUser, -amount); err != nil {
+		if err := r.checkTransferLimits(ctx, tx, fromUser, toUserIDs, transfers); err != nil {
 			return err
 		}
 
-		if err := r.updateBalance(ctx, tx, toUserID, amount); err != nil {
+		if err := r.updateBalance(ctx, tx, fromUser, -total); err != nil {
 			return err
 		}
 
-		if err := r.recordTransaction(ctx, tx, fromUser, toUserID, amount); err != nil {
+		for i, transfer := range transfers {
+			if err := r.updateBalance(ctx, tx, toUserIDs[i], transfer.Amount); err != nil {
+				return err
+			}
+
+			if err := r.recordTransaction(ctx, tx, m.Actor{Type: m.ActorUser, ID: fromUser}, &fromUser, toUserIDs[i], transfer.Amount, transfer.Comment); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toUserIDs, nil
+}
+
+// Grant new coins to a user, returning their ID
+func (r *merchRepo) GrantCoins(ctx context.Context, actor m.Actor, grant m.GrantCoinsRequest) (int64, error) {
+	var toUserID int64
+	err := r.execTx(ctx, func(tx pgx.Tx) error {
+		// Grants have no sender
+		var err error
+		toUserID, err = r.getRecipientID(ctx, tx, 0, grant.ToUser)
+		if err != nil {
+			return err
+		}
+
+		if err := r.updateBalance(ctx, tx, toUserID, grant.Amount); err != nil {
+			return err
+		}
+
+		return r.recordTransaction(ctx, tx, actor, nil, toUserID, grant.Amount, grant.Comment)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return toUserID, nil
+}
+
+// Gift items from inventory to other user
+func (r *merchRepo) GiftItem(ctx context.Context, fromUser int64, toUser, itemName string, quantity int64) error {
+	return r.execTx(ctx, func(tx pgx.Tx) error {
+		toUserID, err := r.getRecipientID(ctx, tx, fromUser, toUser)
+		if err != nil {
+			return err
+		}
+
+		var itemID int64
+		itemQuery := `SELECT id FROM items WHERE name = $1`
+		err = tx.QueryRow(ctx, itemQuery, itemName).Scan(&itemID)
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return db.ErrItemtNotFound
+			}
+			return fmt.Errorf("repo - failed to get item: %w", err)
+		}
+
+		if err := r.removeFromInventory(ctx, tx, fromUser, itemID, quantity); err != nil {
+			return err
+		}
+
+		if err := r.addToInventory(ctx, tx, toUserID, itemID, quantity); err != nil {
+			return err
+		}
+
+		if err := r.recordItemTransfer(ctx, tx, fromUser, toUserID, itemID, quantity); err != nil {
 			return err
 		}
 
@@ -158,45 +387,140 @@ func (r *merchRepo) SendCoins(ctx context.Context, fromUser int64, toUser string
 }
 
 // Buy an item
-func (r *merchRepo) BuyItem(ctx context.Context, userID int64, itemName string) error {
+func (r *merchRepo) BuyItem(ctx context.Context, userID int64, itemName string, quantity int64, key *m.IdempotencyKey) error {
+	return r.execTx(ctx, func(tx pgx.Tx) error {
+		if err := r.saveIdempotencyKey(ctx, tx, userID, key, nil); err != nil {
+			return err
+		}
+
+		return r.purchase(ctx, tx, userID, []m.CartItem{{Item: itemName, Quantity: quantity}})
+	})
+}
+
+// Buy several items at once
+func (r *merchRepo) BuyItems(ctx context.Context, userID int64, items []m.CartItem) error {
+	return r.execTx(ctx, func(tx pgx.Tx) error {
+		return r.purchase(ctx, tx, userID, items)
+	})
+}
+
+// Get order by ID
+func (r *merchRepo) GetOrder(ctx context.Context, orderID int64) (*m.Order, error) {
+	query := `
+		SELECT o.id, COALESCE(o.user_id, 0), i.name, o.price, o.quantity, o.created_at, o.refunded_at
+		FROM orders o
+		JOIN items i ON o.item_id = i.id
+		WHERE o.id = $1
+	`
+
+	var order m.Order
+	err := r.db.QueryRow(ctx, query, orderID).Scan(
+		&order.ID,
+		&order.UserID,
+		&order.Item,
+		&order.Price,
+		&order.Quantity,
+		&order.CreatedAt,
+		&order.RefundedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, db.ErrOrderNotFound
+		}
+		return nil, fmt.Errorf("repo - failed to get order: %w", err)
+	}
+
+	return &order, nil
+}
+
+// Refund an order, returning paid coins and taking items back
+func (r *merchRepo) RefundOrder(ctx context.Context, orderID, refundedBy int64) error {
 	return r.execTx(ctx, func(tx pgx.Tx) error {
 		query := `
-			SELECT i.id, i.price, u.balance
-			FROM items i
-			JOIN users u ON u.id = $1
-			WHERE i.name = $2
+			SELECT user_id, item_id, price, quantity, refunded_at
+			FROM orders
+			WHERE id = $1
+			FOR UPDATE
 		`
-		var itemID, price, balance int64
-		err := tx.QueryRow(ctx, query, userID, itemName).Scan(&itemID, &price, &balance)
+		var itemID, price, quantity int64
+		var userID *int64
+		var refundedAt *time.Time
+		err := tx.QueryRow(ctx, query, orderID).Scan(&userID, &itemID, &price, &quantity, &refundedAt)
 		if err != nil {
 			if err == pgx.ErrNoRows {
-				return db.ErrItemtNotFound
+				return db.ErrOrderNotFound
 			}
-			return fmt.Errorf("repo - failed to get item and balance: %w", err)
+			return fmt.Errorf("repo - failed to get order: %w", err)
 		}
 
-		if balance < price {
-			return db.ErrInsufficientFunds
+		if refundedAt != nil {
+			return db.ErrAlreadyRefunded
+		}
+
+		// Nobody to give the coins back to
+		if userID == nil {
+			return db.ErrOrderOwnerDeleted
+		}
+
+		if err := r.removeFromInventory(ctx, tx, *userID, itemID, quantity); err != nil {
+			return err
+		}
+
+		if err := r.restock(ctx, tx, itemID, quantity); err != nil {
+			return err
 		}
 
-		if err := r.updateBalance(ctx, tx, userID, -price); err != nil {
+		if err := r.updateBalance(ctx, tx, *userID, price*quantity); err != nil {
 			return err
 		}
 
-		upsertInventoryQuery := `
-			INSERT INTO inventory_items (user_id, item_id, quantity)
-			VALUES ($1, $2, 1)
-			ON CONFLICT (user_id, item_id) DO UPDATE SET quantity = inventory_items.quantity + 1
+		refundQuery := `
+			UPDATE orders
+			SET refunded_at = CURRENT_TIMESTAMP, refunded_by = $1
+			WHERE id = $2
 		`
-		_, err = tx.Exec(ctx, upsertInventoryQuery, userID, itemID)
+		_, err = tx.Exec(ctx, refundQuery, refundedBy, orderID)
 		if err != nil {
-			return fmt.Errorf("repo - failed to update inventory: %w", err)
+			return fmt.Errorf("repo - failed to mark order as refunded: %w", err)
 		}
 
 		return nil
 	})
 }
 
+// Get coins sent and received by the user today
+func (r *merchRepo) GetTransferredToday(ctx context.Context, userID int64) (int64, int64, error) {
+	return r.getTransferredToday(ctx, r.db, userID)
+}
+
+// Get outcome of the request completed with the idempotency key
+func (r *merchRepo) GetIdempotencyKey(ctx context.Context, userID int64, key string) (*m.IdempotencyRecord, error) {
+	var record m.IdempotencyRecord
+
+	query := `
+		SELECT fingerprint, status, response
+		FROM idempotency_keys
+		WHERE user_id = $1 AND key = $2 AND created_at >= $3
+	`
+	err := r.db.QueryRow(ctx, query, userID, key, r.idempotencyExpiry()).Scan(
+		&record.Fingerprint,
+		&record.Status,
+		&record.Response,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("repo - failed to get idempotency key: %w", err)
+	}
+
+	return &record, nil
+}
+
+// Save idempotency key of the failed request, so retries get the same error
+func (r *merchRepo) SaveIdempotencyFailure(ctx context.Context, userID int64, key *m.IdempotencyKey, failure *m.IdempotencyFailure) error {
+	return r.execTx(ctx, func(tx pgx.Tx) error {
+		return r.saveIdempotencyKey(ctx, tx, userID, key, failure)
+	})
+}
+
 // Execute a transaction
 func (r *merchRepo) execTx(ctx context.Context, fn func(tx pgx.Tx) error) error {
 	tx, err := r.db.Begin(ctx)
@@ -222,6 +546,195 @@ func (r *merchRepo) execTx(ctx context.Context, fn func(tx pgx.Tx) error) error
 	return nil
 }
 
+// Lock users' rows in a fixed order to avoid deadlocks
+func (r *merchRepo) lockUsers(ctx context.Context, tx pgx.Tx, userIDs ...int64) error {
+	query := `
+		SELECT id
+		FROM users
+		WHERE id = ANY($1)
+		ORDER BY id
+		FOR UPDATE
+	`
+	rows, err := tx.Query(ctx, query, userIDs)
+	if err != nil {
+		return fmt.Errorf("repo - failed to lock users: %w", err)
+	}
+	rows.Close()
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("repo - failed to lock users: %w", err)
+	}
+	return nil
+}
+
+// Check configured transfer limits for the sender and every recipient
+func (r *merchRepo) checkTransferLimits(ctx context.Context, tx pgx.Tx, fromUser int64, toUserIDs []int64, transfers []m.SendCoinRequest) error {
+	limits := r.cfg.App
+
+	var total int64
+	for _, transfer := range transfers {
+		if limits.MaxTransferAmount > 0 && transfer.Amount > limits.MaxTransferAmount {
+			return &db.LimitExceededError{Limit: "per transfer", Remaining: limits.MaxTransferAmount}
+		}
+		total += transfer.Amount
+	}
+
+	if limits.DailySendLimit > 0 {
+		sent, _, err := r.getTransferredToday(ctx, tx, fromUser)
+		if err != nil {
+			return err
+		}
+
+		if remaining := limits.DailySendLimit - sent; total > remaining {
+			return &db.LimitExceededError{Limit: "daily send", Remaining: max(remaining, 0)}
+		}
+	}
+
+	if limits.DailyReceiveLimit > 0 {
+		for i, toUserID := range toUserIDs {
+			_, received, err := r.getTransferredToday(ctx, tx, toUserID)
+			if err != nil {
+				return err
+			}
+
+			if remaining := limits.DailyReceiveLimit - received; transfers[i].Amount > remaining {
+				return &db.LimitExceededError{Limit: transfers[i].ToUser + "'s daily receive", Remaining: max(remaining, 0)}
+			}
+		}
+	}
+
+	return nil
+}
+
+// Sum coins sent and received by the user since the start of the day, grants are not limited
+func (r *merchRepo) getTransferredToday(ctx context.Context, q querier, userID int64) (int64, int64, error) {
+	query := `
+		SELECT
+			COALESCE(SUM(amount) FILTER (WHERE from_id = $1), 0),
+			COALESCE(SUM(amount) FILTER (WHERE to_id = $1), 0)
+		FROM transactions
+		WHERE (from_id = $1 OR to_id = $1)
+			AND kind = 'transfer'
+			AND transaction_date >= date_trunc('day', CURRENT_TIMESTAMP)
+	`
+
+	var sent, received int64
+	if err := q.QueryRow(ctx, query, userID).Scan(&sent, &received); err != nil {
+		return 0, 0, fmt.Errorf("repo - failed to get today's transfers: %w", err)
+	}
+
+	return sent, received, nil
+}
+
+// Move coins to the recipient inside the transaction, returning recipient's ID
+func (r *merchRepo) sendCoins(ctx context.Context, tx pgx.Tx, fromUser int64, transfer m.SendCoinRequest) (int64, error) {
+	toUserID, err := r.getRecipientID(ctx, tx, fromUser, transfer.ToUser)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := r.lockUsers(ctx, tx, fromUser, toUserID); err != nil {
+		return 0, err
+	}
+
+	balanceQuery := `SELECT balance FROM users WHERE id = $1`
+	var currentBalance int64
+	err = tx.QueryRow(ctx, balanceQuery, fromUser).Scan(&currentBalance)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return 0, db.ErrUserNotFound
+		}
+		return 0, fmt.Errorf("repo - failed to get balance: %w", err)
+	}
+
+	if currentBalance < transfer.Amount {
+		return 0, db.ErrInsufficientFunds
+	}
+
+	err = r.checkTransferLimits(ctx, tx, fromUser, []int64{toUserID}, []m.SendCoinRequest{transfer})
+	if err != nil {
+		return 0, err
+	}
+
+	if err := r.updateBalance(ctx, tx, fromUser, -transfer.Amount); err != nil {
+		return 0, err
+	}
+
+	if err := r.updateBalance(ctx, tx, toUserID, transfer.Amount); err != nil {
+		return 0, err
+	}
+
+	if err := r.recordTransaction(ctx, tx, m.Actor{Type: m.ActorUser, ID: fromUser}, &fromUser, toUserID, transfer.Amount, transfer.Comment); err != nil {
+		return 0, err
+	}
+
+	return toUserID, nil
+}
+
+// Get recipient's ID, making sure it's not the sender and the account is active
+func (r *merchRepo) getRecipientID(ctx context.Context, tx pgx.Tx, fromUser int64, toUser string) (int64, error) {
+	var (
+		toUserID    int64
+		deactivated bool
+	)
+	query := `SELECT id, deactivated_at IS NOT NULL FROM users WHERE username = $1`
+	err := tx.QueryRow(ctx, query, toUser).Scan(&toUserID, &deactivated)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return 0, db.ErrUserNotFound
+		}
+		return 0, fmt.Errorf("repo - failed to get recipient: %w", err)
+	}
+
+	if fromUser == toUserID {
+		return 0, db.ErrIncorrectReciever
+	}
+
+	if deactivated {
+		return 0, db.ErrUserDeactivated
+	}
+
+	return toUserID, nil
+}
+
+// Save idempotency key with the failure of the request or nil on success, failing if it was already used.
+// Concurrent requests with the same key wait here until the first one finishes.
+// User's expired keys are pruned first, so they can be used again.
+func (r *merchRepo) saveIdempotencyKey(ctx context.Context, tx pgx.Tx, userID int64, key *m.IdempotencyKey, failure *m.IdempotencyFailure) error {
+	if key == nil {
+		return nil
+	}
+
+	pruneQuery := `DELETE FROM idempotency_keys WHERE user_id = $1 AND created_at < $2`
+	if _, err := tx.Exec(ctx, pruneQuery, userID, r.idempotencyExpiry()); err != nil {
+		return fmt.Errorf("repo - failed to prune idempotency keys: %w", err)
+	}
+
+	status := m.IdempotencySucceeded
+	if failure != nil {
+		status = m.IdempotencyFailed
+	}
+
+	query := `
+		INSERT INTO idempotency_keys (user_id, key, fingerprint, status, response)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id, key) DO NOTHING
+	`
+	tag, err := tx.Exec(ctx, query, userID, key.Key, key.Fingerprint, status, failure)
+	if err != nil {
+		return fmt.Errorf("repo - failed to save idempotency key: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return db.ErrDuplicateRequest
+	}
+	return nil
+}
+
+// Keys created before this moment are expired, matching their cache TTL
+func (r *merchRepo) idempotencyExpiry() time.Time {
+	return time.Now().Add(-r.cfg.Redis.IdempotencyTTL)
+}
+
 // Update user's balance
 func (r *merchRepo) updateBalance(ctx context.Context, tx pgx.Tx, userID, amount int64) error {
 	query := `
@@ -236,19 +749,193 @@ func (r *merchRepo) updateBalance(ctx context.Context, tx pgx.Tx, userID, amount
 	return nil
 }
 
-// Record coin transaction
-func (r *merchRepo) recordTransaction(ctx context.Context, tx pgx.Tx, fromUser, toUser, amount int64) error {
+// Purchase item lines, charging the total price at once
+func (r *merchRepo) purchase(ctx context.Context, tx pgx.Tx, userID int64, items []m.CartItem) error {
+	var balance int64
+	balanceQuery := `SELECT balance FROM users WHERE id = $1 FOR UPDATE`
+	err := tx.QueryRow(ctx, balanceQuery, userID).Scan(&balance)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return db.ErrUserNotFound
+		}
+		return fmt.Errorf("repo - failed to get balance: %w", err)
+	}
+
+	// Lock items in the same order for every purchase to avoid deadlocks
+	lines := slices.Clone(items)
+	slices.SortFunc(lines, func(a, b m.CartItem) int {
+		return strings.Compare(a.Item, b.Item)
+	})
+
+	itemQuery := `
+		SELECT id, price, stock
+		FROM items
+		WHERE name = $1 AND retired_at IS NULL
+		FOR UPDATE
+	`
+	itemIDs := make([]int64, len(lines))
+	prices := make([]int64, len(lines))
+	stocks := make([]*int64, len(lines))
+	var total int64
+	for i, line := range lines {
+		err := tx.QueryRow(ctx, itemQuery, line.Item).Scan(&itemIDs[i], &prices[i], &stocks[i])
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return db.ErrItemtNotFound
+			}
+			return fmt.Errorf("repo - failed to get item: %w", err)
+		}
+
+		if stocks[i] != nil && *stocks[i] < line.Quantity {
+			return db.ErrOutOfStock
+		}
+
+		total += prices[i] * line.Quantity
+	}
+
+	if balance < total {
+		return db.ErrInsufficientFunds
+	}
+
+	if err := r.updateBalance(ctx, tx, userID, -total); err != nil {
+		return err
+	}
+
+	for i, line := range lines {
+		if stocks[i] != nil {
+			if err := r.decrementStock(ctx, tx, itemIDs[i], line.Quantity); err != nil {
+				return err
+			}
+		}
+
+		if err := r.addToInventory(ctx, tx, userID, itemIDs[i], line.Quantity); err != nil {
+			return err
+		}
+
+		if err := r.recordOrder(ctx, tx, userID, itemIDs[i], prices[i], line.Quantity); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Add items to user's inventory
+func (r *merchRepo) addToInventory(ctx context.Context, tx pgx.Tx, userID, itemID, quantity int64) error {
 	query := `
-		INSERT INTO transactions (from_id, to_id, amount)
+		INSERT INTO inventory_items (user_id, item_id, quantity)
 		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, item_id) DO UPDATE SET quantity = inventory_items.quantity + EXCLUDED.quantity
+	`
+	_, err := tx.Exec(ctx, query, userID, itemID, quantity)
+	if err != nil {
+		return fmt.Errorf("repo - failed to update inventory: %w", err)
+	}
+	return nil
+}
+
+// Record purchase order
+func (r *merchRepo) recordOrder(ctx context.Context, tx pgx.Tx, userID, itemID, price, quantity int64) error {
+	query := `
+		INSERT INTO orders (user_id, item_id, price, quantity)
+		VALUES ($1, $2, $3, $4)
 	`
-	_, err := tx.Exec(ctx, query, fromUser, toUser, amount)
+	_, err := tx.Exec(ctx, query, userID, itemID, price, quantity)
+	if err != nil {
+		return fmt.Errorf("repo - failed to record order: %w", err)
+	}
+	return nil
+}
+
+// Remove items from user's inventory
+func (r *merchRepo) removeFromInventory(ctx context.Context, tx pgx.Tx, userID, itemID, quantity int64) error {
+	deleteQuery := `
+		DELETE FROM inventory_items
+		WHERE user_id = $1 AND item_id = $2 AND quantity = $3
+	`
+	tag, err := tx.Exec(ctx, deleteQuery, userID, itemID, quantity)
+	if err != nil {
+		return fmt.Errorf("repo - failed to update inventory: %w", err)
+	}
+	if tag.RowsAffected() > 0 {
+		return nil
+	}
+
+	updateQuery := `
+		UPDATE inventory_items
+		SET quantity = quantity - $3
+		WHERE user_id = $1 AND item_id = $2 AND quantity > $3
+	`
+	tag, err = tx.Exec(ctx, updateQuery, userID, itemID, quantity)
+	if err != nil {
+		return fmt.Errorf("repo - failed to update inventory: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return db.ErrNotEnoughItems
+	}
+
+	return nil
+}
+
+// Return items to stock if it's limited
+func (r *merchRepo) restock(ctx context.Context, tx pgx.Tx, itemID, quantity int64) error {
+	query := `
+		UPDATE items
+		SET stock = stock + $1
+		WHERE id = $2 AND stock IS NOT NULL
+	`
+	_, err := tx.Exec(ctx, query, quantity, itemID)
+	if err != nil {
+		return fmt.Errorf("repo - failed to update stock: %w", err)
+	}
+	return nil
+}
+
+// Decrement item's stock
+func (r *merchRepo) decrementStock(ctx context.Context, tx pgx.Tx, itemID, quantity int64) error {
+	query := `
+		UPDATE items
+		SET stock = stock - $1
+		WHERE id = $2
+	`
+	_, err := tx.Exec(ctx, query, quantity, itemID)
+	if err != nil {
+		return fmt.Errorf("repo - failed to update stock: %w", err)
+	}
+	return nil
+}
+
+// Record coin transaction made by the actor, a transaction without a sender is a grant
+func (r *merchRepo) recordTransaction(ctx context.Context, tx pgx.Tx, actor m.Actor, fromUser *int64, toUser, amount int64, comment string) error {
+	kind := "transfer"
+	if fromUser == nil {
+		kind = "grant"
+	}
+
+	query := `
+		INSERT INTO transactions (from_id, to_id, amount, comment, kind, actor_type, actor_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	_, err := tx.Exec(ctx, query, fromUser, toUser, amount, comment, kind, actor.Type, actor.ID)
 	if err != nil {
 		return fmt.Errorf("repo - failed to record transaction: %w", err)
 	}
 	return nil
 }
 
+// Record item transfer
+func (r *merchRepo) recordItemTransfer(ctx context.Context, tx pgx.Tx, fromUser, toUser, itemID, quantity int64) error {
+	query := `
+		INSERT INTO item_transfers (from_id, to_id, item_id, quantity)
+		VALUES ($1, $2, $3, $4)
+	`
+	_, err := tx.Exec(ctx, query, fromUser, toUser, itemID, quantity)
+	if err != nil {
+		return fmt.Errorf("repo - failed to record item transfer: %w", err)
+	}
+	return nil
+}
+
 // Get user ID by username
 func (r *merchRepo) GetUserIDByUsername(ctx context.Context, username string) (int64, error) {
 	var userID int64
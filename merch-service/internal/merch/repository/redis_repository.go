@@ -63,3 +63,34 @@ func (r *merchRedisRepo) DeleteInfo(ctx context.Context, key string) error {
 	}
 	return nil
 }
+
+// Get outcome of the request completed with the idempotency key
+func (r *merchRedisRepo) GetIdempotencyKey(ctx context.Context, key string) (*m.IdempotencyRecord, error) {
+	recordBytes, err := r.redisClient.Get(ctx, key).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var record m.IdempotencyRecord
+	if err = json.Unmarshal(recordBytes, &record); err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Remember outcome of the request completed with the idempotency key
+func (r *merchRedisRepo) SetIdempotencyKey(ctx context.Context, key string, record *m.IdempotencyRecord) error {
+	recordBytes, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	if err = r.redisClient.Set(ctx, key, recordBytes, r.cfg.Redis.IdempotencyTTL).Err(); err != nil {
+		return err
+	}
+	return nil
+}
@@ -9,6 +9,14 @@ import (
 // Merch usecase interface
 type UseCase interface {
 	GetInfo(ctx context.Context, userID int64) (*m.InfoResponse, error)
-	SendCoins(ctx context.Context, fromUserID int64, toUser string, amount int64) error
-	BuyItem(ctx context.Context, userID int64, item string) error
+	GetHistory(ctx context.Context, userID int64, filter m.HistoryFilter) (*m.HistoryPage, error)
+	SendCoins(ctx context.Context, fromUserID int64, transfer m.SendCoinRequest, idempotencyKey string) error
+	SendCoinBatch(ctx context.Context, fromUserID int64, transfers []m.SendCoinRequest) error
+	PayInvoice(ctx context.Context, fromUserID, invoiceID int64) error
+	GrantCoins(ctx context.Context, actor m.Actor, grant m.GrantCoinsRequest) error
+	GiftItem(ctx context.Context, fromUserID int64, toUser, item string, quantity int64) error
+	BuyItem(ctx context.Context, userID int64, item string, quantity int64, idempotencyKey string) error
+	BuyItems(ctx context.Context, userID int64, items []m.CartItem) error
+	RefundOrder(ctx context.Context, userID, orderID int64) error
+	AdminRefundOrder(ctx context.Context, adminID, orderID int64) error
 }
@@ -2,28 +2,75 @@ package usecase
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
 
+	"cyansnbrst/merch-service/config"
+	"cyansnbrst/merch-service/internal/catalog"
 	"cyansnbrst/merch-service/internal/merch"
 	m "cyansnbrst/merch-service/internal/models"
+	"cyansnbrst/merch-service/pkg/db"
 	"cyansnbrst/merch-service/pkg/db/redis"
 )
 
+var (
+	ErrRefundWindowExpired  = errors.New("refund window has expired")
+	ErrIdempotencyKeyReused = errors.New("idempotency key was already used for a different request")
+)
+
+// History page size when the filter has no limit
+const defaultHistoryLimit = 20
+
+// Business errors stored with the idempotency key and replayed on retries
+var replayableErrors = []error{
+	db.ErrInsufficientFunds,
+	db.ErrOutOfStock,
+	db.ErrItemtNotFound,
+	db.ErrUserNotFound,
+	db.ErrUserDeactivated,
+	db.ErrIncorrectReciever,
+}
+
 // Merch usecase struct
 type merchUC struct {
-	merchRepo      merch.Repository
-	merchRedisRepo merch.RedisRepository
+	cfg              *config.Config
+	merchRepo        merch.Repository
+	merchRedisRepo   merch.RedisRepository
+	catalogRedisRepo catalog.RedisRepository
 }
 
 // Merch usecase constructor
-func NewMerchUseCase(merchRepo merch.Repository, merchRedisRepo merch.RedisRepository) merch.UseCase {
+func NewMerchUseCase(cfg *config.Config, merchRepo merch.Repository, merchRedisRepo merch.RedisRepository, catalogRedisRepo catalog.RedisRepository) merch.UseCase {
 	return &merchUC{
-		merchRepo:      merchRepo,
-		merchRedisRepo: merchRedisRepo,
+		cfg:              cfg,
+		merchRepo:        merchRepo,
+		merchRedisRepo:   merchRedisRepo,
+		catalogRedisRepo: catalogRedisRepo,
 	}
 }
 
 // Get user's info
 func (u *merchUC) GetInfo(ctx context.Context, userID int64) (*m.InfoResponse, error) {
+	info, err := u.getCachedInfo(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Allowance depends on the current day, so it's never cached
+	allowance, err := u.getAllowance(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	info.Allowance = allowance
+
+	return info, nil
+}
+
+// Get user's info from the cache, loading it from the database on miss
+func (u *merchUC) getCachedInfo(ctx context.Context, userID int64) (*m.InfoResponse, error) {
 	key := redis.GetUserInfoCacheKey(userID)
 
 	cachedInfo, err := u.merchRedisRepo.GetInfo(ctx, key)
@@ -40,7 +87,12 @@ func (u *merchUC) GetInfo(ctx context.Context, userID int64) (*m.InfoResponse, e
 		return nil, err
 	}
 
-	coinHistory, err := u.merchRepo.GetTransactionHistory(ctx, userID)
+	coinHistory, err := u.merchRepo.GetTransactionHistory(ctx, userID, u.cfg.App.InfoHistorySize)
+	if err != nil {
+		return nil, err
+	}
+
+	purchaseHistory, err := u.merchRepo.GetPurchaseHistory(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -50,7 +102,8 @@ func (u *merchUC) GetInfo(ctx context.Context, userID int64) (*m.InfoResponse, e
 			Coins:     coinsInventory.Coins,
 			Inventory: coinsInventory.Inventory,
 		},
-		CoinHistory: coinHistory,
+		CoinHistory:     coinHistory,
+		PurchaseHistory: purchaseHistory,
 	}
 
 	if err := u.merchRedisRepo.SetInfo(ctx, key, info); err != nil {
@@ -60,18 +113,146 @@ func (u *merchUC) GetInfo(ctx context.Context, userID int64) (*m.InfoResponse, e
 	return info, nil
 }
 
+// Get coins user can still send and receive today
+func (u *merchUC) getAllowance(ctx context.Context, userID int64) (*m.TransferAllowance, error) {
+	limits := u.cfg.App
+	if limits.DailySendLimit == 0 && limits.DailyReceiveLimit == 0 {
+		return nil, nil
+	}
+
+	sent, received, err := u.merchRepo.GetTransferredToday(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	allowance := &m.TransferAllowance{}
+	if limits.DailySendLimit > 0 {
+		send := max(limits.DailySendLimit-sent, 0)
+		allowance.Send = &send
+	}
+	if limits.DailyReceiveLimit > 0 {
+		receive := max(limits.DailyReceiveLimit-received, 0)
+		allowance.Receive = &receive
+	}
+
+	return allowance, nil
+}
+
+// Get a page of user's transaction history
+func (u *merchUC) GetHistory(ctx context.Context, userID int64, filter m.HistoryFilter) (*m.HistoryPage, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = defaultHistoryLimit
+	}
+
+	// Fetch one extra entry to know whether there is a next page
+	entries, err := u.merchRepo.GetTransactions(ctx, userID, filter, filter.Limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &m.HistoryPage{Transactions: entries}
+	if int64(len(entries)) > filter.Limit {
+		page.Transactions = entries[:filter.Limit]
+		nextCursor := page.Transactions[filter.Limit-1].ID
+		page.NextCursor = &nextCursor
+	}
+
+	return page, nil
+}
+
 // Send coins to other user
-func (u *merchUC) SendCoins(ctx context.Context, fromUserID int64, toUser string, amount int64) error {
-	if err := u.merchRepo.SendCoins(ctx, fromUserID, toUser, amount); err != nil {
+func (u *merchUC) SendCoins(ctx context.Context, fromUserID int64, transfer m.SendCoinRequest, idempotencyKey string) error {
+	key := newIdempotencyKey(idempotencyKey, "sendCoin", transfer.ToUser, transfer.Amount, transfer.Comment)
+
+	completed, err := u.isCompleted(ctx, fromUserID, key)
+	if err != nil || completed {
 		return err
 	}
 
+	if err := u.merchRepo.SendCoins(ctx, fromUserID, transfer, key); err != nil {
+		return u.fail(ctx, fromUserID, key, err)
+	}
+
 	fromUserKey := redis.GetUserInfoCacheKey(fromUserID)
 	if err := u.merchRedisRepo.DeleteInfo(ctx, fromUserKey); err != nil {
 
 		return err
 	}
 
+	toUserID, err := u.merchRepo.GetUserIDByUsername(ctx, transfer.ToUser)
+	if err != nil {
+		return err
+	}
+
+	toUserKey := redis.GetUserInfoCacheKey(toUserID)
+	if err := u.merchRedisRepo.DeleteInfo(ctx, toUserKey); err != nil {
+		return err
+	}
+
+	return u.complete(ctx, fromUserID, key, nil)
+}
+
+// Send coins to several users in one transaction
+func (u *merchUC) SendCoinBatch(ctx context.Context, fromUserID int64, transfers []m.SendCoinRequest) error {
+	recipients := make(map[string]struct{}, len(transfers))
+	for _, transfer := range transfers {
+		if _, ok := recipients[transfer.ToUser]; ok {
+			return db.ErrDuplicateReciever
+		}
+		recipients[transfer.ToUser] = struct{}{}
+	}
+
+	toUserIDs, err := u.merchRepo.SendCoinBatch(ctx, fromUserID, transfers)
+	if err != nil {
+		return err
+	}
+
+	for _, userID := range append([]int64{fromUserID}, toUserIDs...) {
+		if err := u.merchRedisRepo.DeleteInfo(ctx, redis.GetUserInfoCacheKey(userID)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Pay the invoice addressed to the user, accepting it together with the transfer
+func (u *merchUC) PayInvoice(ctx context.Context, fromUserID, invoiceID int64) error {
+	toUserID, err := u.merchRepo.PayInvoice(ctx, fromUserID, invoiceID)
+	if err != nil {
+		return err
+	}
+
+	for _, userID := range []int64{fromUserID, toUserID} {
+		if err := u.merchRedisRepo.DeleteInfo(ctx, redis.GetUserInfoCacheKey(userID)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Grant new coins to a user on behalf of an admin or a service account
+func (u *merchUC) GrantCoins(ctx context.Context, actor m.Actor, grant m.GrantCoinsRequest) error {
+	toUserID, err := u.merchRepo.GrantCoins(ctx, actor, grant)
+	if err != nil {
+		return err
+	}
+
+	return u.merchRedisRepo.DeleteInfo(ctx, redis.GetUserInfoCacheKey(toUserID))
+}
+
+// Gift items from inventory to other user
+func (u *merchUC) GiftItem(ctx context.Context, fromUserID int64, toUser, item string, quantity int64) error {
+	if err := u.merchRepo.GiftItem(ctx, fromUserID, toUser, item, quantity); err != nil {
+		return err
+	}
+
+	fromUserKey := redis.GetUserInfoCacheKey(fromUserID)
+	if err := u.merchRedisRepo.DeleteInfo(ctx, fromUserKey); err != nil {
+		return err
+	}
+
 	toUserID, err := u.merchRepo.GetUserIDByUsername(ctx, toUser)
 	if err != nil {
 		return err
@@ -86,15 +267,226 @@ func (u *merchUC) SendCoins(ctx context.Context, fromUserID int64, toUser string
 }
 
 // Buy an item
-func (u *merchUC) BuyItem(ctx context.Context, userID int64, item string) error {
-	if err := u.merchRepo.BuyItem(ctx, userID, item); err != nil {
+func (u *merchUC) BuyItem(ctx context.Context, userID int64, item string, quantity int64, idempotencyKey string) error {
+	key := newIdempotencyKey(idempotencyKey, "buy", item, quantity)
+
+	completed, err := u.isCompleted(ctx, userID, key)
+	if err != nil || completed {
 		return err
 	}
 
+	if err := u.merchRepo.BuyItem(ctx, userID, item, quantity, key); err != nil {
+		return u.fail(ctx, userID, key, err)
+	}
+
+	if err := u.invalidatePurchaseCache(ctx, userID); err != nil {
+		return err
+	}
+
+	return u.complete(ctx, userID, key, nil)
+}
+
+// Buy several items at once
+func (u *merchUC) BuyItems(ctx context.Context, userID int64, items []m.CartItem) error {
+	if err := u.merchRepo.BuyItems(ctx, userID, items); err != nil {
+		return err
+	}
+
+	return u.invalidatePurchaseCache(ctx, userID)
+}
+
+// Refund user's own order within the refund window
+func (u *merchUC) RefundOrder(ctx context.Context, userID, orderID int64) error {
+	order, err := u.merchRepo.GetOrder(ctx, orderID)
+	if err != nil {
+		return err
+	}
+
+	if order.UserID != userID {
+		return db.ErrOrderNotFound
+	}
+
+	if time.Since(order.CreatedAt) > u.cfg.App.RefundWindow {
+		return ErrRefundWindowExpired
+	}
+
+	if err := u.merchRepo.RefundOrder(ctx, orderID, userID); err != nil {
+		return err
+	}
+
+	return u.invalidatePurchaseCache(ctx, userID)
+}
+
+// Refund any order on behalf of an admin
+func (u *merchUC) AdminRefundOrder(ctx context.Context, adminID, orderID int64) error {
+	order, err := u.merchRepo.GetOrder(ctx, orderID)
+	if err != nil {
+		return err
+	}
+
+	if order.UserID == 0 {
+		return db.ErrOrderOwnerDeleted
+	}
+
+	if err := u.merchRepo.RefundOrder(ctx, orderID, adminID); err != nil {
+		return err
+	}
+
+	return u.invalidatePurchaseCache(ctx, order.UserID)
+}
+
+// Drop cached data changed by a purchase
+func (u *merchUC) invalidatePurchaseCache(ctx context.Context, userID int64) error {
 	key := redis.GetUserInfoCacheKey(userID)
 	if err := u.merchRedisRepo.DeleteInfo(ctx, key); err != nil {
 		return err
 	}
 
+	// Cached catalog holds remaining stock
+	if err := u.catalogRedisRepo.DeleteItems(ctx, redis.GetCatalogCacheKey()); err != nil {
+		return err
+	}
+
 	return nil
 }
+
+// Build idempotency key for the request, nil if the client didn't send one
+func newIdempotencyKey(key, operation string, params ...any) *m.IdempotencyKey {
+	if key == "" {
+		return nil
+	}
+
+	hash := sha256.New()
+	fmt.Fprint(hash, operation)
+	for _, param := range params {
+		fmt.Fprintf(hash, ":%v", param)
+	}
+
+	return &m.IdempotencyKey{
+		Key:         key,
+		Fingerprint: hex.EncodeToString(hash.Sum(nil)),
+	}
+}
+
+// Check the cache for a request already completed with the same key,
+// returning the error it failed with
+func (u *merchUC) isCompleted(ctx context.Context, userID int64, key *m.IdempotencyKey) (bool, error) {
+	if key == nil {
+		return false, nil
+	}
+
+	record, err := u.merchRedisRepo.GetIdempotencyKey(ctx, redis.GetIdempotencyCacheKey(userID, key.Key))
+	if err != nil {
+		return false, err
+	}
+
+	if record == nil {
+		return false, nil
+	}
+
+	if record.Fingerprint != key.Fingerprint {
+		return false, ErrIdempotencyKeyReused
+	}
+
+	return true, replayedError(record.Response)
+}
+
+// Store the business error of the request with its key, so retries fail the same way
+func (u *merchUC) fail(ctx context.Context, userID int64, key *m.IdempotencyKey, err error) error {
+	if errors.Is(err, db.ErrDuplicateRequest) {
+		return u.replay(ctx, userID, key)
+	}
+
+	failure := newIdempotencyFailure(err)
+	if key == nil || failure == nil {
+		return err
+	}
+
+	if saveErr := u.merchRepo.SaveIdempotencyFailure(ctx, userID, key, failure); saveErr != nil {
+		if errors.Is(saveErr, db.ErrDuplicateRequest) {
+			return u.replay(ctx, userID, key)
+		}
+		return saveErr
+	}
+
+	if cacheErr := u.complete(ctx, userID, key, failure); cacheErr != nil {
+		return cacheErr
+	}
+
+	return err
+}
+
+// Replay the outcome of a request already stored in the database
+func (u *merchUC) replay(ctx context.Context, userID int64, key *m.IdempotencyKey) error {
+	record, err := u.merchRepo.GetIdempotencyKey(ctx, userID, key.Key)
+	if err != nil {
+		return err
+	}
+
+	if record.Fingerprint != key.Fingerprint {
+		return ErrIdempotencyKeyReused
+	}
+
+	if err := u.complete(ctx, userID, key, record.Response); err != nil {
+		return err
+	}
+
+	return replayedError(record.Response)
+}
+
+// Cache the outcome of a completed request, failure is nil on success
+func (u *merchUC) complete(ctx context.Context, userID int64, key *m.IdempotencyKey, failure *m.IdempotencyFailure) error {
+	if key == nil {
+		return nil
+	}
+
+	record := &m.IdempotencyRecord{
+		Fingerprint: key.Fingerprint,
+		Status:      m.IdempotencySucceeded,
+		Response:    failure,
+	}
+	if failure != nil {
+		record.Status = m.IdempotencyFailed
+	}
+
+	return u.merchRedisRepo.SetIdempotencyKey(ctx, redis.GetIdempotencyCacheKey(userID, key.Key), record)
+}
+
+// Describe the error for storing with the idempotency key, nil if it must not be replayed
+func newIdempotencyFailure(err error) *m.IdempotencyFailure {
+	var limitErr *db.LimitExceededError
+	if errors.As(err, &limitErr) {
+		return &m.IdempotencyFailure{
+			Error:     limitErr.Error(),
+			Limit:     limitErr.Limit,
+			Remaining: limitErr.Remaining,
+		}
+	}
+
+	for _, replayable := range replayableErrors {
+		if errors.Is(err, replayable) {
+			return &m.IdempotencyFailure{Error: replayable.Error()}
+		}
+	}
+
+	return nil
+}
+
+// Restore the error the request failed with, nil on success
+func replayedError(failure *m.IdempotencyFailure) error {
+	if failure == nil {
+		return nil
+	}
+
+	if failure.Limit != "" {
+		return &db.LimitExceededError{Limit: failure.Limit, Remaining: failure.Remaining}
+	}
+
+	for _, replayable := range replayableErrors {
+		if failure.Error == replayable.Error() {
+			return replayable
+		}
+	}
+
+	return errors.New(failure.Error)
+}
@@ -2,12 +2,17 @@ package usecase_test
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 
+	"cyansnbrst/merch-service/config"
+	mock_catalog "cyansnbrst/merch-service/internal/catalog/mock"
 	mock_merch "cyansnbrst/merch-service/internal/merch/mock"
 	"cyansnbrst/merch-service/internal/merch/usecase"
 	m "cyansnbrst/merch-service/internal/models"
@@ -17,14 +22,40 @@ import (
 
 var ErrRandomDBError = errors.New("db error")
 
+func fingerprint(request string) string {
+	hash := sha256.Sum256([]byte(request))
+	return hex.EncodeToString(hash[:])
+}
+
+func idempotencyRecord(request string, failure *m.IdempotencyFailure) *m.IdempotencyRecord {
+	record := &m.IdempotencyRecord{
+		Fingerprint: fingerprint(request),
+		Status:      m.IdempotencySucceeded,
+		Response:    failure,
+	}
+	if failure != nil {
+		record.Status = m.IdempotencyFailed
+	}
+	return record
+}
+
 func TestMerchUC_GetInfo(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockRepo := mock_merch.NewMockRepository(ctrl)
 	mockRedisRepo := mock_merch.NewMockRedisRepository(ctrl)
+	mockCatalogRedisRepo := mock_catalog.NewMockRedisRepository(ctrl)
+	cfg := &config.Config{
+		App: config.App{
+			RefundWindow:    time.Hour * 72,
+			InfoHistorySize: 10,
+		},
+	}
 
-	merchUC := usecase.NewMerchUseCase(mockRepo, mockRedisRepo)
+	merchUC := usecase.NewMerchUseCase(cfg, mockRepo, mockRedisRepo, mockCatalogRedisRepo)
+
+	purchaseDate := time.Now()
 
 	tests := []struct {
 		name          string
@@ -51,7 +82,7 @@ func TestMerchUC_GetInfo(t *testing.T) {
 						},
 					},
 				}, nil)
-				mockRepo.EXPECT().GetTransactionHistory(gomock.Any(), int64(1)).Return(&m.TransactionHistory{
+				mockRepo.EXPECT().GetTransactionHistory(gomock.Any(), int64(1), int64(10)).Return(&m.TransactionHistory{
 					Received: []m.ReceiveTransaction{
 						{FromUser: "user1", Amount: 50},
 					},
@@ -59,6 +90,10 @@ func TestMerchUC_GetInfo(t *testing.T) {
 						{ToUser: "user2", Amount: 100},
 					},
 				}, nil)
+				mockRepo.EXPECT().GetPurchaseHistory(gomock.Any(), int64(1)).Return([]m.Order{
+					{ID: 1, Item: "hoodie", Price: 300, Quantity: 2, CreatedAt: purchaseDate},
+					{ID: 2, Item: "wallet", Price: 50, Quantity: 1, CreatedAt: purchaseDate},
+				}, nil)
 				mockRedisRepo.EXPECT().SetInfo(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
 			},
 			expectedResp: &m.InfoResponse{
@@ -83,6 +118,10 @@ func TestMerchUC_GetInfo(t *testing.T) {
 						{ToUser: "user2", Amount: 100},
 					},
 				},
+				PurchaseHistory: []m.Order{
+					{ID: 1, Item: "hoodie", Price: 300, Quantity: 2, CreatedAt: purchaseDate},
+					{ID: 2, Item: "wallet", Price: 50, Quantity: 1, CreatedAt: purchaseDate},
+				},
 			},
 			expectedError: nil,
 		},
@@ -159,7 +198,25 @@ func TestMerchUC_GetInfo(t *testing.T) {
 					Coins:     300,
 					Inventory: []m.InventoryItem{},
 				}, nil)
-				mockRepo.EXPECT().GetTransactionHistory(gomock.Any(), int64(5)).Return(nil, ErrRandomDBError)
+				mockRepo.EXPECT().GetTransactionHistory(gomock.Any(), int64(5), int64(10)).Return(nil, ErrRandomDBError)
+			},
+			expectedResp:  nil,
+			expectedError: ErrRandomDBError,
+		},
+		{
+			name:   "error db error in GetPurchaseHistory",
+			userID: 7,
+			mockSetup: func() {
+				mockRedisRepo.EXPECT().GetInfo(gomock.Any(), gomock.Any()).Return(nil, nil)
+				mockRepo.EXPECT().GetCoinsAndInventory(gomock.Any(), int64(7)).Return(&m.CoinsInventory{
+					Coins:     300,
+					Inventory: []m.InventoryItem{},
+				}, nil)
+				mockRepo.EXPECT().GetTransactionHistory(gomock.Any(), int64(7), int64(10)).Return(&m.TransactionHistory{
+					Received: []m.ReceiveTransaction{},
+					Sent:     []m.SendTransaction{},
+				}, nil)
+				mockRepo.EXPECT().GetPurchaseHistory(gomock.Any(), int64(7)).Return(nil, ErrRandomDBError)
 			},
 			expectedResp:  nil,
 			expectedError: ErrRandomDBError,
@@ -173,10 +230,11 @@ func TestMerchUC_GetInfo(t *testing.T) {
 					Coins:     400,
 					Inventory: []m.InventoryItem{},
 				}, nil)
-				mockRepo.EXPECT().GetTransactionHistory(gomock.Any(), int64(6)).Return(&m.TransactionHistory{
+				mockRepo.EXPECT().GetTransactionHistory(gomock.Any(), int64(6), int64(10)).Return(&m.TransactionHistory{
 					Received: []m.ReceiveTransaction{},
 					Sent:     []m.SendTransaction{},
 				}, nil)
+				mockRepo.EXPECT().GetPurchaseHistory(gomock.Any(), int64(6)).Return([]m.Order{}, nil)
 				mockRedisRepo.EXPECT().SetInfo(gomock.Any(), gomock.Any(), gomock.Any()).Return(ErrRandomDBError)
 			},
 			expectedResp:  nil,
@@ -196,30 +254,208 @@ func TestMerchUC_GetInfo(t *testing.T) {
 	}
 }
 
-func TestMerchUC_SendCoins(t *testing.T) {
+func TestMerchUC_GetInfo_Allowance(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockRepo := mock_merch.NewMockRepository(ctrl)
 	mockRedisRepo := mock_merch.NewMockRedisRepository(ctrl)
+	mockCatalogRedisRepo := mock_catalog.NewMockRedisRepository(ctrl)
+	cfg := &config.Config{
+		App: config.App{
+			RefundWindow:      time.Hour * 72,
+			InfoHistorySize:   10,
+			DailySendLimit:    1000,
+			DailyReceiveLimit: 2000,
+		},
+	}
+
+	merchUC := usecase.NewMerchUseCase(cfg, mockRepo, mockRedisRepo, mockCatalogRedisRepo)
 
-	merchUC := usecase.NewMerchUseCase(mockRepo, mockRedisRepo)
+	sendLeft, receiveLeft := int64(700), int64(0)
 
 	tests := []struct {
 		name          string
-		fromUserID    int64
-		toUser        string
-		amount        int64
+		userID        int64
+		mockSetup     func()
+		expectedResp  *m.InfoResponse
+		expectedError error
+	}{
+		{
+			name:   "success",
+			userID: 1,
+			mockSetup: func() {
+				mockRedisRepo.EXPECT().GetInfo(gomock.Any(), redis.GetUserInfoCacheKey(int64(1))).Return(&m.InfoResponse{
+					CoinsInventory: m.CoinsInventory{Coins: 700},
+				}, nil)
+				mockRepo.EXPECT().GetTransferredToday(gomock.Any(), int64(1)).Return(int64(300), int64(2500), nil)
+			},
+			expectedResp: &m.InfoResponse{
+				CoinsInventory: m.CoinsInventory{Coins: 700},
+				Allowance: &m.TransferAllowance{
+					Send:    &sendLeft,
+					Receive: &receiveLeft,
+				},
+			},
+			expectedError: nil,
+		},
+		{
+			name:   "error db error",
+			userID: 2,
+			mockSetup: func() {
+				mockRedisRepo.EXPECT().GetInfo(gomock.Any(), redis.GetUserInfoCacheKey(int64(2))).Return(&m.InfoResponse{}, nil)
+				mockRepo.EXPECT().GetTransferredToday(gomock.Any(), int64(2)).Return(int64(0), int64(0), ErrRandomDBError)
+			},
+			expectedResp:  nil,
+			expectedError: ErrRandomDBError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			resp, err := merchUC.GetInfo(context.Background(), tt.userID)
+
+			assert.Equal(t, tt.expectedResp, resp)
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
+
+func TestMerchUC_GetHistory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_merch.NewMockRepository(ctrl)
+	mockRedisRepo := mock_merch.NewMockRedisRepository(ctrl)
+	mockCatalogRedisRepo := mock_catalog.NewMockRedisRepository(ctrl)
+	cfg := &config.Config{
+		App: config.App{
+			RefundWindow:    time.Hour * 72,
+			InfoHistorySize: 10,
+		},
+	}
+
+	merchUC := usecase.NewMerchUseCase(cfg, mockRepo, mockRedisRepo, mockCatalogRedisRepo)
+
+	date := time.Now()
+	nextCursor := int64(8)
+
+	tests := []struct {
+		name          string
+		userID        int64
+		filter        m.HistoryFilter
 		mockSetup     func()
+		expectedPage  *m.HistoryPage
 		expectedError error
+	}{
+		{
+			name:   "success with next page",
+			userID: 1,
+			filter: m.HistoryFilter{Direction: "sent", Limit: 2},
+			mockSetup: func() {
+				mockRepo.EXPECT().GetTransactions(gomock.Any(), int64(1), m.HistoryFilter{Direction: "sent", Limit: 2}, int64(3)).Return([]m.HistoryEntry{
+					{ID: 10, Direction: "sent", Counterparty: "user2", Amount: 50, Date: date},
+					{ID: 8, Direction: "sent", Counterparty: "user3", Amount: 20, Date: date},
+					{ID: 5, Direction: "sent", Counterparty: "user2", Amount: 10, Date: date},
+				}, nil)
+			},
+			expectedPage: &m.HistoryPage{
+				Transactions: []m.HistoryEntry{
+					{ID: 10, Direction: "sent", Counterparty: "user2", Amount: 50, Date: date},
+					{ID: 8, Direction: "sent", Counterparty: "user3", Amount: 20, Date: date},
+				},
+				NextCursor: &nextCursor,
+			},
+			expectedError: nil,
+		},
+		{
+			name:   "success last page",
+			userID: 1,
+			filter: m.HistoryFilter{Cursor: 5, Limit: 2},
+			mockSetup: func() {
+				mockRepo.EXPECT().GetTransactions(gomock.Any(), int64(1), m.HistoryFilter{Cursor: 5, Limit: 2}, int64(3)).Return([]m.HistoryEntry{
+					{ID: 3, Direction: "received", Counterparty: "user4", Amount: 100, Date: date},
+				}, nil)
+			},
+			expectedPage: &m.HistoryPage{
+				Transactions: []m.HistoryEntry{
+					{ID: 3, Direction: "received", Counterparty: "user4", Amount: 100, Date: date},
+				},
+			},
+			expectedError: nil,
+		},
+		{
+			name:   "success default limit",
+			userID: 1,
+			filter: m.HistoryFilter{Limit: 0},
+			mockSetup: func() {
+				mockRepo.EXPECT().GetTransactions(gomock.Any(), int64(1), m.HistoryFilter{Limit: 20}, int64(21)).Return([]m.HistoryEntry{
+					{ID: 3, Direction: "received", Counterparty: "user4", Amount: 100, Date: date},
+				}, nil)
+			},
+			expectedPage: &m.HistoryPage{
+				Transactions: []m.HistoryEntry{
+					{ID: 3, Direction: "received", Counterparty: "user4", Amount: 100, Date: date},
+				},
+			},
+			expectedError: nil,
+		},
+		{
+			name:   "error db error",
+			userID: 2,
+			filter: m.HistoryFilter{Limit: 20},
+			mockSetup: func() {
+				mockRepo.EXPECT().GetTransactions(gomock.Any(), int64(2), m.HistoryFilter{Limit: 20}, int64(21)).Return(nil, ErrRandomDBError)
+			},
+			expectedPage:  nil,
+			expectedError: ErrRandomDBError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			page, err := merchUC.GetHistory(context.Background(), tt.userID, tt.filter)
+
+			assert.Equal(t, tt.expectedPage, page)
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
+
+func TestMerchUC_SendCoins(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_merch.NewMockRepository(ctrl)
+	mockRedisRepo := mock_merch.NewMockRedisRepository(ctrl)
+	mockCatalogRedisRepo := mock_catalog.NewMockRedisRepository(ctrl)
+	cfg := &config.Config{
+		App: config.App{
+			RefundWindow:    time.Hour * 72,
+			InfoHistorySize: 10,
+		},
+	}
+
+	merchUC := usecase.NewMerchUseCase(cfg, mockRepo, mockRedisRepo, mockCatalogRedisRepo)
+
+	tests := []struct {
+		name           string
+		fromUserID     int64
+		transfer       m.SendCoinRequest
+		idempotencyKey string
+		mockSetup      func()
+		expectedError  error
 	}{
 		{
 			name:       "success",
 			fromUserID: 1,
-			toUser:     "user1",
-			amount:     50,
+			transfer:   m.SendCoinRequest{ToUser: "user1", Amount: 50, Comment: "thanks for the help"},
 			mockSetup: func() {
-				mockRepo.EXPECT().SendCoins(gomock.Any(), int64(1), "user1", int64(50)).Return(nil)
+				mockRepo.EXPECT().SendCoins(gomock.Any(), int64(1), m.SendCoinRequest{ToUser: "user1", Amount: 50, Comment: "thanks for the help"}, nil).Return(nil)
 				mockRedisRepo.EXPECT().DeleteInfo(gomock.Any(), redis.GetUserInfoCacheKey(int64(1))).Return(nil)
 				mockRepo.EXPECT().GetUserIDByUsername(gomock.Any(), "user1").Return(int64(2), nil)
 				mockRedisRepo.EXPECT().DeleteInfo(gomock.Any(), redis.GetUserInfoCacheKey(int64(2))).Return(nil)
@@ -229,20 +465,18 @@ func TestMerchUC_SendCoins(t *testing.T) {
 		{
 			name:       "error insufficient funds",
 			fromUserID: 1,
-			toUser:     "user2",
-			amount:     200,
+			transfer:   m.SendCoinRequest{ToUser: "user2", Amount: 200},
 			mockSetup: func() {
-				mockRepo.EXPECT().SendCoins(gomock.Any(), int64(1), "user2", int64(200)).Return(db.ErrInsufficientFunds)
+				mockRepo.EXPECT().SendCoins(gomock.Any(), int64(1), m.SendCoinRequest{ToUser: "user2", Amount: 200}, nil).Return(db.ErrInsufficientFunds)
 			},
 			expectedError: db.ErrInsufficientFunds,
 		},
 		{
 			name:       "error delete cache for sender",
 			fromUserID: 1,
-			toUser:     "user3",
-			amount:     50,
+			transfer:   m.SendCoinRequest{ToUser: "user3", Amount: 50},
 			mockSetup: func() {
-				mockRepo.EXPECT().SendCoins(gomock.Any(), int64(1), "user3", int64(50)).Return(nil)
+				mockRepo.EXPECT().SendCoins(gomock.Any(), int64(1), m.SendCoinRequest{ToUser: "user3", Amount: 50}, nil).Return(nil)
 				mockRedisRepo.EXPECT().DeleteInfo(gomock.Any(), redis.GetUserInfoCacheKey(int64(1))).Return(ErrRandomDBError)
 			},
 			expectedError: ErrRandomDBError,
@@ -250,10 +484,9 @@ func TestMerchUC_SendCoins(t *testing.T) {
 		{
 			name:       "error delete cache for receiver",
 			fromUserID: 1,
-			toUser:     "user4",
-			amount:     50,
+			transfer:   m.SendCoinRequest{ToUser: "user4", Amount: 50},
 			mockSetup: func() {
-				mockRepo.EXPECT().SendCoins(gomock.Any(), int64(1), "user4", int64(50)).Return(nil)
+				mockRepo.EXPECT().SendCoins(gomock.Any(), int64(1), m.SendCoinRequest{ToUser: "user4", Amount: 50}, nil).Return(nil)
 				mockRedisRepo.EXPECT().DeleteInfo(gomock.Any(), redis.GetUserInfoCacheKey(int64(1))).Return(nil)
 				mockRepo.EXPECT().GetUserIDByUsername(gomock.Any(), "user4").Return(int64(3), nil)
 				mockRedisRepo.EXPECT().DeleteInfo(gomock.Any(), redis.GetUserInfoCacheKey(int64(3))).Return(ErrRandomDBError)
@@ -263,22 +496,108 @@ func TestMerchUC_SendCoins(t *testing.T) {
 		{
 			name:       "error get user ID by username",
 			fromUserID: 1,
-			toUser:     "user5",
-			amount:     50,
+			transfer:   m.SendCoinRequest{ToUser: "user5", Amount: 50},
 			mockSetup: func() {
-				mockRepo.EXPECT().SendCoins(gomock.Any(), int64(1), "user5", int64(50)).Return(nil)
+				mockRepo.EXPECT().SendCoins(gomock.Any(), int64(1), m.SendCoinRequest{ToUser: "user5", Amount: 50}, nil).Return(nil)
 				mockRedisRepo.EXPECT().DeleteInfo(gomock.Any(), redis.GetUserInfoCacheKey(int64(1))).Return(nil)
 				mockRepo.EXPECT().GetUserIDByUsername(gomock.Any(), "user5").Return(int64(0), ErrRandomDBError)
 			},
 			expectedError: ErrRandomDBError,
 		},
+		{
+			name:           "success with idempotency key",
+			fromUserID:     1,
+			transfer:       m.SendCoinRequest{ToUser: "user6", Amount: 50},
+			idempotencyKey: "key1",
+			mockSetup: func() {
+				mockRedisRepo.EXPECT().GetIdempotencyKey(gomock.Any(), redis.GetIdempotencyCacheKey(int64(1), "key1")).Return(nil, nil)
+				mockRepo.EXPECT().SendCoins(gomock.Any(), int64(1), m.SendCoinRequest{ToUser: "user6", Amount: 50}, gomock.Not(gomock.Nil())).Return(nil)
+				mockRedisRepo.EXPECT().DeleteInfo(gomock.Any(), redis.GetUserInfoCacheKey(int64(1))).Return(nil)
+				mockRepo.EXPECT().GetUserIDByUsername(gomock.Any(), "user6").Return(int64(6), nil)
+				mockRedisRepo.EXPECT().DeleteInfo(gomock.Any(), redis.GetUserInfoCacheKey(int64(6))).Return(nil)
+				mockRedisRepo.EXPECT().SetIdempotencyKey(gomock.Any(), redis.GetIdempotencyCacheKey(int64(1), "key1"), idempotencyRecord("sendCoin:user6:50:", nil)).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:           "success replayed from cache",
+			fromUserID:     1,
+			transfer:       m.SendCoinRequest{ToUser: "user6", Amount: 50},
+			idempotencyKey: "key2",
+			mockSetup: func() {
+				mockRedisRepo.EXPECT().GetIdempotencyKey(gomock.Any(), redis.GetIdempotencyCacheKey(int64(1), "key2")).Return(idempotencyRecord("sendCoin:user6:50:", nil), nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:           "success replayed from database",
+			fromUserID:     1,
+			transfer:       m.SendCoinRequest{ToUser: "user6", Amount: 50},
+			idempotencyKey: "key3",
+			mockSetup: func() {
+				mockRedisRepo.EXPECT().GetIdempotencyKey(gomock.Any(), redis.GetIdempotencyCacheKey(int64(1), "key3")).Return(nil, nil)
+				mockRepo.EXPECT().SendCoins(gomock.Any(), int64(1), m.SendCoinRequest{ToUser: "user6", Amount: 50}, gomock.Not(gomock.Nil())).Return(db.ErrDuplicateRequest)
+				mockRepo.EXPECT().GetIdempotencyKey(gomock.Any(), int64(1), "key3").Return(idempotencyRecord("sendCoin:user6:50:", nil), nil)
+				mockRedisRepo.EXPECT().SetIdempotencyKey(gomock.Any(), redis.GetIdempotencyCacheKey(int64(1), "key3"), idempotencyRecord("sendCoin:user6:50:", nil)).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:           "error insufficient funds stored with idempotency key",
+			fromUserID:     1,
+			transfer:       m.SendCoinRequest{ToUser: "user6", Amount: 5000},
+			idempotencyKey: "key4",
+			mockSetup: func() {
+				failure := &m.IdempotencyFailure{Error: db.ErrInsufficientFunds.Error()}
+				mockRedisRepo.EXPECT().GetIdempotencyKey(gomock.Any(), redis.GetIdempotencyCacheKey(int64(1), "key4")).Return(nil, nil)
+				mockRepo.EXPECT().SendCoins(gomock.Any(), int64(1), m.SendCoinRequest{ToUser: "user6", Amount: 5000}, gomock.Not(gomock.Nil())).Return(db.ErrInsufficientFunds)
+				mockRepo.EXPECT().SaveIdempotencyFailure(gomock.Any(), int64(1), gomock.Not(gomock.Nil()), failure).Return(nil)
+				mockRedisRepo.EXPECT().SetIdempotencyKey(gomock.Any(), redis.GetIdempotencyCacheKey(int64(1), "key4"), idempotencyRecord("sendCoin:user6:5000:", failure)).Return(nil)
+			},
+			expectedError: db.ErrInsufficientFunds,
+		},
+		{
+			name:           "error limit exceeded replayed from cache",
+			fromUserID:     1,
+			transfer:       m.SendCoinRequest{ToUser: "user6", Amount: 500},
+			idempotencyKey: "key5",
+			mockSetup: func() {
+				mockRedisRepo.EXPECT().GetIdempotencyKey(gomock.Any(), redis.GetIdempotencyCacheKey(int64(1), "key5")).Return(idempotencyRecord("sendCoin:user6:500:", &m.IdempotencyFailure{
+					Error:     "daily send limit exceeded, 100 coins left",
+					Limit:     "daily send",
+					Remaining: 100,
+				}), nil)
+			},
+			expectedError: &db.LimitExceededError{Limit: "daily send", Remaining: 100},
+		},
+		{
+			name:           "error unexpected failure not stored",
+			fromUserID:     1,
+			transfer:       m.SendCoinRequest{ToUser: "user6", Amount: 50},
+			idempotencyKey: "key6",
+			mockSetup: func() {
+				mockRedisRepo.EXPECT().GetIdempotencyKey(gomock.Any(), redis.GetIdempotencyCacheKey(int64(1), "key6")).Return(nil, nil)
+				mockRepo.EXPECT().SendCoins(gomock.Any(), int64(1), m.SendCoinRequest{ToUser: "user6", Amount: 50}, gomock.Not(gomock.Nil())).Return(ErrRandomDBError)
+			},
+			expectedError: ErrRandomDBError,
+		},
+		{
+			name:           "error idempotency key reused",
+			fromUserID:     1,
+			transfer:       m.SendCoinRequest{ToUser: "user6", Amount: 100},
+			idempotencyKey: "key2",
+			mockSetup: func() {
+				mockRedisRepo.EXPECT().GetIdempotencyKey(gomock.Any(), redis.GetIdempotencyCacheKey(int64(1), "key2")).Return(idempotencyRecord("sendCoin:user6:50:", nil), nil)
+			},
+			expectedError: usecase.ErrIdempotencyKeyReused,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockSetup()
 
-			err := merchUC.SendCoins(context.Background(), tt.fromUserID, tt.toUser, tt.amount)
+			err := merchUC.SendCoins(context.Background(), tt.fromUserID, tt.transfer, tt.idempotencyKey)
 
 			assert.Equal(t, tt.expectedError, err)
 		})
@@ -291,41 +610,182 @@ func TestMerchUC_BuyItem(t *testing.T) {
 
 	mockRepo := mock_merch.NewMockRepository(ctrl)
 	mockRedisRepo := mock_merch.NewMockRedisRepository(ctrl)
+	mockCatalogRedisRepo := mock_catalog.NewMockRedisRepository(ctrl)
+	cfg := &config.Config{
+		App: config.App{
+			RefundWindow:    time.Hour * 72,
+			InfoHistorySize: 10,
+		},
+	}
+
+	merchUC := usecase.NewMerchUseCase(cfg, mockRepo, mockRedisRepo, mockCatalogRedisRepo)
+
+	tests := []struct {
+		name           string
+		userID         int64
+		item           string
+		quantity       int64
+		idempotencyKey string
+		mockSetup      func()
+		expectedError  error
+	}{
+		{
+			name:     "success",
+			userID:   1,
+			item:     "item1",
+			quantity: 1,
+			mockSetup: func() {
+				mockRepo.EXPECT().BuyItem(gomock.Any(), int64(1), "item1", int64(1), nil).Return(nil)
+				mockRedisRepo.EXPECT().DeleteInfo(gomock.Any(), redis.GetUserInfoCacheKey(int64(1))).Return(nil)
+				mockCatalogRedisRepo.EXPECT().DeleteItems(gomock.Any(), redis.GetCatalogCacheKey()).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:     "success several units",
+			userID:   1,
+			item:     "pen",
+			quantity: 10,
+			mockSetup: func() {
+				mockRepo.EXPECT().BuyItem(gomock.Any(), int64(1), "pen", int64(10), nil).Return(nil)
+				mockRedisRepo.EXPECT().DeleteInfo(gomock.Any(), redis.GetUserInfoCacheKey(int64(1))).Return(nil)
+				mockCatalogRedisRepo.EXPECT().DeleteItems(gomock.Any(), redis.GetCatalogCacheKey()).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:     "error item not found",
+			userID:   1,
+			item:     "item1",
+			quantity: 1,
+			mockSetup: func() {
+				mockRepo.EXPECT().BuyItem(gomock.Any(), int64(1), "item1", int64(1), nil).Return(db.ErrItemtNotFound)
+			},
+			expectedError: db.ErrItemtNotFound,
+		},
+		{
+			name:     "error out of stock",
+			userID:   1,
+			item:     "pink-hoody",
+			quantity: 1,
+			mockSetup: func() {
+				mockRepo.EXPECT().BuyItem(gomock.Any(), int64(1), "pink-hoody", int64(1), nil).Return(db.ErrOutOfStock)
+			},
+			expectedError: db.ErrOutOfStock,
+		},
+		{
+			name:     "error delete cache",
+			userID:   1,
+			item:     "item1",
+			quantity: 1,
+			mockSetup: func() {
+				mockRepo.EXPECT().BuyItem(gomock.Any(), int64(1), "item1", int64(1), nil).Return(nil)
+				mockRedisRepo.EXPECT().DeleteInfo(gomock.Any(), redis.GetUserInfoCacheKey(int64(1))).Return(ErrRandomDBError)
+			},
+			expectedError: ErrRandomDBError,
+		},
+		{
+			name:     "error delete catalog cache",
+			userID:   1,
+			item:     "item1",
+			quantity: 1,
+			mockSetup: func() {
+				mockRepo.EXPECT().BuyItem(gomock.Any(), int64(1), "item1", int64(1), nil).Return(nil)
+				mockRedisRepo.EXPECT().DeleteInfo(gomock.Any(), redis.GetUserInfoCacheKey(int64(1))).Return(nil)
+				mockCatalogRedisRepo.EXPECT().DeleteItems(gomock.Any(), redis.GetCatalogCacheKey()).Return(ErrRandomDBError)
+			},
+			expectedError: ErrRandomDBError,
+		},
+		{
+			name:           "success replayed from database",
+			userID:         1,
+			item:           "cup",
+			quantity:       2,
+			idempotencyKey: "key1",
+			mockSetup: func() {
+				mockRedisRepo.EXPECT().GetIdempotencyKey(gomock.Any(), redis.GetIdempotencyCacheKey(int64(1), "key1")).Return(nil, nil)
+				mockRepo.EXPECT().BuyItem(gomock.Any(), int64(1), "cup", int64(2), gomock.Not(gomock.Nil())).Return(db.ErrDuplicateRequest)
+				mockRepo.EXPECT().GetIdempotencyKey(gomock.Any(), int64(1), "key1").Return(idempotencyRecord("buy:cup:2", nil), nil)
+				mockRedisRepo.EXPECT().SetIdempotencyKey(gomock.Any(), redis.GetIdempotencyCacheKey(int64(1), "key1"), idempotencyRecord("buy:cup:2", nil)).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:           "error idempotency key reused",
+			userID:         1,
+			item:           "cup",
+			quantity:       3,
+			idempotencyKey: "key1",
+			mockSetup: func() {
+				mockRedisRepo.EXPECT().GetIdempotencyKey(gomock.Any(), redis.GetIdempotencyCacheKey(int64(1), "key1")).Return(nil, nil)
+				mockRepo.EXPECT().BuyItem(gomock.Any(), int64(1), "cup", int64(3), gomock.Not(gomock.Nil())).Return(db.ErrDuplicateRequest)
+				mockRepo.EXPECT().GetIdempotencyKey(gomock.Any(), int64(1), "key1").Return(idempotencyRecord("buy:cup:2", nil), nil)
+			},
+			expectedError: usecase.ErrIdempotencyKeyReused,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			err := merchUC.BuyItem(context.Background(), tt.userID, tt.item, tt.quantity, tt.idempotencyKey)
+
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
+
+func TestMerchUC_BuyItems(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_merch.NewMockRepository(ctrl)
+	mockRedisRepo := mock_merch.NewMockRedisRepository(ctrl)
+	mockCatalogRedisRepo := mock_catalog.NewMockRedisRepository(ctrl)
+	cfg := &config.Config{
+		App: config.App{
+			RefundWindow:    time.Hour * 72,
+			InfoHistorySize: 10,
+		},
+	}
+
+	merchUC := usecase.NewMerchUseCase(cfg, mockRepo, mockRedisRepo, mockCatalogRedisRepo)
 
-	merchUC := usecase.NewMerchUseCase(mockRepo, mockRedisRepo)
+	items := []m.CartItem{
+		{Item: "cup", Quantity: 1},
+		{Item: "socks", Quantity: 2},
+	}
 
 	tests := []struct {
 		name          string
 		userID        int64
-		item          string
 		mockSetup     func()
 		expectedError error
 	}{
 		{
 			name:   "success",
 			userID: 1,
-			item:   "item1",
 			mockSetup: func() {
-				mockRepo.EXPECT().BuyItem(gomock.Any(), int64(1), "item1").Return(nil)
+				mockRepo.EXPECT().BuyItems(gomock.Any(), int64(1), items).Return(nil)
 				mockRedisRepo.EXPECT().DeleteInfo(gomock.Any(), redis.GetUserInfoCacheKey(int64(1))).Return(nil)
+				mockCatalogRedisRepo.EXPECT().DeleteItems(gomock.Any(), redis.GetCatalogCacheKey()).Return(nil)
 			},
 			expectedError: nil,
 		},
 		{
 			name:   "error item not found",
 			userID: 1,
-			item:   "item1",
 			mockSetup: func() {
-				mockRepo.EXPECT().BuyItem(gomock.Any(), int64(1), "item1").Return(db.ErrItemtNotFound)
+				mockRepo.EXPECT().BuyItems(gomock.Any(), int64(1), items).Return(db.ErrItemtNotFound)
 			},
 			expectedError: db.ErrItemtNotFound,
 		},
 		{
 			name:   "error delete cache",
 			userID: 1,
-			item:   "item1",
 			mockSetup: func() {
-				mockRepo.EXPECT().BuyItem(gomock.Any(), int64(1), "item1").Return(nil)
+				mockRepo.EXPECT().BuyItems(gomock.Any(), int64(1), items).Return(nil)
 				mockRedisRepo.EXPECT().DeleteInfo(gomock.Any(), redis.GetUserInfoCacheKey(int64(1))).Return(ErrRandomDBError)
 			},
 			expectedError: ErrRandomDBError,
@@ -336,7 +796,503 @@ func TestMerchUC_BuyItem(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockSetup()
 
-			err := merchUC.BuyItem(context.Background(), tt.userID, tt.item)
+			err := merchUC.BuyItems(context.Background(), tt.userID, items)
+
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
+
+func TestMerchUC_RefundOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_merch.NewMockRepository(ctrl)
+	mockRedisRepo := mock_merch.NewMockRedisRepository(ctrl)
+	mockCatalogRedisRepo := mock_catalog.NewMockRedisRepository(ctrl)
+	cfg := &config.Config{
+		App: config.App{
+			RefundWindow:    time.Hour * 72,
+			InfoHistorySize: 10,
+		},
+	}
+
+	merchUC := usecase.NewMerchUseCase(cfg, mockRepo, mockRedisRepo, mockCatalogRedisRepo)
+
+	tests := []struct {
+		name          string
+		userID        int64
+		orderID       int64
+		mockSetup     func()
+		expectedError error
+	}{
+		{
+			name:    "success",
+			userID:  1,
+			orderID: 10,
+			mockSetup: func() {
+				mockRepo.EXPECT().GetOrder(gomock.Any(), int64(10)).Return(&m.Order{
+					ID:        10,
+					UserID:    1,
+					CreatedAt: time.Now().Add(-time.Hour),
+				}, nil)
+				mockRepo.EXPECT().RefundOrder(gomock.Any(), int64(10), int64(1)).Return(nil)
+				mockRedisRepo.EXPECT().DeleteInfo(gomock.Any(), redis.GetUserInfoCacheKey(int64(1))).Return(nil)
+				mockCatalogRedisRepo.EXPECT().DeleteItems(gomock.Any(), redis.GetCatalogCacheKey()).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:    "error someone else's order",
+			userID:  2,
+			orderID: 10,
+			mockSetup: func() {
+				mockRepo.EXPECT().GetOrder(gomock.Any(), int64(10)).Return(&m.Order{
+					ID:        10,
+					UserID:    1,
+					CreatedAt: time.Now().Add(-time.Hour),
+				}, nil)
+			},
+			expectedError: db.ErrOrderNotFound,
+		},
+		{
+			name:    "error refund window expired",
+			userID:  1,
+			orderID: 11,
+			mockSetup: func() {
+				mockRepo.EXPECT().GetOrder(gomock.Any(), int64(11)).Return(&m.Order{
+					ID:        11,
+					UserID:    1,
+					CreatedAt: time.Now().Add(-time.Hour * 73),
+				}, nil)
+			},
+			expectedError: usecase.ErrRefundWindowExpired,
+		},
+		{
+			name:    "error already refunded",
+			userID:  1,
+			orderID: 12,
+			mockSetup: func() {
+				mockRepo.EXPECT().GetOrder(gomock.Any(), int64(12)).Return(&m.Order{
+					ID:        12,
+					UserID:    1,
+					CreatedAt: time.Now(),
+				}, nil)
+				mockRepo.EXPECT().RefundOrder(gomock.Any(), int64(12), int64(1)).Return(db.ErrAlreadyRefunded)
+			},
+			expectedError: db.ErrAlreadyRefunded,
+		},
+		{
+			name:    "error order not found",
+			userID:  1,
+			orderID: 13,
+			mockSetup: func() {
+				mockRepo.EXPECT().GetOrder(gomock.Any(), int64(13)).Return(nil, db.ErrOrderNotFound)
+			},
+			expectedError: db.ErrOrderNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			err := merchUC.RefundOrder(context.Background(), tt.userID, tt.orderID)
+
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
+
+func TestMerchUC_AdminRefundOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_merch.NewMockRepository(ctrl)
+	mockRedisRepo := mock_merch.NewMockRedisRepository(ctrl)
+	mockCatalogRedisRepo := mock_catalog.NewMockRedisRepository(ctrl)
+	cfg := &config.Config{
+		App: config.App{
+			RefundWindow:    time.Hour * 72,
+			InfoHistorySize: 10,
+		},
+	}
+
+	merchUC := usecase.NewMerchUseCase(cfg, mockRepo, mockRedisRepo, mockCatalogRedisRepo)
+
+	tests := []struct {
+		name          string
+		adminID       int64
+		orderID       int64
+		mockSetup     func()
+		expectedError error
+	}{
+		{
+			name:    "success outside refund window",
+			adminID: 100,
+			orderID: 10,
+			mockSetup: func() {
+				mockRepo.EXPECT().GetOrder(gomock.Any(), int64(10)).Return(&m.Order{
+					ID:        10,
+					UserID:    1,
+					CreatedAt: time.Now().Add(-time.Hour * 1000),
+				}, nil)
+				mockRepo.EXPECT().RefundOrder(gomock.Any(), int64(10), int64(100)).Return(nil)
+				mockRedisRepo.EXPECT().DeleteInfo(gomock.Any(), redis.GetUserInfoCacheKey(int64(1))).Return(nil)
+				mockCatalogRedisRepo.EXPECT().DeleteItems(gomock.Any(), redis.GetCatalogCacheKey()).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:    "error not enough items",
+			adminID: 100,
+			orderID: 11,
+			mockSetup: func() {
+				mockRepo.EXPECT().GetOrder(gomock.Any(), int64(11)).Return(&m.Order{
+					ID:        11,
+					UserID:    1,
+					CreatedAt: time.Now(),
+				}, nil)
+				mockRepo.EXPECT().RefundOrder(gomock.Any(), int64(11), int64(100)).Return(db.ErrNotEnoughItems)
+			},
+			expectedError: db.ErrNotEnoughItems,
+		},
+		{
+			name:    "error owner deleted",
+			adminID: 100,
+			orderID: 13,
+			mockSetup: func() {
+				mockRepo.EXPECT().GetOrder(gomock.Any(), int64(13)).Return(&m.Order{
+					ID:        13,
+					UserID:    0,
+					CreatedAt: time.Now(),
+				}, nil)
+			},
+			expectedError: db.ErrOrderOwnerDeleted,
+		},
+		{
+			name:    "error delete cache",
+			adminID: 100,
+			orderID: 12,
+			mockSetup: func() {
+				mockRepo.EXPECT().GetOrder(gomock.Any(), int64(12)).Return(&m.Order{
+					ID:        12,
+					UserID:    3,
+					CreatedAt: time.Now(),
+				}, nil)
+				mockRepo.EXPECT().RefundOrder(gomock.Any(), int64(12), int64(100)).Return(nil)
+				mockRedisRepo.EXPECT().DeleteInfo(gomock.Any(), redis.GetUserInfoCacheKey(int64(3))).Return(ErrRandomDBError)
+			},
+			expectedError: ErrRandomDBError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			err := merchUC.AdminRefundOrder(context.Background(), tt.adminID, tt.orderID)
+
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
+
+func TestMerchUC_SendCoinBatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_merch.NewMockRepository(ctrl)
+	mockRedisRepo := mock_merch.NewMockRedisRepository(ctrl)
+	mockCatalogRedisRepo := mock_catalog.NewMockRedisRepository(ctrl)
+	cfg := &config.Config{
+		App: config.App{
+			RefundWindow:    time.Hour * 72,
+			InfoHistorySize: 10,
+		},
+	}
+
+	merchUC := usecase.NewMerchUseCase(cfg, mockRepo, mockRedisRepo, mockCatalogRedisRepo)
+
+	tests := []struct {
+		name          string
+		fromUserID    int64
+		transfers     []m.SendCoinRequest
+		mockSetup     func()
+		expectedError error
+	}{
+		{
+			name:       "success",
+			fromUserID: 1,
+			transfers: []m.SendCoinRequest{
+				{ToUser: "user2", Amount: 50},
+				{ToUser: "user3", Amount: 100, Comment: "great job"},
+			},
+			mockSetup: func() {
+				mockRepo.EXPECT().SendCoinBatch(gomock.Any(), int64(1), []m.SendCoinRequest{
+					{ToUser: "user2", Amount: 50},
+					{ToUser: "user3", Amount: 100, Comment: "great job"},
+				}).Return([]int64{2, 3}, nil)
+				mockRedisRepo.EXPECT().DeleteInfo(gomock.Any(), redis.GetUserInfoCacheKey(int64(1))).Return(nil)
+				mockRedisRepo.EXPECT().DeleteInfo(gomock.Any(), redis.GetUserInfoCacheKey(int64(2))).Return(nil)
+				mockRedisRepo.EXPECT().DeleteInfo(gomock.Any(), redis.GetUserInfoCacheKey(int64(3))).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:       "error duplicate recipient",
+			fromUserID: 1,
+			transfers: []m.SendCoinRequest{
+				{ToUser: "user2", Amount: 50},
+				{ToUser: "user2", Amount: 100},
+			},
+			mockSetup:     func() {},
+			expectedError: db.ErrDuplicateReciever,
+		},
+		{
+			name:       "error insufficient funds",
+			fromUserID: 1,
+			transfers: []m.SendCoinRequest{
+				{ToUser: "user2", Amount: 5000},
+			},
+			mockSetup: func() {
+				mockRepo.EXPECT().SendCoinBatch(gomock.Any(), int64(1), []m.SendCoinRequest{
+					{ToUser: "user2", Amount: 5000},
+				}).Return(nil, db.ErrInsufficientFunds)
+			},
+			expectedError: db.ErrInsufficientFunds,
+		},
+		{
+			name:       "error delete cache",
+			fromUserID: 1,
+			transfers: []m.SendCoinRequest{
+				{ToUser: "user4", Amount: 10},
+			},
+			mockSetup: func() {
+				mockRepo.EXPECT().SendCoinBatch(gomock.Any(), int64(1), []m.SendCoinRequest{
+					{ToUser: "user4", Amount: 10},
+				}).Return([]int64{4}, nil)
+				mockRedisRepo.EXPECT().DeleteInfo(gomock.Any(), redis.GetUserInfoCacheKey(int64(1))).Return(ErrRandomDBError)
+			},
+			expectedError: ErrRandomDBError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			err := merchUC.SendCoinBatch(context.Background(), tt.fromUserID, tt.transfers)
+
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
+
+func TestMerchUC_PayInvoice(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_merch.NewMockRepository(ctrl)
+	mockRedisRepo := mock_merch.NewMockRedisRepository(ctrl)
+	mockCatalogRedisRepo := mock_catalog.NewMockRedisRepository(ctrl)
+
+	merchUC := usecase.NewMerchUseCase(&config.Config{}, mockRepo, mockRedisRepo, mockCatalogRedisRepo)
+
+	tests := []struct {
+		name          string
+		fromUserID    int64
+		invoiceID     int64
+		mockSetup     func()
+		expectedError error
+	}{
+		{
+			name:       "success",
+			fromUserID: 1,
+			invoiceID:  10,
+			mockSetup: func() {
+				mockRepo.EXPECT().PayInvoice(gomock.Any(), int64(1), int64(10)).Return(int64(2), nil)
+				mockRedisRepo.EXPECT().DeleteInfo(gomock.Any(), redis.GetUserInfoCacheKey(int64(1))).Return(nil)
+				mockRedisRepo.EXPECT().DeleteInfo(gomock.Any(), redis.GetUserInfoCacheKey(int64(2))).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:       "error invoice not pending",
+			fromUserID: 1,
+			invoiceID:  11,
+			mockSetup: func() {
+				mockRepo.EXPECT().PayInvoice(gomock.Any(), int64(1), int64(11)).Return(int64(0), db.ErrInvoiceNotPending)
+			},
+			expectedError: db.ErrInvoiceNotPending,
+		},
+		{
+			name:       "error delete cache",
+			fromUserID: 1,
+			invoiceID:  12,
+			mockSetup: func() {
+				mockRepo.EXPECT().PayInvoice(gomock.Any(), int64(1), int64(12)).Return(int64(2), nil)
+				mockRedisRepo.EXPECT().DeleteInfo(gomock.Any(), redis.GetUserInfoCacheKey(int64(1))).Return(ErrRandomDBError)
+			},
+			expectedError: ErrRandomDBError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			err := merchUC.PayInvoice(context.Background(), tt.fromUserID, tt.invoiceID)
+
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
+
+func TestMerchUC_GiftItem(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_merch.NewMockRepository(ctrl)
+	mockRedisRepo := mock_merch.NewMockRedisRepository(ctrl)
+	mockCatalogRedisRepo := mock_catalog.NewMockRedisRepository(ctrl)
+	cfg := &config.Config{
+		App: config.App{
+			RefundWindow:    time.Hour * 72,
+			InfoHistorySize: 10,
+		},
+	}
+
+	merchUC := usecase.NewMerchUseCase(cfg, mockRepo, mockRedisRepo, mockCatalogRedisRepo)
+
+	tests := []struct {
+		name          string
+		fromUserID    int64
+		toUser        string
+		item          string
+		quantity      int64
+		mockSetup     func()
+		expectedError error
+	}{
+		{
+			name:       "success",
+			fromUserID: 1,
+			toUser:     "user1",
+			item:       "t-shirt",
+			quantity:   1,
+			mockSetup: func() {
+				mockRepo.EXPECT().GiftItem(gomock.Any(), int64(1), "user1", "t-shirt", int64(1)).Return(nil)
+				mockRedisRepo.EXPECT().DeleteInfo(gomock.Any(), redis.GetUserInfoCacheKey(int64(1))).Return(nil)
+				mockRepo.EXPECT().GetUserIDByUsername(gomock.Any(), "user1").Return(int64(2), nil)
+				mockRedisRepo.EXPECT().DeleteInfo(gomock.Any(), redis.GetUserInfoCacheKey(int64(2))).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:       "error not enough items",
+			fromUserID: 1,
+			toUser:     "user2",
+			item:       "cup",
+			quantity:   5,
+			mockSetup: func() {
+				mockRepo.EXPECT().GiftItem(gomock.Any(), int64(1), "user2", "cup", int64(5)).Return(db.ErrNotEnoughItems)
+			},
+			expectedError: db.ErrNotEnoughItems,
+		},
+		{
+			name:       "error same user",
+			fromUserID: 1,
+			toUser:     "user1",
+			item:       "cup",
+			quantity:   1,
+			mockSetup: func() {
+				mockRepo.EXPECT().GiftItem(gomock.Any(), int64(1), "user1", "cup", int64(1)).Return(db.ErrIncorrectReciever)
+			},
+			expectedError: db.ErrIncorrectReciever,
+		},
+		{
+			name:       "error delete cache for receiver",
+			fromUserID: 1,
+			toUser:     "user3",
+			item:       "pen",
+			quantity:   2,
+			mockSetup: func() {
+				mockRepo.EXPECT().GiftItem(gomock.Any(), int64(1), "user3", "pen", int64(2)).Return(nil)
+				mockRedisRepo.EXPECT().DeleteInfo(gomock.Any(), redis.GetUserInfoCacheKey(int64(1))).Return(nil)
+				mockRepo.EXPECT().GetUserIDByUsername(gomock.Any(), "user3").Return(int64(3), nil)
+				mockRedisRepo.EXPECT().DeleteInfo(gomock.Any(), redis.GetUserInfoCacheKey(int64(3))).Return(ErrRandomDBError)
+			},
+			expectedError: ErrRandomDBError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			err := merchUC.GiftItem(context.Background(), tt.fromUserID, tt.toUser, tt.item, tt.quantity)
+
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
+
+func TestMerchUC_GrantCoins(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_merch.NewMockRepository(ctrl)
+	mockRedisRepo := mock_merch.NewMockRedisRepository(ctrl)
+	mockCatalogRedisRepo := mock_catalog.NewMockRedisRepository(ctrl)
+	cfg := &config.Config{}
+
+	merchUC := usecase.NewMerchUseCase(cfg, mockRepo, mockRedisRepo, mockCatalogRedisRepo)
+
+	service := m.Actor{Type: m.ActorService, ID: 7}
+
+	tests := []struct {
+		name          string
+		actor         m.Actor
+		grant         m.GrantCoinsRequest
+		mockSetup     func()
+		expectedError error
+	}{
+		{
+			name:  "success",
+			actor: service,
+			grant: m.GrantCoinsRequest{ToUser: "user2", Amount: 500, Comment: "bonus"},
+			mockSetup: func() {
+				mockRepo.EXPECT().GrantCoins(gomock.Any(), service, m.GrantCoinsRequest{ToUser: "user2", Amount: 500, Comment: "bonus"}).Return(int64(2), nil)
+				mockRedisRepo.EXPECT().DeleteInfo(gomock.Any(), redis.GetUserInfoCacheKey(int64(2))).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:  "error user not found",
+			actor: service,
+			grant: m.GrantCoinsRequest{ToUser: "nobody", Amount: 500},
+			mockSetup: func() {
+				mockRepo.EXPECT().GrantCoins(gomock.Any(), service, m.GrantCoinsRequest{ToUser: "nobody", Amount: 500}).Return(int64(0), db.ErrUserNotFound)
+			},
+			expectedError: db.ErrUserNotFound,
+		},
+		{
+			name:  "error delete cache",
+			actor: m.Actor{Type: m.ActorUser, ID: 1},
+			grant: m.GrantCoinsRequest{ToUser: "user3", Amount: 100},
+			mockSetup: func() {
+				mockRepo.EXPECT().GrantCoins(gomock.Any(), m.Actor{Type: m.ActorUser, ID: 1}, m.GrantCoinsRequest{ToUser: "user3", Amount: 100}).Return(int64(3), nil)
+				mockRedisRepo.EXPECT().DeleteInfo(gomock.Any(), redis.GetUserInfoCacheKey(int64(3))).Return(ErrRandomDBError)
+			},
+			expectedError: ErrRandomDBError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			err := merchUC.GrantCoins(context.Background(), tt.actor, tt.grant)
 
 			assert.Equal(t, tt.expectedError, err)
 		})
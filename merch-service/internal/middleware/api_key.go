@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/labstack/echo/v4"
+
+	m "cyansnbrst/merch-service/internal/models"
+	"cyansnbrst/merch-service/pkg/auth"
+	hh "cyansnbrst/merch-service/pkg/http_helpers"
+)
+
+const APIKeyHeader = "X-API-Key"
+
+// Service account authentication middleware, falls back to Authenticate when no API key is sent
+func (mw *Manager) AuthenticateAPIKey(next echo.HandlerFunc) echo.HandlerFunc {
+	authenticate := mw.Authenticate(next)
+
+	return func(c echo.Context) error {
+		key := c.Request().Header.Get(APIKeyHeader)
+		if key == "" {
+			return authenticate(c)
+		}
+
+		apiKey, err := mw.apiKeysUC.ValidateAPIKey(c.Request().Context(), key)
+		if err != nil {
+			if errors.Is(err, auth.ErrInvalidAPIKey) {
+				return hh.InvalidAuthenticationTokenResponse(c)
+			}
+			return hh.ServerErrorResponse(c, mw.logger, err)
+		}
+
+		ContextSetActor(c, m.Actor{Type: m.ActorService, ID: apiKey.ID})
+		ContextSetScopes(c, apiKey.Scopes)
+
+		return next(c)
+	}
+}
+
+// Scope access middleware, admins are granted every scope, must be used after AuthenticateAPIKey
+func (mw *Manager) RequireScope(scope string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			actor, err := ContextGetActor(c)
+			if err != nil {
+				return hh.ServerErrorResponse(c, mw.logger, err)
+			}
+
+			if actor.Type == m.ActorUser {
+				role, err := ContextGetRole(c)
+				if err != nil {
+					return hh.ServerErrorResponse(c, mw.logger, err)
+				}
+				if role != m.RoleAdmin {
+					return hh.NotPermittedResponse(c)
+				}
+				return next(c)
+			}
+
+			if !slices.Contains(ContextGetScopes(c), scope) {
+				return hh.NotPermittedResponse(c)
+			}
+
+			return next(c)
+		}
+	}
+}
@@ -2,12 +2,13 @@ package middleware
 
 import (
 	"errors"
+	"slices"
 	"strings"
 
 	"github.com/labstack/echo/v4"
 
+	m "cyansnbrst/merch-service/internal/models"
 	"cyansnbrst/merch-service/pkg/auth"
-	"cyansnbrst/merch-service/pkg/auth/jwt"
 	hh "cyansnbrst/merch-service/pkg/http_helpers"
 )
 
@@ -24,16 +25,37 @@ func (mw *Manager) Authenticate(next echo.HandlerFunc) echo.HandlerFunc {
 			return hh.InvalidAuthenticationTokenResponse(c)
 		}
 
-		userID, err := jwt.ParseJWT(token, mw.cfg.App.JWTSecretKey)
+		claims, err := mw.authUC.ValidateToken(c.Request().Context(), token)
 		if err != nil {
-			if errors.Is(err, auth.ErrInvalidToken) {
+			if errors.Is(err, auth.ErrInvalidToken) || errors.Is(err, auth.ErrRevokedToken) {
 				return hh.InvalidAuthenticationTokenResponse(c)
 			}
 			return hh.ServerErrorResponse(c, mw.logger, err)
 		}
 
-		ContextSetUserID(c, userID)
+		ContextSetUserID(c, claims.UserID)
+		ContextSetRole(c, claims.Role)
+		ContextSetClaims(c, claims)
+		ContextSetActor(c, m.Actor{Type: m.ActorUser, ID: claims.UserID})
 
 		return next(c)
 	}
 }
+
+// Role access middleware, must be used after Authenticate
+func (mw *Manager) RequireRole(roles ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			role, err := ContextGetRole(c)
+			if err != nil {
+				return hh.ServerErrorResponse(c, mw.logger, err)
+			}
+
+			if !slices.Contains(roles, role) {
+				return hh.NotPermittedResponse(c)
+			}
+
+			return next(c)
+		}
+	}
+}
@@ -4,9 +4,18 @@ import (
 	"errors"
 
 	"github.com/labstack/echo/v4"
+
+	m "cyansnbrst/merch-service/internal/models"
+	"cyansnbrst/merch-service/pkg/auth/jwt"
 )
 
-const UserContextKey = "user_id"
+const (
+	UserContextKey   = "user_id"
+	RoleContextKey   = "role"
+	ClaimsContextKey = "claims"
+	ActorContextKey  = "actor"
+	ScopesContextKey = "scopes"
+)
 
 // Set user ID to the context
 func ContextSetUserID(c echo.Context, userID int64) {
@@ -21,3 +30,56 @@ func ContextGetUserID(c echo.Context) (int64, error) {
 	}
 	return userID, nil
 }
+
+// Set user role to the context
+func ContextSetRole(c echo.Context, role string) {
+	c.Set(RoleContextKey, role)
+}
+
+// Get user role from the context
+func ContextGetRole(c echo.Context) (string, error) {
+	role, ok := c.Get(RoleContextKey).(string)
+	if !ok {
+		return "", errors.New("incorrect user role")
+	}
+	return role, nil
+}
+
+// Set access token claims to the context
+func ContextSetClaims(c echo.Context, claims *jwt.Claims) {
+	c.Set(ClaimsContextKey, claims)
+}
+
+// Get access token claims from the context
+func ContextGetClaims(c echo.Context) (*jwt.Claims, error) {
+	claims, ok := c.Get(ClaimsContextKey).(*jwt.Claims)
+	if !ok || claims == nil {
+		return nil, errors.New("incorrect token claims")
+	}
+	return claims, nil
+}
+
+// Set acting identity to the context
+func ContextSetActor(c echo.Context, actor m.Actor) {
+	c.Set(ActorContextKey, actor)
+}
+
+// Get acting identity from the context
+func ContextGetActor(c echo.Context) (m.Actor, error) {
+	actor, ok := c.Get(ActorContextKey).(m.Actor)
+	if !ok {
+		return m.Actor{}, errors.New("incorrect actor")
+	}
+	return actor, nil
+}
+
+// Set API key scopes to the context
+func ContextSetScopes(c echo.Context, scopes []string) {
+	c.Set(ScopesContextKey, scopes)
+}
+
+// Get API key scopes from the context, empty for users
+func ContextGetScopes(c echo.Context) []string {
+	scopes, _ := c.Get(ScopesContextKey).([]string)
+	return scopes
+}
@@ -4,18 +4,24 @@ import (
 	"go.uber.org/zap"
 
 	"cyansnbrst/merch-service/config"
+	"cyansnbrst/merch-service/internal/apikeys"
+	"cyansnbrst/merch-service/internal/auth"
 )
 
 // Middleware manager struct
 type Manager struct {
-	cfg    *config.Config
-	logger *zap.Logger
+	cfg       *config.Config
+	authUC    auth.UseCase
+	apiKeysUC apikeys.UseCase
+	logger    *zap.Logger
 }
 
 // Middleware manager constructor
-func NewManager(cfg *config.Config, logger *zap.Logger) *Manager {
+func NewManager(cfg *config.Config, authUC auth.UseCase, apiKeysUC apikeys.UseCase, logger *zap.Logger) *Manager {
 	return &Manager{
-		cfg:    cfg,
-		logger: logger,
+		cfg:       cfg,
+		authUC:    authUC,
+		apiKeysUC: apiKeysUC,
+		logger:    logger,
 	}
 }
@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// API key scopes
+const (
+	ScopeGrantCoins = "coins:grant"
+)
+
+// Transaction actor types
+const (
+	ActorUser    = "user"
+	ActorService = "service"
+)
+
+// Service account API key
+type APIKey struct {
+	ID         int64      `json:"id"`
+	Name       string     `json:"name"`
+	Scopes     []string   `json:"scopes"`
+	CreatedBy  *int64     `json:"created_by,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+// Create API key request
+type CreateAPIKeyRequest struct {
+	Name   string   `json:"name" validate:"required,max=64"`
+	Scopes []string `json:"scopes" validate:"required,min=1,dive,oneof=coins:grant"`
+}
+
+// Created API key response, the key itself is shown only once
+type CreateAPIKeyResponse struct {
+	APIKey
+	Key string `json:"key"`
+}
+
+// Identity performing an operation, either a user or a service account
+type Actor struct {
+	Type string
+	ID   int64
+}
@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 // Auth request
 type AuthRequest struct {
 	Username string `json:"username" validate:"required,min=4,max=20"`
@@ -8,5 +10,60 @@ type AuthRequest struct {
 
 // Auth response
 type AuthResponse struct {
-	Token string `json:"token"`
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// Refresh token request
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+// Change password request
+type ChangePasswordRequest struct {
+	OldPassword string `json:"old_password" validate:"required"`
+	NewPassword string `json:"new_password" validate:"required,min=4,max=20"`
+}
+
+// Reset password with one-time token request
+type ResetPasswordRequest struct {
+	ResetToken  string `json:"reset_token" validate:"required"`
+	NewPassword string `json:"new_password" validate:"required,min=4,max=20"`
+}
+
+// One-time password reset token response
+type PasswordResetResponse struct {
+	ResetToken string    `json:"reset_token"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// Refresh token model
+type RefreshToken struct {
+	ID        int64      `db:"id"`
+	UserID    int64      `db:"user_id"`
+	FamilyID  string     `db:"family_id"`
+	TokenHash string     `db:"token_hash"`
+	ExpiresAt time.Time  `db:"expires_at"`
+	UsedAt    *time.Time `db:"used_at"`
+	RevokedAt *time.Time `db:"revoked_at"`
+}
+
+// Client the user logs in from
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+}
+
+// Login session, spans one refresh token family
+type Session struct {
+	ID        string     `db:"id" json:"id"`
+	UserID    int64      `db:"user_id" json:"-"`
+	TokenID   string     `db:"token_id" json:"-"`
+	IssuedAt  time.Time  `db:"issued_at" json:"issued_at"`
+	ExpiresAt time.Time  `db:"expires_at" json:"expires_at"`
+	UserAgent string     `db:"user_agent" json:"user_agent"`
+	IP        string     `db:"ip" json:"ip"`
+	CreatedAt time.Time  `db:"created_at" json:"created_at"`
+	RevokedAt *time.Time `db:"revoked_at" json:"-"`
+	Current   bool       `db:"-" json:"current"`
 }
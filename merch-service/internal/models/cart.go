@@ -0,0 +1,12 @@
+package models
+
+// Cart item struct
+type CartItem struct {
+	Item     string `json:"item" validate:"required,max=255"`
+	Quantity int64  `json:"quantity" validate:"required,min=1,max=1000"`
+}
+
+// Cart struct
+type Cart struct {
+	Items []CartItem `json:"items"`
+}
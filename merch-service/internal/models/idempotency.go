@@ -0,0 +1,26 @@
+package models
+
+const (
+	IdempotencySucceeded = "succeeded"
+	IdempotencyFailed    = "failed"
+)
+
+// Idempotency key sent by the client with the fingerprint of the request
+type IdempotencyKey struct {
+	Key         string
+	Fingerprint string
+}
+
+// Stored outcome of the request completed with the idempotency key
+type IdempotencyRecord struct {
+	Fingerprint string              `json:"fingerprint"`
+	Status      string              `json:"status"`
+	Response    *IdempotencyFailure `json:"response,omitempty"`
+}
+
+// Error the request failed with, replayed on retries
+type IdempotencyFailure struct {
+	Error     string `json:"error"`
+	Limit     string `json:"limit,omitempty"`
+	Remaining int64  `json:"remaining,omitempty"`
+}
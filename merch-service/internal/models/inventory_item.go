@@ -5,3 +5,16 @@ type InventoryItem struct {
 	Type     string `db:"type" json:"type"`
 	Quantity int64  `db:"quantity" json:"quantity"`
 }
+
+// Buy item request
+type BuyItemRequest struct {
+	Item     string `param:"item" validate:"required"`
+	Quantity int64  `query:"quantity" validate:"omitempty,min=1,max=1000"`
+}
+
+// Gift item request
+type GiftItemRequest struct {
+	ToUser   string `json:"to_user" validate:"required"`
+	Item     string `json:"item" validate:"required"`
+	Quantity int64  `json:"quantity" validate:"required,min=1,max=1000"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// Invoice statuses
+const (
+	InvoiceStatusPending  = "pending"
+	InvoiceStatusAccepted = "accepted"
+	InvoiceStatusDeclined = "declined"
+)
+
+// Request for coins, paid by FromUser to ToUser, IDs of deleted users are 0
+type Invoice struct {
+	ID         int64      `db:"id" json:"id"`
+	FromID     int64      `db:"from_id" json:"-"`
+	FromUser   string     `db:"from_user" json:"from_user"`
+	ToID       int64      `db:"to_id" json:"-"`
+	ToUser     string     `db:"to_user" json:"to_user"`
+	Amount     int64      `db:"amount" json:"amount"`
+	Comment    string     `db:"comment" json:"comment,omitempty"`
+	Status     string     `db:"status" json:"status"`
+	CreatedAt  time.Time  `db:"created_at" json:"created_at"`
+	ResolvedAt *time.Time `db:"resolved_at" json:"resolved_at,omitempty"`
+}
+
+// Create invoice request
+type CreateInvoiceRequest struct {
+	FromUser string `json:"from_user" validate:"required"`
+	Amount   int64  `json:"amount" validate:"required,min=1"`
+	Comment  string `json:"comment" validate:"omitempty,max=255"`
+}
+
+// Invoice list filter
+type InvoiceFilter struct {
+	Direction string `query:"direction" validate:"required,oneof=incoming outgoing"`
+}
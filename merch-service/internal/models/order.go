@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// Order struct, UserID is 0 if the owner is deleted
+type Order struct {
+	ID         int64      `db:"id" json:"id"`
+	UserID     int64      `db:"user_id" json:"-"`
+	Item       string     `db:"item" json:"item"`
+	Price      int64      `db:"price" json:"price"`
+	Quantity   int64      `db:"quantity" json:"quantity"`
+	CreatedAt  time.Time  `db:"created_at" json:"created_at"`
+	RefundedAt *time.Time `db:"refunded_at" json:"refunded_at,omitempty"`
+}
@@ -4,8 +4,46 @@ import "time"
 
 // Product struct
 type Product struct {
-	ID        int64     `db:"id"`
-	Name      string    `db:"name"`
-	Price     int64     `db:"price"`
-	CreatedAt time.Time `db:"created_at"`
+	ID        int64      `db:"id" json:"id"`
+	Name      string     `db:"name" json:"name"`
+	Price     int64      `db:"price" json:"price"`
+	Stock     *int64     `db:"stock" json:"stock"`
+	CreatedAt time.Time  `db:"created_at" json:"created_at"`
+	RetiredAt *time.Time `db:"retired_at" json:"retired_at,omitempty"`
+}
+
+// Create product request
+type CreateProductRequest struct {
+	Name  string `json:"name" validate:"required,max=255"`
+	Price int64  `json:"price" validate:"required,min=1"`
+	Stock *int64 `json:"stock" validate:"omitempty,min=0"`
+}
+
+// Update product price request
+type UpdatePriceRequest struct {
+	Price int64 `json:"price" validate:"required,min=1"`
+}
+
+// Update product stock request, null stock means unlimited
+type UpdateStockRequest struct {
+	Stock *int64 `json:"stock" validate:"omitempty,min=0"`
+}
+
+// Rename product request
+type RenameProductRequest struct {
+	Name string `json:"name" validate:"required,max=255"`
+}
+
+// Catalog item struct
+type CatalogItem struct {
+	Name      string `db:"name" json:"name"`
+	Price     int64  `db:"price" json:"price"`
+	Stock     *int64 `db:"stock" json:"stock,omitempty"`
+	Available bool   `json:"available"`
+}
+
+// Catalog filter
+type CatalogFilter struct {
+	Sort       string `query:"sort" validate:"omitempty,oneof=price_asc price_desc"`
+	Affordable bool   `query:"affordable"`
 }
@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 // Transaction history struct
 type TransactionHistory struct {
 	Received []ReceiveTransaction `json:"received"`
@@ -8,12 +10,63 @@ type TransactionHistory struct {
 
 // Receive transaction struct
 type ReceiveTransaction struct {
-	FromUser string `db:"from_user" json:"from_user"`
-	Amount   int64  `db:"amount" json:"amount"`
+	ID       int64     `db:"id" json:"id"`
+	FromUser string    `db:"from_user" json:"from_user"`
+	Amount   int64     `db:"amount" json:"amount"`
+	Comment  string    `db:"comment" json:"comment,omitempty"`
+	Date     time.Time `db:"transaction_date" json:"date"`
 }
 
 // Send transaction struct
 type SendTransaction struct {
-	ToUser string `db:"to_user" json:"to_user" validate:"required"`
-	Amount int64  `db:"amount" json:"amount" validate:"required,min=1"`
+	ID      int64     `db:"id" json:"id"`
+	ToUser  string    `db:"to_user" json:"to_user"`
+	Amount  int64     `db:"amount" json:"amount"`
+	Comment string    `db:"comment" json:"comment,omitempty"`
+	Date    time.Time `db:"transaction_date" json:"date"`
+}
+
+// Transaction history filter
+type HistoryFilter struct {
+	Cursor       int64      `query:"cursor" validate:"omitempty,min=1"`
+	Direction    string     `query:"direction" validate:"omitempty,oneof=sent received"`
+	Counterparty string     `query:"counterparty" validate:"omitempty,max=255"`
+	From         *time.Time `query:"from"`
+	To           *time.Time `query:"to"`
+	Limit        int64      `query:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+// Transaction history entry
+type HistoryEntry struct {
+	ID           int64     `db:"id" json:"id"`
+	Direction    string    `db:"direction" json:"direction"`
+	Counterparty string    `db:"counterparty" json:"counterparty"`
+	Amount       int64     `db:"amount" json:"amount"`
+	Comment      string    `db:"comment" json:"comment,omitempty"`
+	Date         time.Time `db:"transaction_date" json:"date"`
+}
+
+// Transaction history page
+type HistoryPage struct {
+	Transactions []HistoryEntry `json:"transactions"`
+	NextCursor   *int64         `json:"next_cursor,omitempty"`
+}
+
+// Send coins request
+type SendCoinRequest struct {
+	ToUser  string `json:"to_user" validate:"required"`
+	Amount  int64  `json:"amount" validate:"required,min=1"`
+	Comment string `json:"comment" validate:"omitempty,max=255"`
+}
+
+// Send coins to several users request
+type SendCoinBatchRequest struct {
+	Transfers []SendCoinRequest `json:"transfers" validate:"required,min=1,max=100,dive"`
+}
+
+// Grant coins request
+type GrantCoinsRequest struct {
+	ToUser  string `json:"to_user" validate:"required"`
+	Amount  int64  `json:"amount" validate:"required,min=1"`
+	Comment string `json:"comment" validate:"omitempty,max=255"`
 }
@@ -2,19 +2,35 @@ package models
 
 import "time"
 
+// User roles
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
 // User model
 type User struct {
-	ID           int64     `db:"id"`
-	Username     string    `db:"username"`
-	PasswordHash string    `db:"password_hash"`
-	Balance      int64     `db:"balance"`
-	CreatedAt    time.Time `db:"created_at"`
+	ID            int64      `db:"id"`
+	Username      string     `db:"username"`
+	PasswordHash  string     `db:"password_hash"`
+	Balance       int64      `db:"balance"`
+	Role          string     `db:"role"`
+	CreatedAt     time.Time  `db:"created_at"`
+	DeactivatedAt *time.Time `db:"deactivated_at"`
 }
 
 // User info response model
 type InfoResponse struct {
 	CoinsInventory
-	CoinHistory *TransactionHistory `json:"coin_history"`
+	CoinHistory     *TransactionHistory `json:"coin_history"`
+	PurchaseHistory []Order             `json:"purchase_history"`
+	Allowance       *TransferAllowance  `json:"remaining_allowance,omitempty"`
+}
+
+// Coins user can still transfer today, omitted when there is no limit
+type TransferAllowance struct {
+	Send    *int64 `json:"send,omitempty"`
+	Receive *int64 `json:"receive,omitempty"`
 }
 
 // Coins and inventory
@@ -22,3 +38,8 @@ type CoinsInventory struct {
 	Coins     int64           `json:"coins"`
 	Inventory []InventoryItem `json:"inventory"`
 }
+
+// Set user role request model
+type SetRoleRequest struct {
+	Role string `json:"role" validate:"required,oneof=user admin"`
+}
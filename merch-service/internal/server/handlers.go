@@ -1,19 +1,37 @@
 package server
 
 import (
+	"net"
+
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	echoSwagger "github.com/swaggo/echo-swagger"
 
 	// swagger docs
 	_ "cyansnbrst/merch-service/docs"
+	apiKeysHTTP "cyansnbrst/merch-service/internal/apikeys/delivery/http"
+	apiKeysRepository "cyansnbrst/merch-service/internal/apikeys/repository"
+	apiKeysUseCase "cyansnbrst/merch-service/internal/apikeys/usecase"
 	authHTTP "cyansnbrst/merch-service/internal/auth/delivery/http"
 	authRepository "cyansnbrst/merch-service/internal/auth/repository"
 	authUseCase "cyansnbrst/merch-service/internal/auth/usecase"
+	cartHTTP "cyansnbrst/merch-service/internal/cart/delivery/http"
+	cartRepository "cyansnbrst/merch-service/internal/cart/repository"
+	cartUseCase "cyansnbrst/merch-service/internal/cart/usecase"
+	catalogHTTP "cyansnbrst/merch-service/internal/catalog/delivery/http"
+	catalogRepository "cyansnbrst/merch-service/internal/catalog/repository"
+	catalogUseCase "cyansnbrst/merch-service/internal/catalog/usecase"
+	invoiceHTTP "cyansnbrst/merch-service/internal/invoice/delivery/http"
+	invoiceRepository "cyansnbrst/merch-service/internal/invoice/repository"
+	invoiceUseCase "cyansnbrst/merch-service/internal/invoice/usecase"
 	merchHTTP "cyansnbrst/merch-service/internal/merch/delivery/http"
 	merchRepository "cyansnbrst/merch-service/internal/merch/repository"
 	merchUseCase "cyansnbrst/merch-service/internal/merch/usecase"
 	mm "cyansnbrst/merch-service/internal/middleware"
+	"cyansnbrst/merch-service/internal/models"
+	usersHTTP "cyansnbrst/merch-service/internal/users/delivery/http"
+	usersRepository "cyansnbrst/merch-service/internal/users/repository"
+	usersUseCase "cyansnbrst/merch-service/internal/users/usecase"
 	"cyansnbrst/merch-service/pkg/validator"
 )
 
@@ -26,26 +44,87 @@ func (s *Server) RegisterHandlers() *echo.Echo {
 	e.Use(middleware.Recover())
 
 	e.Validator = validator.NewCustomValidator()
+	e.IPExtractor = s.ipExtractor()
 
 	authRepo := authRepository.NewAuthRepo(s.db)
-	merchRepo := merchRepository.NewMerchRepo(s.db)
+	authRedisRepo := authRepository.NewAuthRedisRepo(s.config, s.redisClient)
+	merchRepo := merchRepository.NewMerchRepo(s.config, s.db)
 	merchRedisRepo := merchRepository.NewMerchRedisRepo(s.config, s.redisClient)
+	catalogRepo := catalogRepository.NewCatalogRepo(s.db)
+	catalogRedisRepo := catalogRepository.NewCatalogRedisRepo(s.config, s.redisClient)
+	cartRedisRepo := cartRepository.NewCartRedisRepo(s.config, s.redisClient)
+	invoiceRepo := invoiceRepository.NewInvoiceRepo(s.db)
+	usersRepo := usersRepository.NewUsersRepo(s.db)
+	apiKeysRepo := apiKeysRepository.NewAPIKeysRepo(s.db)
 
-	authUC := authUseCase.NewAuthUseCase(s.config, authRepo)
-	merchUC := merchUseCase.NewMerchUseCase(merchRepo, merchRedisRepo)
+	authUC := authUseCase.NewAuthUseCase(s.config, authRepo, authRedisRepo, s.keys)
+	merchUC := merchUseCase.NewMerchUseCase(s.config, merchRepo, merchRedisRepo, catalogRedisRepo)
+	catalogUC := catalogUseCase.NewCatalogUseCase(catalogRepo, catalogRedisRepo)
+	cartUC := cartUseCase.NewCartUseCase(cartRedisRepo, catalogUC, merchUC)
+	invoiceUC := invoiceUseCase.NewInvoiceUseCase(invoiceRepo, merchUC)
+	usersUC := usersUseCase.NewUsersUseCase(usersRepo, merchRedisRepo, authUC)
+	apiKeysUC := apiKeysUseCase.NewAPIKeysUseCase(apiKeysRepo)
 
 	authHandlers := authHTTP.NewAuthHandlers(authUC, s.logger)
 	merchHandlers := merchHTTP.NewMerchHandlers(merchUC, s.logger)
+	catalogHandlers := catalogHTTP.NewCatalogHandlers(catalogUC, s.logger)
+	cartHandlers := cartHTTP.NewCartHandlers(cartUC, s.logger)
+	invoiceHandlers := invoiceHTTP.NewInvoiceHandlers(invoiceUC, s.logger)
+	usersHandlers := usersHTTP.NewUsersHandlers(usersUC, s.logger)
+	apiKeysHandlers := apiKeysHTTP.NewAPIKeysHandlers(apiKeysUC, s.logger)
+
+	mw := mm.NewManager(s.config, authUC, apiKeysUC, s.logger)
 
-	mw := mm.NewManager(s.config, s.logger)
+	authHTTP.RegisterWellKnownRoutes(e.Group("/.well-known"), authHandlers)
 
 	api := e.Group("/api")
 	protectedAPI := api.Group("")
 
 	protectedAPI.Use(mw.Authenticate)
 
+	adminAPI := protectedAPI.Group("/admin", mw.RequireRole(models.RoleAdmin))
+
+	grantsAPI := api.Group("/grants", mw.AuthenticateAPIKey, mw.RequireScope(models.ScopeGrantCoins))
+
 	authHTTP.RegisterAuthRoutes(api, authHandlers)
+	if s.config.App.LegacyAuth {
+		authHTTP.RegisterLegacyAuthRoutes(api, authHandlers)
+	}
+	authHTTP.RegisterAuthProtectedRoutes(protectedAPI, authHandlers)
 	merchHTTP.RegisterMerchRoutes(protectedAPI, merchHandlers)
+	catalogHTTP.RegisterCatalogRoutes(protectedAPI, catalogHandlers)
+	cartHTTP.RegisterCartRoutes(protectedAPI, cartHandlers)
+	invoiceHTTP.RegisterInvoiceRoutes(protectedAPI, invoiceHandlers)
+	authHTTP.RegisterAuthAdminRoutes(adminAPI, authHandlers)
+	catalogHTTP.RegisterCatalogAdminRoutes(adminAPI, catalogHandlers)
+	merchHTTP.RegisterMerchAdminRoutes(adminAPI, merchHandlers)
+	usersHTTP.RegisterUsersAdminRoutes(adminAPI, usersHandlers)
+	apiKeysHTTP.RegisterAPIKeysAdminRoutes(adminAPI, apiKeysHandlers)
+	merchHTTP.RegisterMerchGrantRoutes(grantsAPI, merchHandlers)
 
 	return e
 }
+
+// Take client IP from X-Forwarded-For only when the request came through a trusted proxy,
+// otherwise from the connection, so clients can't spoof it
+func (s *Server) ipExtractor() echo.IPExtractor {
+	if len(s.config.App.TrustedProxies) == 0 {
+		return echo.ExtractIPDirect()
+	}
+
+	options := []echo.TrustOption{
+		echo.TrustLoopback(false),
+		echo.TrustLinkLocal(false),
+		echo.TrustPrivateNet(false),
+	}
+	for _, cidr := range s.config.App.TrustedProxies {
+		// Networks are validated when the config is loaded
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			continue
+		}
+		options = append(options, echo.TrustIPRange(network))
+	}
+
+	return echo.ExtractIPFromXFFHeader(options...)
+}
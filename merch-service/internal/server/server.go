@@ -14,6 +14,8 @@ import (
 	"go.uber.org/zap"
 
 	"cyansnbrst/merch-service/config"
+	usersRepository "cyansnbrst/merch-service/internal/users/repository"
+	"cyansnbrst/merch-service/pkg/auth/jwt"
 )
 
 // Server struct
@@ -22,20 +24,26 @@ type Server struct {
 	logger      *zap.Logger
 	db          *pgxpool.Pool
 	redisClient *redis.Client
+	keys        *jwt.Keys
 }
 
 // New server constructor
-func NewServer(cfg *config.Config, logger *zap.Logger, db *pgxpool.Pool, redisClient *redis.Client) *Server {
+func NewServer(cfg *config.Config, logger *zap.Logger, db *pgxpool.Pool, redisClient *redis.Client, keys *jwt.Keys) *Server {
 	return &Server{
 		config:      cfg,
 		logger:      logger,
 		db:          db,
 		redisClient: redisClient,
+		keys:        keys,
 	}
 }
 
 // Run server
 func (s *Server) Run() error {
+	if err := s.bootstrapAdmins(); err != nil {
+		return err
+	}
+
 	addr := fmt.Sprintf(":%d", s.config.App.HTTPPort)
 	server := &http.Server{
 		Addr:         addr,
@@ -89,3 +97,21 @@ func (s *Server) Run() error {
 
 	return nil
 }
+
+// Promote users configured as admins, existing admins are kept
+func (s *Server) bootstrapAdmins() error {
+	if len(s.config.App.AdminUserIDs) == 0 {
+		return nil
+	}
+
+	promoted, err := usersRepository.NewUsersRepo(s.db).PromoteAdmins(context.Background(), s.config.App.AdminUserIDs)
+	if err != nil {
+		return err
+	}
+
+	s.logger.Info("admins bootstrapped",
+		zap.Int64("promoted", promoted),
+	)
+
+	return nil
+}
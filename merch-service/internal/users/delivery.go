@@ -0,0 +1,11 @@
+package users
+
+import "github.com/labstack/echo/v4"
+
+// Users handlers interface
+type Handlers interface {
+	SetRole(c echo.Context) error
+	DeactivateUser(c echo.Context) error
+	ReactivateUser(c echo.Context) error
+	DeleteUser(c echo.Context) error
+}
@@ -0,0 +1,173 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"cyansnbrst/merch-service/internal/middleware"
+	m "cyansnbrst/merch-service/internal/models"
+	"cyansnbrst/merch-service/internal/users"
+	"cyansnbrst/merch-service/internal/users/usecase"
+	"cyansnbrst/merch-service/pkg/db"
+	hh "cyansnbrst/merch-service/pkg/http_helpers"
+)
+
+// Users handlers struct
+type usersHandlers struct {
+	usersUC users.UseCase
+	logger  *zap.Logger
+}
+
+// Users handlers constructor
+func NewUsersHandlers(usersUC users.UseCase, logger *zap.Logger) users.Handlers {
+	return &usersHandlers{
+		usersUC: usersUC,
+		logger:  logger,
+	}
+}
+
+// @Summary		Set user role
+// @Description	Grant or revoke a role. All the user's sessions are ended, so the new role applies on the next login.
+// @Tags		admin
+// @Accept		json
+// @Param		id		path	int						true	"user id"
+// @Param		input	body	models.SetRoleRequest	true	"input"
+// @Success		200	"successful"
+// @Failure		400	{object}	httphelpers.ErrorResponse	"bad request"
+// @Failure		401	{object}	httphelpers.ErrorResponse	"authentication required"
+// @Failure		403	{object}	httphelpers.ErrorResponse	"not permitted"
+// @Failure		404	{object}	httphelpers.ErrorResponse	"user not found"
+// @Failure		500	{object}	httphelpers.ErrorResponse	"internal server error"
+// @Security 	JWT
+// @Router		/admin/users/{id}/role [put]
+func (h *usersHandlers) SetRole(c echo.Context) error {
+	adminID, err := middleware.ContextGetUserID(c)
+	if err != nil {
+		return hh.ServerErrorResponse(c, h.logger, err)
+	}
+
+	userID, err := hh.ReadIDParam(c)
+	if err != nil {
+		return hh.BadRequestResponse(c, err)
+	}
+
+	var input m.SetRoleRequest
+	if err := c.Bind(&input); err != nil {
+		return hh.BadRequestResponse(c, err)
+	}
+
+	if err := c.Validate(input); err != nil {
+		return hh.BadRequestResponse(c, err)
+	}
+
+	if err := h.usersUC.SetRole(c.Request().Context(), adminID, userID, input.Role); err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrOwnRoleChange):
+			return hh.BadRequestResponse(c, err)
+		case errors.Is(err, db.ErrUserNotFound):
+			return hh.NotFoundResponse(c, err)
+		}
+		return hh.ServerErrorResponse(c, h.logger, err)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+// @Summary		Deactivate user
+// @Description	Block the user from logging in and receiving coins. All their sessions are ended.
+// @Tags		admin
+// @Param		id	path	int	true	"user id"
+// @Success		200	"successful"
+// @Failure		400	{object}	httphelpers.ErrorResponse	"bad request"
+// @Failure		401	{object}	httphelpers.ErrorResponse	"authentication required"
+// @Failure		403	{object}	httphelpers.ErrorResponse	"not permitted"
+// @Failure		404	{object}	httphelpers.ErrorResponse	"user not found"
+// @Failure		500	{object}	httphelpers.ErrorResponse	"internal server error"
+// @Security 	JWT
+// @Router		/admin/users/{id}/deactivate [post]
+func (h *usersHandlers) DeactivateUser(c echo.Context) error {
+	adminID, err := middleware.ContextGetUserID(c)
+	if err != nil {
+		return hh.ServerErrorResponse(c, h.logger, err)
+	}
+
+	userID, err := hh.ReadIDParam(c)
+	if err != nil {
+		return hh.BadRequestResponse(c, err)
+	}
+
+	if err := h.usersUC.DeactivateUser(c.Request().Context(), adminID, userID); err != nil {
+		return h.accountErrorResponse(c, err)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+// @Summary		Reactivate user
+// @Description	Allow a deactivated user to log in and receive coins again.
+// @Tags		admin
+// @Param		id	path	int	true	"user id"
+// @Success		200	"successful"
+// @Failure		400	{object}	httphelpers.ErrorResponse	"bad request"
+// @Failure		401	{object}	httphelpers.ErrorResponse	"authentication required"
+// @Failure		403	{object}	httphelpers.ErrorResponse	"not permitted"
+// @Failure		404	{object}	httphelpers.ErrorResponse	"user not found"
+// @Failure		500	{object}	httphelpers.ErrorResponse	"internal server error"
+// @Security 	JWT
+// @Router		/admin/users/{id}/reactivate [post]
+func (h *usersHandlers) ReactivateUser(c echo.Context) error {
+	userID, err := hh.ReadIDParam(c)
+	if err != nil {
+		return hh.BadRequestResponse(c, err)
+	}
+
+	if err := h.usersUC.ReactivateUser(c.Request().Context(), userID); err != nil {
+		return h.accountErrorResponse(c, err)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+// @Summary		Delete user
+// @Description	Delete the user with their inventory. Their orders, transactions and invoices are kept without the owner, pending invoices are declined.
+// @Tags		admin
+// @Param		id	path	int	true	"user id"
+// @Success		200	"successful"
+// @Failure		400	{object}	httphelpers.ErrorResponse	"bad request"
+// @Failure		401	{object}	httphelpers.ErrorResponse	"authentication required"
+// @Failure		403	{object}	httphelpers.ErrorResponse	"not permitted"
+// @Failure		404	{object}	httphelpers.ErrorResponse	"user not found"
+// @Failure		500	{object}	httphelpers.ErrorResponse	"internal server error"
+// @Security 	JWT
+// @Router		/admin/users/{id} [delete]
+func (h *usersHandlers) DeleteUser(c echo.Context) error {
+	adminID, err := middleware.ContextGetUserID(c)
+	if err != nil {
+		return hh.ServerErrorResponse(c, h.logger, err)
+	}
+
+	userID, err := hh.ReadIDParam(c)
+	if err != nil {
+		return hh.BadRequestResponse(c, err)
+	}
+
+	if err := h.usersUC.DeleteUser(c.Request().Context(), adminID, userID); err != nil {
+		return h.accountErrorResponse(c, err)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+// Map account management errors to responses
+func (h *usersHandlers) accountErrorResponse(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrOwnAccountChange):
+		return hh.BadRequestResponse(c, err)
+	case errors.Is(err, db.ErrUserNotFound):
+		return hh.NotFoundResponse(c, err)
+	}
+	return hh.ServerErrorResponse(c, h.logger, err)
+}
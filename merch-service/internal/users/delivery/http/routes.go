@@ -0,0 +1,15 @@
+package http
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"cyansnbrst/merch-service/internal/users"
+)
+
+// Register users admin routes
+func RegisterUsersAdminRoutes(g *echo.Group, h users.Handlers) {
+	g.PUT("/users/:id/role", h.SetRole)
+	g.POST("/users/:id/deactivate", h.DeactivateUser)
+	g.POST("/users/:id/reactivate", h.ReactivateUser)
+	g.DELETE("/users/:id", h.DeleteUser)
+}
@@ -0,0 +1,11 @@
+package users
+
+import "context"
+
+// Users repository interface
+type Repository interface {
+	SetRole(ctx context.Context, userID int64, role string) error
+	PromoteAdmins(ctx context.Context, userIDs []int64) (int64, error)
+	SetDeactivated(ctx context.Context, userID int64, deactivated bool) error
+	DeleteUser(ctx context.Context, userID int64) ([]int64, error)
+}
@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"cyansnbrst/merch-service/internal/users"
+	"cyansnbrst/merch-service/pkg/db"
+)
+
+// Users repository struct
+type usersRepo struct {
+	db *pgxpool.Pool
+}
+
+// Users repository constructor
+func NewUsersRepo(db *pgxpool.Pool) users.Repository {
+	return &usersRepo{db: db}
+}
+
+// Change user's role
+func (r *usersRepo) SetRole(ctx context.Context, userID int64, role string) error {
+	query := `
+		UPDATE users
+		SET role = $2
+		WHERE id = $1
+	`
+
+	result, err := r.db.Exec(ctx, query, userID, role)
+	if err != nil {
+		return fmt.Errorf("repo - failed to set user role: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return db.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// Give admin role to the users, returning how many were promoted
+func (r *usersRepo) PromoteAdmins(ctx context.Context, userIDs []int64) (int64, error) {
+	query := `
+		UPDATE users
+		SET role = 'admin'
+		WHERE id = ANY($1) AND role <> 'admin'
+	`
+
+	result, err := r.db.Exec(ctx, query, userIDs)
+	if err != nil {
+		return 0, fmt.Errorf("repo - failed to promote admins: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
+
+// Deactivate or reactivate user, deactivation time is kept on repeated calls
+func (r *usersRepo) SetDeactivated(ctx context.Context, userID int64, deactivated bool) error {
+	query := `
+		UPDATE users
+		SET deactivated_at = CASE WHEN $2::boolean THEN COALESCE(deactivated_at, CURRENT_TIMESTAMP) END
+		WHERE id = $1
+	`
+
+	result, err := r.db.Exec(ctx, query, userID, deactivated)
+	if err != nil {
+		return fmt.Errorf("repo - failed to set user deactivation: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return db.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// Delete user, their transactions and invoices are kept without the reference,
+// invoices still pending are declined since they can't be paid anymore.
+// Returns IDs of the users they had transactions with
+func (r *usersRepo) DeleteUser(ctx context.Context, userID int64) ([]int64, error) {
+	var counterpartyIDs []int64
+	err := r.execTx(ctx, func(tx pgx.Tx) error {
+		var err error
+		counterpartyIDs, err = r.getCounterpartyIDs(ctx, tx, userID)
+		if err != nil {
+			return err
+		}
+
+		invoicesQuery := `
+			UPDATE invoices
+			SET status = 'declined', resolved_at = CURRENT_TIMESTAMP
+			WHERE status = 'pending' AND (from_id = $1 OR to_id = $1)
+		`
+		if _, err := tx.Exec(ctx, invoicesQuery, userID); err != nil {
+			return fmt.Errorf("repo - failed to decline invoices: %w", err)
+		}
+
+		query := `DELETE FROM users WHERE id = $1`
+		result, err := tx.Exec(ctx, query, userID)
+		if err != nil {
+			return fmt.Errorf("repo - failed to delete user: %w", err)
+		}
+
+		if result.RowsAffected() == 0 {
+			return db.ErrUserNotFound
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return counterpartyIDs, nil
+}
+
+// Get IDs of the users the user has transactions with
+func (r *usersRepo) getCounterpartyIDs(ctx context.Context, tx pgx.Tx, userID int64) ([]int64, error) {
+	query := `
+		SELECT to_id FROM transactions WHERE from_id = $1 AND to_id IS NOT NULL
+		UNION
+		SELECT from_id FROM transactions WHERE to_id = $1 AND from_id IS NOT NULL
+	`
+
+	rows, err := tx.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("repo - failed to get counterparties: %w", err)
+	}
+	defer rows.Close()
+
+	userIDs := make([]int64, 0)
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("repo - failed to scan row: %w", err)
+		}
+		userIDs = append(userIDs, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("repo - rows iteration error: %w", err)
+	}
+
+	return userIDs, nil
+}
+
+// Execute a transaction
+func (r *usersRepo) execTx(ctx context.Context, fn func(tx pgx.Tx) error) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("repo - failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil {
+			if err != pgx.ErrTxClosed {
+				log.Printf("repo - failed to rollback transaction: %v", err)
+			}
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("repo - failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
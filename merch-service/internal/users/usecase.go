@@ -0,0 +1,11 @@
+package users
+
+import "context"
+
+// Users usecase interface
+type UseCase interface {
+	SetRole(ctx context.Context, adminID, userID int64, role string) error
+	DeactivateUser(ctx context.Context, adminID, userID int64) error
+	ReactivateUser(ctx context.Context, userID int64) error
+	DeleteUser(ctx context.Context, adminID, userID int64) error
+}
@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"cyansnbrst/merch-service/internal/auth"
+	"cyansnbrst/merch-service/internal/merch"
+	"cyansnbrst/merch-service/internal/users"
+	"cyansnbrst/merch-service/pkg/db/redis"
+)
+
+var (
+	ErrOwnRoleChange    = errors.New("cannot change own role")
+	ErrOwnAccountChange = errors.New("cannot deactivate or delete own account")
+)
+
+// Users usecase struct
+type usersUC struct {
+	usersRepo      users.Repository
+	merchRedisRepo merch.RedisRepository
+	authUC         auth.UseCase
+}
+
+// Users usecase constructor
+func NewUsersUseCase(usersRepo users.Repository, merchRedisRepo merch.RedisRepository, authUC auth.UseCase) users.UseCase {
+	return &usersUC{
+		usersRepo:      usersRepo,
+		merchRedisRepo: merchRedisRepo,
+		authUC:         authUC,
+	}
+}
+
+// Change user's role and log them out everywhere, so tokens with the old role stop working
+func (u *usersUC) SetRole(ctx context.Context, adminID, userID int64, role string) error {
+	if adminID == userID {
+		return ErrOwnRoleChange
+	}
+
+	if err := u.usersRepo.SetRole(ctx, userID, role); err != nil {
+		return err
+	}
+
+	return u.authUC.RevokeAllSessions(ctx, userID)
+}
+
+// Deactivate user and log them out everywhere
+func (u *usersUC) DeactivateUser(ctx context.Context, adminID, userID int64) error {
+	if adminID == userID {
+		return ErrOwnAccountChange
+	}
+
+	if err := u.usersRepo.SetDeactivated(ctx, userID, true); err != nil {
+		return err
+	}
+
+	return u.authUC.RevokeAllSessions(ctx, userID)
+}
+
+// Reactivate user, they have to log in again
+func (u *usersUC) ReactivateUser(ctx context.Context, userID int64) error {
+	return u.usersRepo.SetDeactivated(ctx, userID, false)
+}
+
+// Revoke user's tokens and delete the account
+func (u *usersUC) DeleteUser(ctx context.Context, adminID, userID int64) error {
+	if adminID == userID {
+		return ErrOwnAccountChange
+	}
+
+	if err := u.authUC.RevokeAllSessions(ctx, userID); err != nil {
+		return err
+	}
+
+	counterpartyIDs, err := u.usersRepo.DeleteUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	// Cached info of the counterparties still shows the deleted username
+	for _, counterpartyID := range counterpartyIDs {
+		if err := u.merchRedisRepo.DeleteInfo(ctx, redis.GetUserInfoCacheKey(counterpartyID)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,267 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	mock_auth "cyansnbrst/merch-service/internal/auth/mock"
+	mock_merch "cyansnbrst/merch-service/internal/merch/mock"
+	m "cyansnbrst/merch-service/internal/models"
+	mock_users "cyansnbrst/merch-service/internal/users/mock"
+	"cyansnbrst/merch-service/internal/users/usecase"
+	"cyansnbrst/merch-service/pkg/db"
+)
+
+var ErrRandomDBError = errors.New("db error")
+
+func TestUsersUC_SetRole(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_users.NewMockRepository(ctrl)
+	mockAuthUC := mock_auth.NewMockUseCase(ctrl)
+
+	usersUC := usecase.NewUsersUseCase(mockRepo, nil, mockAuthUC)
+
+	tests := []struct {
+		name          string
+		adminID       int64
+		userID        int64
+		role          string
+		mockSetup     func()
+		expectedError error
+	}{
+		{
+			name:    "success",
+			adminID: 1,
+			userID:  2,
+			role:    m.RoleAdmin,
+			mockSetup: func() {
+				mockRepo.EXPECT().SetRole(gomock.Any(), int64(2), m.RoleAdmin).Return(nil)
+				mockAuthUC.EXPECT().RevokeAllSessions(gomock.Any(), int64(2)).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:    "revoke sessions error",
+			adminID: 1,
+			userID:  4,
+			role:    m.RoleUser,
+			mockSetup: func() {
+				mockRepo.EXPECT().SetRole(gomock.Any(), int64(4), m.RoleUser).Return(nil)
+				mockAuthUC.EXPECT().RevokeAllSessions(gomock.Any(), int64(4)).Return(ErrRandomDBError)
+			},
+			expectedError: ErrRandomDBError,
+		},
+		{
+			name:          "own role",
+			adminID:       1,
+			userID:        1,
+			role:          m.RoleUser,
+			mockSetup:     func() {},
+			expectedError: usecase.ErrOwnRoleChange,
+		},
+		{
+			name:    "user not found",
+			adminID: 1,
+			userID:  3,
+			role:    m.RoleAdmin,
+			mockSetup: func() {
+				mockRepo.EXPECT().SetRole(gomock.Any(), int64(3), m.RoleAdmin).Return(db.ErrUserNotFound)
+			},
+			expectedError: db.ErrUserNotFound,
+		},
+		{
+			name:    "db error",
+			adminID: 1,
+			userID:  2,
+			role:    m.RoleUser,
+			mockSetup: func() {
+				mockRepo.EXPECT().SetRole(gomock.Any(), int64(2), m.RoleUser).Return(ErrRandomDBError)
+			},
+			expectedError: ErrRandomDBError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			err := usersUC.SetRole(context.Background(), tt.adminID, tt.userID, tt.role)
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
+
+func TestUsersUC_DeactivateUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_users.NewMockRepository(ctrl)
+	mockAuthUC := mock_auth.NewMockUseCase(ctrl)
+
+	usersUC := usecase.NewUsersUseCase(mockRepo, nil, mockAuthUC)
+
+	tests := []struct {
+		name          string
+		adminID       int64
+		userID        int64
+		mockSetup     func()
+		expectedError error
+	}{
+		{
+			name:    "success",
+			adminID: 1,
+			userID:  2,
+			mockSetup: func() {
+				mockRepo.EXPECT().SetDeactivated(gomock.Any(), int64(2), true).Return(nil)
+				mockAuthUC.EXPECT().RevokeAllSessions(gomock.Any(), int64(2)).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:          "own account",
+			adminID:       1,
+			userID:        1,
+			mockSetup:     func() {},
+			expectedError: usecase.ErrOwnAccountChange,
+		},
+		{
+			name:    "user not found",
+			adminID: 1,
+			userID:  3,
+			mockSetup: func() {
+				mockRepo.EXPECT().SetDeactivated(gomock.Any(), int64(3), true).Return(db.ErrUserNotFound)
+			},
+			expectedError: db.ErrUserNotFound,
+		},
+		{
+			name:    "revoke sessions error",
+			adminID: 1,
+			userID:  2,
+			mockSetup: func() {
+				mockRepo.EXPECT().SetDeactivated(gomock.Any(), int64(2), true).Return(nil)
+				mockAuthUC.EXPECT().RevokeAllSessions(gomock.Any(), int64(2)).Return(ErrRandomDBError)
+			},
+			expectedError: ErrRandomDBError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			err := usersUC.DeactivateUser(context.Background(), tt.adminID, tt.userID)
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
+
+func TestUsersUC_ReactivateUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_users.NewMockRepository(ctrl)
+
+	usersUC := usecase.NewUsersUseCase(mockRepo, nil, nil)
+
+	mockRepo.EXPECT().SetDeactivated(gomock.Any(), int64(2), false).Return(nil)
+	assert.NoError(t, usersUC.ReactivateUser(context.Background(), 2))
+
+	mockRepo.EXPECT().SetDeactivated(gomock.Any(), int64(3), false).Return(db.ErrUserNotFound)
+	assert.Equal(t, db.ErrUserNotFound, usersUC.ReactivateUser(context.Background(), 3))
+}
+
+func TestUsersUC_DeleteUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_users.NewMockRepository(ctrl)
+	mockRedisRepo := mock_merch.NewMockRedisRepository(ctrl)
+	mockAuthUC := mock_auth.NewMockUseCase(ctrl)
+
+	usersUC := usecase.NewUsersUseCase(mockRepo, mockRedisRepo, mockAuthUC)
+
+	tests := []struct {
+		name          string
+		adminID       int64
+		userID        int64
+		mockSetup     func()
+		expectedError error
+	}{
+		{
+			name:    "success",
+			adminID: 1,
+			userID:  2,
+			mockSetup: func() {
+				gomock.InOrder(
+					mockAuthUC.EXPECT().RevokeAllSessions(gomock.Any(), int64(2)).Return(nil),
+					mockRepo.EXPECT().DeleteUser(gomock.Any(), int64(2)).Return([]int64{3, 4}, nil),
+				)
+				mockRedisRepo.EXPECT().DeleteInfo(gomock.Any(), "user:3:info").Return(nil)
+				mockRedisRepo.EXPECT().DeleteInfo(gomock.Any(), "user:4:info").Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:    "no counterparties",
+			adminID: 1,
+			userID:  2,
+			mockSetup: func() {
+				mockAuthUC.EXPECT().RevokeAllSessions(gomock.Any(), int64(2)).Return(nil)
+				mockRepo.EXPECT().DeleteUser(gomock.Any(), int64(2)).Return([]int64{}, nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:          "own account",
+			adminID:       1,
+			userID:        1,
+			mockSetup:     func() {},
+			expectedError: usecase.ErrOwnAccountChange,
+		},
+		{
+			name:    "user not found",
+			adminID: 1,
+			userID:  3,
+			mockSetup: func() {
+				mockAuthUC.EXPECT().RevokeAllSessions(gomock.Any(), int64(3)).Return(db.ErrUserNotFound)
+			},
+			expectedError: db.ErrUserNotFound,
+		},
+		{
+			name:    "db error",
+			adminID: 1,
+			userID:  2,
+			mockSetup: func() {
+				mockAuthUC.EXPECT().RevokeAllSessions(gomock.Any(), int64(2)).Return(nil)
+				mockRepo.EXPECT().DeleteUser(gomock.Any(), int64(2)).Return(nil, ErrRandomDBError)
+			},
+			expectedError: ErrRandomDBError,
+		},
+		{
+			name:    "redis error",
+			adminID: 1,
+			userID:  2,
+			mockSetup: func() {
+				mockAuthUC.EXPECT().RevokeAllSessions(gomock.Any(), int64(2)).Return(nil)
+				mockRepo.EXPECT().DeleteUser(gomock.Any(), int64(2)).Return([]int64{3}, nil)
+				mockRedisRepo.EXPECT().DeleteInfo(gomock.Any(), "user:3:info").Return(ErrRandomDBError)
+			},
+			expectedError: ErrRandomDBError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			err := usersUC.DeleteUser(context.Background(), tt.adminID, tt.userID)
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
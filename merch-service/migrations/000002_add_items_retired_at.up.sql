@@ -0,0 +1 @@
+ALTER TABLE items ADD COLUMN retired_at TIMESTAMP WITH TIME ZONE;
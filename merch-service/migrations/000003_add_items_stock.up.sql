@@ -0,0 +1 @@
+ALTER TABLE items ADD COLUMN stock INTEGER CHECK (stock >= 0);
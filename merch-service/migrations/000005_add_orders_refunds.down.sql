@@ -0,0 +1,3 @@
+ALTER TABLE orders
+    DROP COLUMN IF EXISTS refunded_by,
+    DROP COLUMN IF EXISTS refunded_at;
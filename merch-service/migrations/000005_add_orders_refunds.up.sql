@@ -0,0 +1,3 @@
+ALTER TABLE orders
+    ADD COLUMN refunded_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN refunded_by INTEGER REFERENCES users(id) ON DELETE SET NULL;
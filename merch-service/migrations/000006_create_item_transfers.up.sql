@@ -0,0 +1,11 @@
+CREATE TABLE item_transfers (
+    id SERIAL PRIMARY KEY,
+    from_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    to_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    item_id INTEGER NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    transfer_date TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_item_transfers_from_id ON item_transfers(from_id);
+CREATE INDEX idx_item_transfers_to_id ON item_transfers(to_id);
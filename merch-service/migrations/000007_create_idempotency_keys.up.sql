@@ -0,0 +1,9 @@
+CREATE TABLE idempotency_keys (
+    user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+    key VARCHAR(255) NOT NULL,
+    fingerprint VARCHAR(64) NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'succeeded' CHECK (status IN ('succeeded', 'failed')),
+    response JSONB,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, key)
+);
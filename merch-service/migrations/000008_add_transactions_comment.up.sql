@@ -0,0 +1,2 @@
+ALTER TABLE transactions
+    ADD COLUMN comment VARCHAR(255) NOT NULL DEFAULT '';
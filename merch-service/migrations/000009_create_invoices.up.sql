@@ -0,0 +1,13 @@
+CREATE TABLE invoices (
+    id SERIAL PRIMARY KEY,
+    from_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    to_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    amount INTEGER NOT NULL CHECK (amount > 0),
+    comment VARCHAR(255) NOT NULL DEFAULT '',
+    status VARCHAR(16) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'accepted', 'declined')),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    resolved_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_invoices_from_id ON invoices(from_id);
+CREATE INDEX idx_invoices_to_id ON invoices(to_id);
@@ -0,0 +1,8 @@
+DELETE FROM transactions WHERE kind = 'grant';
+
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS kind,
+    DROP COLUMN IF EXISTS actor_type,
+    DROP COLUMN IF EXISTS actor_id;
+
+DROP TABLE IF EXISTS api_keys;
@@ -0,0 +1,17 @@
+CREATE TABLE api_keys (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(64) NOT NULL,
+    key_hash VARCHAR(64) NOT NULL UNIQUE,
+    scopes TEXT[] NOT NULL,
+    created_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE
+);
+
+ALTER TABLE transactions
+    ADD COLUMN kind VARCHAR(16) NOT NULL DEFAULT 'transfer' CHECK (kind IN ('transfer', 'grant')),
+    ADD COLUMN actor_type VARCHAR(16) NOT NULL DEFAULT 'user' CHECK (actor_type IN ('user', 'service')),
+    ADD COLUMN actor_id INTEGER;
+
+UPDATE transactions SET actor_id = from_id;
@@ -2,4 +2,8 @@ package auth
 
 import "errors"
 
-var ErrInvalidToken = errors.New("invalid or expired token")
+var (
+	ErrInvalidToken  = errors.New("invalid or expired token")
+	ErrRevokedToken  = errors.New("token has been revoked")
+	ErrInvalidAPIKey = errors.New("invalid or revoked api key")
+)
@@ -0,0 +1,256 @@
+package jwt
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"cyansnbrst/merch-service/config"
+)
+
+const minRSAKeyBits = 2048
+
+// Supported signing algorithms
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmRS256 = "RS256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
+// Token signing key and verification keys by key id
+type Keys struct {
+	method     jwt.SigningMethod
+	signingKey any
+	keyID      string
+	verifying  map[string]any
+}
+
+// JSON Web Key
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JSON Web Key Set
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// Load keys for the configured algorithm
+func LoadKeys(cfg *config.Config) (*Keys, error) {
+	switch cfg.App.JWTAlgorithm {
+	case AlgorithmHS256:
+		if cfg.App.JWTSecretKey == "" {
+			return nil, errors.New("jwt secret key is required for HS256")
+		}
+		return &Keys{
+			method:     jwt.SigningMethodHS256,
+			signingKey: []byte(cfg.App.JWTSecretKey),
+		}, nil
+	case AlgorithmRS256:
+		return loadAsymmetricKeys(jwt.SigningMethodRS256, cfg.App.JWTPrivateKeyFile, cfg.App.JWTPublicKeyFiles)
+	case AlgorithmEdDSA:
+		return loadAsymmetricKeys(jwt.SigningMethodEdDSA, cfg.App.JWTPrivateKeyFile, cfg.App.JWTPublicKeyFiles)
+	}
+	return nil, fmt.Errorf("unsupported jwt algorithm: %q", cfg.App.JWTAlgorithm)
+}
+
+// Sign claims with the current signing key
+func (k *Keys) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(k.method, claims)
+	if k.keyID != "" {
+		token.Header["kid"] = k.keyID
+	}
+	return token.SignedString(k.signingKey)
+}
+
+// Public verification keys, empty for HMAC
+func (k *Keys) JWKS() JWKS {
+	jwks := JWKS{Keys: []JWK{}}
+	for kid, key := range k.verifying {
+		jwk := publicJWK(key)
+		jwk.Kid = kid
+		jwk.Use = "sig"
+		jwk.Alg = k.method.Alg()
+		jwks.Keys = append(jwks.Keys, jwk)
+	}
+	return jwks
+}
+
+// Pick verification key for the token
+func (k *Keys) keyFunc(token *jwt.Token) (any, error) {
+	if token.Method.Alg() != k.method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+
+	if k.verifying == nil {
+		return k.signingKey, nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	key, ok := k.verifying[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key id: %q", kid)
+	}
+	return key, nil
+}
+
+// Load private signing key and public keys still accepted for verification
+func loadAsymmetricKeys(method jwt.SigningMethod, privateKeyFile string, publicKeyFiles []string) (*Keys, error) {
+	signingKey, err := readPrivateKey(privateKeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var publicKey any
+	switch key := signingKey.(type) {
+	case *rsa.PrivateKey:
+		publicKey = &key.PublicKey
+	case ed25519.PrivateKey:
+		publicKey = key.Public()
+	}
+
+	keys := &Keys{
+		method:     method,
+		signingKey: signingKey,
+		verifying:  make(map[string]any),
+	}
+
+	if err := keys.addVerificationKey(publicKey); err != nil {
+		return nil, fmt.Errorf("%s: %w", privateKeyFile, err)
+	}
+	keys.keyID = thumbprint(publicKey)
+
+	for _, file := range publicKeyFiles {
+		publicKey, err := readPublicKey(file)
+		if err != nil {
+			return nil, err
+		}
+		if err := keys.addVerificationKey(publicKey); err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+	}
+
+	return keys, nil
+}
+
+// Check that key fits the signing method and index it by thumbprint
+func (k *Keys) addVerificationKey(key any) error {
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		if k.method != jwt.SigningMethodRS256 {
+			return errors.New("rsa key can't be used with " + k.method.Alg())
+		}
+		if key.N.BitLen() < minRSAKeyBits {
+			return fmt.Errorf("rsa key must be at least %d bits", minRSAKeyBits)
+		}
+	case ed25519.PublicKey:
+		if k.method != jwt.SigningMethodEdDSA {
+			return errors.New("ed25519 key can't be used with " + k.method.Alg())
+		}
+	default:
+		return fmt.Errorf("unsupported key type %T", key)
+	}
+
+	k.verifying[thumbprint(key)] = key
+	return nil
+}
+
+// Read PKCS#8 or PKCS#1 private key from PEM file
+func readPrivateKey(file string) (any, error) {
+	block, err := readPEM(file)
+	if err != nil {
+		return nil, err
+	}
+
+	if block.Type == "RSA PRIVATE KEY" {
+		key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		return key, nil
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	return key, nil
+}
+
+// Read PKIX public key from PEM file
+func readPublicKey(file string) (any, error) {
+	block, err := readPEM(file)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	return key, nil
+}
+
+func readPEM(file string) (*pem.Block, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file: %w", err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s: no PEM data found", file)
+	}
+	return block, nil
+}
+
+// Public key as JWK without metadata
+func publicJWK(key any) JWK {
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		return JWK{
+			Kty: "RSA",
+			N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}
+	case ed25519.PublicKey:
+		return JWK{
+			Kty: "OKP",
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(key),
+		}
+	}
+	return JWK{}
+}
+
+// RFC 7638 JWK thumbprint used as key id
+func thumbprint(key any) string {
+	jwk := publicJWK(key)
+
+	var canonical string
+	switch jwk.Kty {
+	case "RSA":
+		canonical = fmt.Sprintf(`{"e":"%s","kty":"RSA","n":"%s"}`, jwk.E, jwk.N)
+	case "OKP":
+		canonical = fmt.Sprintf(`{"crv":"%s","kty":"OKP","x":"%s"}`, jwk.Crv, jwk.X)
+	}
+
+	hash := sha256.Sum256([]byte(canonical))
+	return base64.RawURLEncoding.EncodeToString(hash[:])
+}
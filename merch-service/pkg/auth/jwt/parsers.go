@@ -1,37 +1,68 @@
 package jwt
 
 import (
-	"errors"
-	"fmt"
+	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 
 	"cyansnbrst/merch-service/pkg/auth"
 )
 
-// Parse JWT token (HMAC)
-func ParseJWT(tokenString, secret string) (int64, error) {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return []byte(secret), nil
-	})
+// Parsed token claims
+type Claims struct {
+	ID        string
+	UserID    int64
+	Role      string
+	SessionID string
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+}
 
+// Parse JWT token signed with one of the verification keys, session ID is optional.
+// Issue time is taken from the private millisecond claim when present
+func ParseJWT(tokenString string, keys *Keys) (*Claims, error) {
+	token, err := jwt.Parse(tokenString, keys.keyFunc)
 	if err != nil {
-		if errors.Is(err, jwt.ErrTokenMalformed) || errors.Is(err, jwt.ErrTokenExpired) {
-			return 0, auth.ErrInvalidToken
-		}
-		return 0, err
+		return nil, auth.ErrInvalidToken
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return nil, auth.ErrInvalidToken
 	}
 
-	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		userID, ok := claims["user_id"].(float64)
-		if !ok {
-			return 0, auth.ErrInvalidToken
-		}
-		return int64(userID), nil
+	userID, ok := claims["user_id"].(float64)
+	if !ok {
+		return nil, auth.ErrInvalidToken
+	}
+	role, ok := claims["role"].(string)
+	if !ok {
+		return nil, auth.ErrInvalidToken
+	}
+	jti, ok := claims["jti"].(string)
+	if !ok || jti == "" {
+		return nil, auth.ErrInvalidToken
+	}
+	issuedAt, err := claims.GetIssuedAt()
+	if err != nil || issuedAt == nil {
+		return nil, auth.ErrInvalidToken
+	}
+	issuedAtTime := issuedAt.Time
+	if issuedAtMs, ok := claims["iat_ms"].(float64); ok {
+		issuedAtTime = time.UnixMilli(int64(issuedAtMs))
+	}
+	sessionID, _ := claims["sid"].(string)
+	expiresAt, err := claims.GetExpirationTime()
+	if err != nil || expiresAt == nil {
+		return nil, auth.ErrInvalidToken
 	}
 
-	return 0, auth.ErrInvalidToken
+	return &Claims{
+		ID:        jti,
+		UserID:    int64(userID),
+		Role:      role,
+		SessionID: sessionID,
+		IssuedAt:  issuedAtTime,
+		ExpiresAt: expiresAt.Time,
+	}, nil
 }
@@ -1,10 +1,40 @@
 package db
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
 	ErrItemtNotFound     = errors.New("item not found")
+	ErrItemAlreadyExists = errors.New("item with this name already exists")
+	ErrOutOfStock        = errors.New("item is out of stock")
 	ErrInsufficientFunds = errors.New("insufficient funds")
 	ErrIncorrectReciever = errors.New("can't send money to the same user")
 	ErrUserNotFound      = errors.New("user not found")
+	ErrUserAlreadyExists = errors.New("user with this username already exists")
+	ErrUserDeactivated   = errors.New("user account is deactivated")
+	ErrOrderNotFound     = errors.New("order not found")
+	ErrAlreadyRefunded   = errors.New("order is already refunded")
+	ErrOrderOwnerDeleted = errors.New("order owner is deleted")
+	ErrNotEnoughItems    = errors.New("not enough items in inventory")
+	ErrDuplicateRequest  = errors.New("request with this idempotency key was already processed")
+	ErrDuplicateReciever = errors.New("each recipient can appear only once")
+	ErrSelfInvoice       = errors.New("can't request coins from yourself")
+	ErrInvoiceNotFound   = errors.New("invoice not found")
+	ErrInvoiceNotPending = errors.New("invoice is already accepted or declined")
+	ErrTokenNotFound     = errors.New("refresh token not found")
+	ErrTokenAlreadyUsed  = errors.New("refresh token was already used")
+	ErrAPIKeyNotFound    = errors.New("api key not found")
+	ErrSessionNotFound   = errors.New("session not found")
 )
+
+// Transfer limit violation
+type LimitExceededError struct {
+	Limit     string
+	Remaining int64
+}
+
+func (e *LimitExceededError) Error() string {
+	return fmt.Sprintf("%s limit exceeded, %d coins left", e.Limit, e.Remaining)
+}
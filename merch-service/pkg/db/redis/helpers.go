@@ -2,6 +2,48 @@ package redis
 
 import "fmt"
 
+const catalogCacheKey = "catalog:items"
+
 func GetUserInfoCacheKey(userID int64) string {
 	return fmt.Sprintf("user:%d:info", userID)
 }
+
+func GetUserCartKey(userID int64) string {
+	return fmt.Sprintf("user:%d:cart", userID)
+}
+
+func GetUserCartKeyPattern() string {
+	return "user:*:cart"
+}
+
+func GetIdempotencyCacheKey(userID int64, key string) string {
+	return fmt.Sprintf("user:%d:idempotency:%s", userID, key)
+}
+
+func GetCatalogCacheKey() string {
+	return catalogCacheKey
+}
+
+func GetRevokedTokenKey(jti string) string {
+	return fmt.Sprintf("token:%s:revoked", jti)
+}
+
+func GetRevokedSessionKey(sessionID string) string {
+	return fmt.Sprintf("session:%s:revoked", sessionID)
+}
+
+func GetUserRevokedBeforeKey(userID int64) string {
+	return fmt.Sprintf("user:%d:revoked_before", userID)
+}
+
+func GetPasswordResetKey(tokenHash string) string {
+	return fmt.Sprintf("password_reset:%s", tokenHash)
+}
+
+func GetLoginAttemptsKey(subject string) string {
+	return fmt.Sprintf("login:%s:attempts", subject)
+}
+
+func GetLoginLockoutKey(subject string) string {
+	return fmt.Sprintf("login:%s:lockout", subject)
+}
@@ -0,0 +1,33 @@
+package httphelpers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+const idempotencyKeyHeader = "Idempotency-Key"
+
+var (
+	ErrInvalidIDParam        = errors.New("invalid id parameter")
+	ErrInvalidIdempotencyKey = errors.New("idempotency key must be at most 255 characters long")
+)
+
+// Read ID from the path parameters
+func ReadIDParam(c echo.Context) (int64, error) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id < 1 {
+		return 0, ErrInvalidIDParam
+	}
+	return id, nil
+}
+
+// Read optional idempotency key from the request headers
+func ReadIdempotencyKey(c echo.Context) (string, error) {
+	key := c.Request().Header.Get(idempotencyKeyHeader)
+	if len(key) > 255 {
+		return "", ErrInvalidIdempotencyKey
+	}
+	return key, nil
+}
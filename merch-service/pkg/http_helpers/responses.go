@@ -1,7 +1,10 @@
 package httphelpers
 
 import (
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"go.uber.org/zap"
@@ -12,6 +15,7 @@ const (
 	msgInvalidCredentials         = "invalid authentication credentials"
 	msgInvalidAuthenticationToken = "invalid or missing authentication token"
 	msgAuthenticationRequired     = "you must be authenticated to access this resource"
+	msgNotPermitted               = "you don't have permission to access this resource"
 )
 
 // Error response
@@ -44,6 +48,26 @@ func BadRequestResponse(c echo.Context, err error) error {
 	return errorResponse(c, http.StatusBadRequest, err.Error())
 }
 
+// Not found response (404)
+func NotFoundResponse(c echo.Context, err error) error {
+	return errorResponse(c, http.StatusNotFound, err.Error())
+}
+
+// Conflict response (409)
+func ConflictResponse(c echo.Context, err error) error {
+	return errorResponse(c, http.StatusConflict, err.Error())
+}
+
+// Forbidden response (403)
+func ForbiddenResponse(c echo.Context, err error) error {
+	return errorResponse(c, http.StatusForbidden, err.Error())
+}
+
+// Not permitted response (403)
+func NotPermittedResponse(c echo.Context) error {
+	return errorResponse(c, http.StatusForbidden, msgNotPermitted)
+}
+
 // Invalid credentials response (401)
 func InvalidCredentialsResponse(c echo.Context) error {
 	return errorResponse(c, http.StatusUnauthorized, msgInvalidCredentials)
@@ -58,3 +82,10 @@ func InvalidAuthenticationTokenResponse(c echo.Context) error {
 func AuthenticationRequiredResponse(c echo.Context) error {
 	return errorResponse(c, http.StatusUnauthorized, msgAuthenticationRequired)
 }
+
+// Too many requests response (429)
+func TooManyRequestsResponse(c echo.Context, err error, retryAfter time.Duration) error {
+	seconds := int64(math.Ceil(retryAfter.Seconds()))
+	c.Response().Header().Set("Retry-After", strconv.FormatInt(max(seconds, 1), 10))
+	return errorResponse(c, http.StatusTooManyRequests, err.Error())
+}
@@ -0,0 +1,140 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/google/uuid"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+
+	"cyansnbrst/merch-service/internal/models"
+	"cyansnbrst/merch-service/internal/server"
+)
+
+type APIKeysTestSuite struct {
+	BaseTestSuite
+}
+
+func TestAPIKeysSuite(t *testing.T) {
+	suite.Run(t, new(APIKeysTestSuite))
+}
+
+func (s *APIKeysTestSuite) SetupSuite() {
+	s.BaseTestSuite.SetupSuite()
+}
+
+func (s *APIKeysTestSuite) TearDownSuite() {
+	s.BaseTestSuite.TearDownSuite()
+}
+
+func (s *APIKeysTestSuite) grant(ts *httptest.Server, header, value, toUser string, amount int) int {
+	reqBody := fmt.Sprintf(`{"to_user": "%s", "amount": %d, "comment": "bonus"}`, toUser, amount)
+	req, err := http.NewRequest(http.MethodPost, ts.URL+"/api/grants", strings.NewReader(reqBody))
+	s.Require().NoError(err)
+
+	req.Header.Set(header, value)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+
+	return resp.StatusCode
+}
+
+func (s *APIKeysTestSuite) TestAPIKeys_GrantCoins() {
+	app := server.NewServer(s.cfg, zap.NewNop(), s.dbPool, s.redisClient, s.keys)
+	ts := httptest.NewServer(app.RegisterHandlers())
+	defer ts.Close()
+
+	adminID, _, adminToken := s.createUser(models.RoleAdmin)
+	userID, username, userToken := s.createUser(models.RoleUser)
+
+	reqBody := fmt.Sprintf(`{"name": "hr-bot", "scopes": ["%s"]}`, models.ScopeGrantCoins)
+	req, err := http.NewRequest(http.MethodPost, ts.URL+"/api/admin/api-keys", strings.NewReader(reqBody))
+	s.Require().NoError(err)
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", adminToken))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	s.Require().Equal(http.StatusCreated, resp.StatusCode)
+
+	var created models.CreateAPIKeyResponse
+	s.Require().NoError(json.NewDecoder(resp.Body).Decode(&created))
+	s.NotEmpty(created.Key)
+
+	var keyHash string
+	err = s.dbPool.QueryRow(context.Background(),
+		`SELECT key_hash FROM api_keys WHERE id = $1`, created.ID,
+	).Scan(&keyHash)
+	s.Require().NoError(err)
+	s.NotEqual(created.Key, keyHash)
+
+	s.Equal(http.StatusOK, s.grant(ts, "X-API-Key", created.Key, username, 500))
+	s.Equal(http.StatusOK, s.grant(ts, "Authorization", "Bearer "+adminToken, username, 100))
+	s.Equal(http.StatusForbidden, s.grant(ts, "Authorization", "Bearer "+userToken, username, 100))
+	s.Equal(http.StatusUnauthorized, s.grant(ts, "X-API-Key", "msk_invalid", username, 100))
+	s.Equal(http.StatusBadRequest, s.grant(ts, "X-API-Key", created.Key, "nobody-"+uuid.New().String(), 100))
+
+	var balance int
+	err = s.dbPool.QueryRow(context.Background(),
+		`SELECT balance FROM users WHERE id = $1`, userID,
+	).Scan(&balance)
+	s.Require().NoError(err)
+	s.Equal(1600, balance)
+
+	rows, err := s.dbPool.Query(context.Background(),
+		`SELECT actor_type, actor_id FROM transactions WHERE to_id = $1 AND kind = 'grant' ORDER BY id`, userID,
+	)
+	s.Require().NoError(err)
+	defer rows.Close()
+
+	var actors []models.Actor
+	for rows.Next() {
+		var actor models.Actor
+		s.Require().NoError(rows.Scan(&actor.Type, &actor.ID))
+		actors = append(actors, actor)
+	}
+	s.Equal([]models.Actor{
+		{Type: models.ActorService, ID: created.ID},
+		{Type: models.ActorUser, ID: int64(adminID)},
+	}, actors)
+
+	req, err = http.NewRequest(http.MethodGet, ts.URL+"/api/history?direction=received", nil)
+	s.Require().NoError(err)
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", userToken))
+
+	resp, err = http.DefaultClient.Do(req)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var page models.HistoryPage
+	s.Require().NoError(json.NewDecoder(resp.Body).Decode(&page))
+	s.Require().Len(page.Transactions, 2)
+	s.Equal("system", page.Transactions[0].Counterparty)
+
+	req, err = http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/api/admin/api-keys/%d", ts.URL, created.ID), nil)
+	s.Require().NoError(err)
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", adminToken))
+
+	resp, err = http.DefaultClient.Do(req)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	s.Equal(http.StatusOK, resp.StatusCode)
+
+	s.Equal(http.StatusUnauthorized, s.grant(ts, "X-API-Key", created.Key, username, 100))
+}
@@ -2,12 +2,21 @@ package tests
 
 import (
 	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
 	"encoding/json"
+	"encoding/pem"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/alexedwards/argon2id"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
@@ -16,8 +25,10 @@ import (
 	"github.com/stretchr/testify/suite"
 	"go.uber.org/zap"
 
+	"cyansnbrst/merch-service/config"
 	"cyansnbrst/merch-service/internal/models"
 	"cyansnbrst/merch-service/internal/server"
+	"cyansnbrst/merch-service/pkg/auth/jwt"
 )
 
 type AuthTestSuite struct {
@@ -37,7 +48,7 @@ func (s *AuthTestSuite) TearDownSuite() {
 }
 
 func (s *AuthTestSuite) TestAuth_Authenticate_Login() {
-	app := server.NewServer(s.cfg, zap.NewNop(), s.dbPool, s.redisClient)
+	app := server.NewServer(s.cfg, zap.NewNop(), s.dbPool, s.redisClient, s.keys)
 	ts := httptest.NewServer(app.RegisterHandlers())
 	defer ts.Close()
 
@@ -73,7 +84,7 @@ func (s *AuthTestSuite) TestAuth_Authenticate_Login() {
 }
 
 func (s *AuthTestSuite) TestAuth_Authenticate_InvalidPassword() {
-	app := server.NewServer(s.cfg, zap.NewNop(), s.dbPool, s.redisClient)
+	app := server.NewServer(s.cfg, zap.NewNop(), s.dbPool, s.redisClient, s.keys)
 	ts := httptest.NewServer(app.RegisterHandlers())
 	defer ts.Close()
 
@@ -109,7 +120,7 @@ func (s *AuthTestSuite) TestAuth_Authenticate_InvalidPassword() {
 }
 
 func (s *AuthTestSuite) TestAuth_Authenticate_Register() {
-	app := server.NewServer(s.cfg, zap.NewNop(), s.dbPool, s.redisClient)
+	app := server.NewServer(s.cfg, zap.NewNop(), s.dbPool, s.redisClient, s.keys)
 	ts := httptest.NewServer(app.RegisterHandlers())
 	defer ts.Close()
 
@@ -146,3 +157,458 @@ func (s *AuthTestSuite) TestAuth_Authenticate_Register() {
 	s.Require().NoError(err)
 	s.True(match)
 }
+
+func (s *AuthTestSuite) postCredentials(url, username, password string) *http.Response {
+	reqBody := fmt.Sprintf(`{"username": "%s", "password": "%s"}`, username, password)
+	req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(reqBody))
+	s.Require().NoError(err)
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	s.Require().NoError(err)
+
+	return resp
+}
+
+func (s *AuthTestSuite) TestAuth_RegisterAndLogin() {
+	app := server.NewServer(s.cfg, zap.NewNop(), s.dbPool, s.redisClient, s.keys)
+	ts := httptest.NewServer(app.RegisterHandlers())
+	defer ts.Close()
+
+	username := "user-" + uuid.New().String()[:8]
+	password := "password"
+
+	resp := s.postCredentials(ts.URL+"/api/login", username, password)
+	defer resp.Body.Close()
+	s.Equal(http.StatusUnauthorized, resp.StatusCode)
+
+	resp = s.postCredentials(ts.URL+"/api/register", username, password)
+	defer resp.Body.Close()
+	s.Equal(http.StatusCreated, resp.StatusCode)
+
+	var authResp models.AuthResponse
+	err := json.NewDecoder(resp.Body).Decode(&authResp)
+	s.Require().NoError(err)
+	s.NotEmpty(authResp.Token)
+
+	resp = s.postCredentials(ts.URL+"/api/register", username, "other-password")
+	defer resp.Body.Close()
+	s.Equal(http.StatusConflict, resp.StatusCode)
+
+	resp = s.postCredentials(ts.URL+"/api/login", username, "wrong-password")
+	defer resp.Body.Close()
+	s.Equal(http.StatusUnauthorized, resp.StatusCode)
+
+	resp = s.postCredentials(ts.URL+"/api/login", username, password)
+	defer resp.Body.Close()
+	s.Equal(http.StatusOK, resp.StatusCode)
+
+	err = json.NewDecoder(resp.Body).Decode(&authResp)
+	s.Require().NoError(err)
+	s.NotEmpty(authResp.Token)
+}
+
+func (s *AuthTestSuite) TestAuth_LegacyAuthDisabled() {
+	cfg := *s.cfg
+	cfg.App.LegacyAuth = false
+
+	app := server.NewServer(&cfg, zap.NewNop(), s.dbPool, s.redisClient, s.keys)
+	ts := httptest.NewServer(app.RegisterHandlers())
+	defer ts.Close()
+
+	resp := s.postCredentials(ts.URL+"/api/auth", "user-"+uuid.New().String()[:8], "password")
+	defer resp.Body.Close()
+	s.Equal(http.StatusNotFound, resp.StatusCode)
+}
+
+func (s *AuthTestSuite) refresh(url, refreshToken string) (*http.Response, models.AuthResponse) {
+	reqBody := fmt.Sprintf(`{"refresh_token": "%s"}`, refreshToken)
+	req, err := http.NewRequest(http.MethodPost, url+"/api/token/refresh", strings.NewReader(reqBody))
+	s.Require().NoError(err)
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+
+	var authResp models.AuthResponse
+	if resp.StatusCode == http.StatusOK {
+		err = json.NewDecoder(resp.Body).Decode(&authResp)
+		s.Require().NoError(err)
+	}
+
+	return resp, authResp
+}
+
+func (s *AuthTestSuite) TestAuth_RefreshToken_Rotation() {
+	app := server.NewServer(s.cfg, zap.NewNop(), s.dbPool, s.redisClient, s.keys)
+	ts := httptest.NewServer(app.RegisterHandlers())
+	defer ts.Close()
+
+	resp := s.postCredentials(ts.URL+"/api/register", "user-"+uuid.New().String()[:8], "password")
+	defer resp.Body.Close()
+	s.Require().Equal(http.StatusCreated, resp.StatusCode)
+
+	var login models.AuthResponse
+	err := json.NewDecoder(resp.Body).Decode(&login)
+	s.Require().NoError(err)
+	s.NotEmpty(login.RefreshToken)
+
+	resp, rotated := s.refresh(ts.URL, login.RefreshToken)
+	s.Equal(http.StatusOK, resp.StatusCode)
+	s.NotEmpty(rotated.Token)
+	s.NotEqual(login.RefreshToken, rotated.RefreshToken)
+
+	resp, _ = s.refresh(ts.URL, login.RefreshToken)
+	s.Equal(http.StatusUnauthorized, resp.StatusCode)
+
+	resp, _ = s.refresh(ts.URL, rotated.RefreshToken)
+	s.Equal(http.StatusUnauthorized, resp.StatusCode)
+
+	resp, _ = s.refresh(ts.URL, "unknown-token")
+	s.Equal(http.StatusUnauthorized, resp.StatusCode)
+}
+
+func (s *AuthTestSuite) TestAuth_Logout() {
+	app := server.NewServer(s.cfg, zap.NewNop(), s.dbPool, s.redisClient, s.keys)
+	ts := httptest.NewServer(app.RegisterHandlers())
+	defer ts.Close()
+
+	resp := s.postCredentials(ts.URL+"/api/register", "user-"+uuid.New().String()[:8], "password")
+	defer resp.Body.Close()
+	s.Require().Equal(http.StatusCreated, resp.StatusCode)
+
+	var login models.AuthResponse
+	err := json.NewDecoder(resp.Body).Decode(&login)
+	s.Require().NoError(err)
+
+	req, err := http.NewRequest(http.MethodPost, ts.URL+"/api/logout", nil)
+	s.Require().NoError(err)
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", login.Token))
+
+	resp, err = http.DefaultClient.Do(req)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	s.Equal(http.StatusOK, resp.StatusCode)
+
+	req, err = http.NewRequest(http.MethodGet, ts.URL+"/api/info", nil)
+	s.Require().NoError(err)
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", login.Token))
+
+	resp, err = http.DefaultClient.Do(req)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	s.Equal(http.StatusUnauthorized, resp.StatusCode)
+
+	resp, _ = s.refresh(ts.URL, login.RefreshToken)
+	s.Equal(http.StatusUnauthorized, resp.StatusCode)
+}
+
+func (s *AuthTestSuite) TestAuth_ChangePassword() {
+	app := server.NewServer(s.cfg, zap.NewNop(), s.dbPool, s.redisClient, s.keys)
+	ts := httptest.NewServer(app.RegisterHandlers())
+	defer ts.Close()
+
+	username := "user-" + uuid.New().String()[:8]
+
+	resp := s.postCredentials(ts.URL+"/api/register", username, "password")
+	defer resp.Body.Close()
+	s.Require().Equal(http.StatusCreated, resp.StatusCode)
+
+	var login models.AuthResponse
+	err := json.NewDecoder(resp.Body).Decode(&login)
+	s.Require().NoError(err)
+
+	changePassword := func(oldPassword string) int {
+		reqBody := fmt.Sprintf(`{"old_password": "%s", "new_password": "new-password"}`, oldPassword)
+		req, err := http.NewRequest(http.MethodPost, ts.URL+"/api/password", strings.NewReader(reqBody))
+		s.Require().NoError(err)
+
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", login.Token))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := http.DefaultClient.Do(req)
+		s.Require().NoError(err)
+		defer resp.Body.Close()
+
+		return resp.StatusCode
+	}
+
+	s.Equal(http.StatusBadRequest, changePassword("wrong-password"))
+	s.Equal(http.StatusOK, changePassword("password"))
+	s.Equal(http.StatusUnauthorized, changePassword("new-password"))
+
+	resp = s.postCredentials(ts.URL+"/api/login", username, "password")
+	defer resp.Body.Close()
+	s.Equal(http.StatusUnauthorized, resp.StatusCode)
+
+	resp = s.postCredentials(ts.URL+"/api/login", username, "new-password")
+	defer resp.Body.Close()
+	s.Equal(http.StatusOK, resp.StatusCode)
+}
+
+func (s *AuthTestSuite) TestAuth_Login_Lockout() {
+	cfg := *s.cfg
+	cfg.App.MaxLoginAttempts = 2
+	cfg.App.MaxLoginAttemptsPerIP = 0
+	cfg.App.LoginLockout = time.Minute
+	cfg.App.MaxLoginLockout = time.Hour
+
+	app := server.NewServer(&cfg, zap.NewNop(), s.dbPool, s.redisClient, s.keys)
+	ts := httptest.NewServer(app.RegisterHandlers())
+	defer ts.Close()
+
+	username := "user-" + uuid.New().String()[:8]
+
+	resp := s.postCredentials(ts.URL+"/api/register", username, "password")
+	defer resp.Body.Close()
+	s.Require().Equal(http.StatusCreated, resp.StatusCode)
+
+	for range 2 {
+		resp = s.postCredentials(ts.URL+"/api/login", username, "wrong-password")
+		defer resp.Body.Close()
+		s.Equal(http.StatusUnauthorized, resp.StatusCode)
+	}
+
+	resp = s.postCredentials(ts.URL+"/api/login", username, "password")
+	defer resp.Body.Close()
+	s.Equal(http.StatusTooManyRequests, resp.StatusCode)
+	s.Equal("60", resp.Header.Get("Retry-After"))
+}
+
+// Write key in PEM format to a file in the test temp dir
+func (s *AuthTestSuite) writePEM(blockType string, der []byte) string {
+	path := filepath.Join(s.T().TempDir(), uuid.New().String()+".pem")
+	err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600)
+	s.Require().NoError(err)
+	return path
+}
+
+func (s *AuthTestSuite) getJWKS(keys *jwt.Keys, cfg *config.Config) (jwt.JWKS, string) {
+	app := server.NewServer(cfg, zap.NewNop(), s.dbPool, s.redisClient, keys)
+	ts := httptest.NewServer(app.RegisterHandlers())
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/.well-known/jwks.json")
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var jwks jwt.JWKS
+	err = json.NewDecoder(resp.Body).Decode(&jwks)
+	s.Require().NoError(err)
+
+	resp = s.postCredentials(ts.URL+"/api/register", "user-"+uuid.New().String()[:8], "password")
+	defer resp.Body.Close()
+	s.Require().Equal(http.StatusCreated, resp.StatusCode)
+
+	var tokens models.AuthResponse
+	s.Require().NoError(json.NewDecoder(resp.Body).Decode(&tokens))
+
+	header, err := base64.RawURLEncoding.DecodeString(strings.Split(tokens.Token, ".")[0])
+	s.Require().NoError(err)
+
+	var tokenHeader struct {
+		Kid string `json:"kid"`
+		Alg string `json:"alg"`
+	}
+	s.Require().NoError(json.Unmarshal(header, &tokenHeader))
+	s.Equal(cfg.App.JWTAlgorithm, tokenHeader.Alg)
+
+	return jwks, tokenHeader.Kid
+}
+
+func (s *AuthTestSuite) TestAuth_JWKS_HS256() {
+	jwks, kid := s.getJWKS(s.keys, s.cfg)
+	s.Empty(jwks.Keys)
+	s.Empty(kid)
+}
+
+func (s *AuthTestSuite) TestAuth_JWKS_RS256() {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	s.Require().NoError(err)
+	previousKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	s.Require().NoError(err)
+	previousPublicKey, err := x509.MarshalPKIXPublicKey(&previousKey.PublicKey)
+	s.Require().NoError(err)
+
+	cfg := *s.cfg
+	cfg.App.JWTAlgorithm = jwt.AlgorithmRS256
+	cfg.App.JWTPrivateKeyFile = s.writePEM("RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(privateKey))
+	cfg.App.JWTPublicKeyFiles = []string{s.writePEM("PUBLIC KEY", previousPublicKey)}
+
+	keys, err := jwt.LoadKeys(&cfg)
+	s.Require().NoError(err)
+
+	jwks, kid := s.getJWKS(keys, &cfg)
+	s.Require().Len(jwks.Keys, 2)
+	s.NotEmpty(kid)
+
+	kids := make([]string, 0, len(jwks.Keys))
+	for _, key := range jwks.Keys {
+		s.Equal("RSA", key.Kty)
+		s.Equal(jwt.AlgorithmRS256, key.Alg)
+		s.Equal("sig", key.Use)
+		s.NotEmpty(key.N)
+		s.NotEmpty(key.E)
+		s.NotEmpty(key.Kid)
+		kids = append(kids, key.Kid)
+	}
+	s.NotEqual(kids[0], kids[1])
+	s.Contains(kids, kid)
+}
+
+func (s *AuthTestSuite) TestAuth_JWKS_EdDSA() {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	s.Require().NoError(err)
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	s.Require().NoError(err)
+
+	cfg := *s.cfg
+	cfg.App.JWTAlgorithm = jwt.AlgorithmEdDSA
+	cfg.App.JWTPrivateKeyFile = s.writePEM("PRIVATE KEY", der)
+
+	keys, err := jwt.LoadKeys(&cfg)
+	s.Require().NoError(err)
+
+	jwks, kid := s.getJWKS(keys, &cfg)
+	s.Require().Len(jwks.Keys, 1)
+
+	key := jwks.Keys[0]
+	s.Equal("OKP", key.Kty)
+	s.Equal("Ed25519", key.Crv)
+	s.Equal(jwt.AlgorithmEdDSA, key.Alg)
+	s.Equal("sig", key.Use)
+	s.NotEmpty(key.X)
+	s.NotEmpty(key.Kid)
+	s.Equal(key.Kid, kid)
+}
+
+func (s *AuthTestSuite) TestAuth_Sessions() {
+	app := server.NewServer(s.cfg, zap.NewNop(), s.dbPool, s.redisClient, s.keys)
+	ts := httptest.NewServer(app.RegisterHandlers())
+	defer ts.Close()
+
+	username := "user-" + uuid.New().String()[:8]
+
+	resp := s.postCredentials(ts.URL+"/api/register", username, "password")
+	defer resp.Body.Close()
+	s.Require().Equal(http.StatusCreated, resp.StatusCode)
+
+	var first models.AuthResponse
+	s.Require().NoError(json.NewDecoder(resp.Body).Decode(&first))
+
+	resp = s.postCredentials(ts.URL+"/api/login", username, "password")
+	defer resp.Body.Close()
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var second models.AuthResponse
+	s.Require().NoError(json.NewDecoder(resp.Body).Decode(&second))
+
+	sessionsRequest := func(method, path, token string) *http.Response {
+		req, err := http.NewRequest(method, ts.URL+"/api/sessions"+path, nil)
+		s.Require().NoError(err)
+
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+
+		resp, err := http.DefaultClient.Do(req)
+		s.Require().NoError(err)
+		return resp
+	}
+
+	resp = sessionsRequest(http.MethodGet, "", first.Token)
+	defer resp.Body.Close()
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var sessions []models.Session
+	s.Require().NoError(json.NewDecoder(resp.Body).Decode(&sessions))
+	s.Require().Len(sessions, 2)
+	s.Equal("Go-http-client/1.1", sessions[0].UserAgent)
+	s.NotEmpty(sessions[0].IP)
+
+	var otherID string
+	for _, session := range sessions {
+		if !session.Current {
+			otherID = session.ID
+		}
+	}
+	s.Require().NotEmpty(otherID)
+
+	resp = sessionsRequest(http.MethodDelete, "/not-a-uuid", first.Token)
+	defer resp.Body.Close()
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+
+	resp = sessionsRequest(http.MethodDelete, "/"+uuid.NewString(), first.Token)
+	defer resp.Body.Close()
+	s.Equal(http.StatusNotFound, resp.StatusCode)
+
+	resp = sessionsRequest(http.MethodDelete, "/"+otherID, first.Token)
+	defer resp.Body.Close()
+	s.Equal(http.StatusOK, resp.StatusCode)
+
+	resp = sessionsRequest(http.MethodGet, "", second.Token)
+	defer resp.Body.Close()
+	s.Equal(http.StatusUnauthorized, resp.StatusCode)
+
+	resp, _ = s.refresh(ts.URL, second.RefreshToken)
+	s.Equal(http.StatusUnauthorized, resp.StatusCode)
+
+	resp = sessionsRequest(http.MethodGet, "", first.Token)
+	defer resp.Body.Close()
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	sessions = nil
+	s.Require().NoError(json.NewDecoder(resp.Body).Decode(&sessions))
+	s.Require().Len(sessions, 1)
+	s.True(sessions[0].Current)
+}
+
+func (s *AuthTestSuite) TestAuth_Login_IgnoresUntrustedForwardedFor() {
+	app := server.NewServer(s.cfg, zap.NewNop(), s.dbPool, s.redisClient, s.keys)
+	ts := httptest.NewServer(app.RegisterHandlers())
+	defer ts.Close()
+
+	username := "user-" + uuid.New().String()[:8]
+
+	resp := s.postCredentials(ts.URL+"/api/register", username, "password")
+	defer resp.Body.Close()
+	s.Require().Equal(http.StatusCreated, resp.StatusCode)
+
+	reqBody := fmt.Sprintf(`{"username": "%s", "password": "password"}`, username)
+	req, err := http.NewRequest(http.MethodPost, ts.URL+"/api/login", strings.NewReader(reqBody))
+	s.Require().NoError(err)
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Forwarded-For", "203.0.113.7")
+	req.Header.Set("X-Real-IP", "203.0.113.7")
+
+	resp, err = http.DefaultClient.Do(req)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var auth models.AuthResponse
+	s.Require().NoError(json.NewDecoder(resp.Body).Decode(&auth))
+
+	req, err = http.NewRequest(http.MethodGet, ts.URL+"/api/sessions", nil)
+	s.Require().NoError(err)
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", auth.Token))
+
+	resp, err = http.DefaultClient.Do(req)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var sessions []models.Session
+	s.Require().NoError(json.NewDecoder(resp.Body).Decode(&sessions))
+	s.Require().NotEmpty(sessions)
+	for _, session := range sessions {
+		s.Equal("127.0.0.1", session.IP)
+	}
+}
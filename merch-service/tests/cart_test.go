@@ -0,0 +1,170 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/google/uuid"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+
+	"cyansnbrst/merch-service/internal/models"
+	"cyansnbrst/merch-service/internal/server"
+	"cyansnbrst/merch-service/pkg/db"
+)
+
+type CartTestSuite struct {
+	BaseTestSuite
+}
+
+func TestCartSuite(t *testing.T) {
+	suite.Run(t, new(CartTestSuite))
+}
+
+func (s *CartTestSuite) SetupSuite() {
+	s.BaseTestSuite.SetupSuite()
+}
+
+func (s *CartTestSuite) TearDownSuite() {
+	s.BaseTestSuite.TearDownSuite()
+}
+
+func (s *CartTestSuite) addToCart(ts *httptest.Server, token, item string, quantity int) {
+	reqBody := fmt.Sprintf(`{"item": "%s", "quantity": %d}`, item, quantity)
+	req, err := http.NewRequest(http.MethodPost, ts.URL+"/api/cart", strings.NewReader(reqBody))
+	s.Require().NoError(err)
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+}
+
+func (s *CartTestSuite) TestCart_Checkout_Success() {
+	app := server.NewServer(s.cfg, zap.NewNop(), s.dbPool, s.redisClient, s.keys)
+	ts := httptest.NewServer(app.RegisterHandlers())
+	defer ts.Close()
+
+	var id int
+	err := s.dbPool.QueryRow(context.Background(),
+		`INSERT INTO users (username, password_hash) 
+		VALUES ($1, $2) 
+		RETURNING id`,
+		"user-"+uuid.New().String(), s.passwordHash,
+	).Scan(&id)
+	s.Require().NoError(err)
+
+	token := s.login(id)
+
+	s.addToCart(ts, token, "t-shirt", 1)
+	s.addToCart(ts, token, "cup", 2)
+	s.addToCart(ts, token, "socks", 3)
+
+	req, err := http.NewRequest(http.MethodGet, ts.URL+"/api/cart", nil)
+	s.Require().NoError(err)
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+
+	resp, err := http.DefaultClient.Do(req)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+
+	s.Equal(http.StatusOK, resp.StatusCode)
+
+	var userCart models.Cart
+	err = json.NewDecoder(resp.Body).Decode(&userCart)
+	s.Require().NoError(err)
+	s.Len(userCart.Items, 3)
+
+	req, err = http.NewRequest(http.MethodPost, ts.URL+"/api/cart/checkout", nil)
+	s.Require().NoError(err)
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+
+	resp, err = http.DefaultClient.Do(req)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+
+	s.Equal(http.StatusOK, resp.StatusCode)
+
+	var balance, items int
+	err = s.dbPool.QueryRow(context.Background(),
+		`SELECT balance FROM users 
+		WHERE id = $1`,
+		id,
+	).Scan(&balance)
+	s.Require().NoError(err)
+	s.Equal(1000-80-2*20-3*10, balance)
+
+	err = s.dbPool.QueryRow(context.Background(),
+		`SELECT SUM(quantity) FROM inventory_items 
+		WHERE user_id = $1`,
+		id,
+	).Scan(&items)
+	s.Require().NoError(err)
+	s.Equal(6, items)
+}
+
+func (s *CartTestSuite) TestCart_Checkout_InsufficientFunds() {
+	app := server.NewServer(s.cfg, zap.NewNop(), s.dbPool, s.redisClient, s.keys)
+	ts := httptest.NewServer(app.RegisterHandlers())
+	defer ts.Close()
+
+	var id int
+	err := s.dbPool.QueryRow(context.Background(),
+		`INSERT INTO users (username, password_hash, balance) 
+		VALUES ($1, $2, $3) 
+		RETURNING id`,
+		"user-"+uuid.New().String(), s.passwordHash, 100,
+	).Scan(&id)
+	s.Require().NoError(err)
+
+	token := s.login(id)
+
+	s.addToCart(ts, token, "t-shirt", 1)
+	s.addToCart(ts, token, "cup", 2)
+
+	req, err := http.NewRequest(http.MethodPost, ts.URL+"/api/cart/checkout", nil)
+	s.Require().NoError(err)
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+
+	resp, err := http.DefaultClient.Do(req)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+
+	var response map[string]interface{}
+	err = json.NewDecoder(resp.Body).Decode(&response)
+	s.Require().NoError(err)
+	s.Equal(db.ErrInsufficientFunds.Error(), response["errors"])
+
+	var balance, items int
+	err = s.dbPool.QueryRow(context.Background(),
+		`SELECT balance FROM users 
+		WHERE id = $1`,
+		id,
+	).Scan(&balance)
+	s.Require().NoError(err)
+	s.Equal(100, balance)
+
+	err = s.dbPool.QueryRow(context.Background(),
+		`SELECT COUNT(*) FROM inventory_items 
+		WHERE user_id = $1`,
+		id,
+	).Scan(&items)
+	s.Require().NoError(err)
+	s.Equal(0, items)
+}
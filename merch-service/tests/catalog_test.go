@@ -0,0 +1,182 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/google/uuid"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+
+	"cyansnbrst/merch-service/internal/models"
+	"cyansnbrst/merch-service/internal/server"
+)
+
+type CatalogTestSuite struct {
+	BaseTestSuite
+}
+
+func TestCatalogSuite(t *testing.T) {
+	suite.Run(t, new(CatalogTestSuite))
+}
+
+func (s *CatalogTestSuite) SetupSuite() {
+	s.BaseTestSuite.SetupSuite()
+}
+
+func (s *CatalogTestSuite) TearDownSuite() {
+	s.BaseTestSuite.TearDownSuite()
+}
+
+func (s *CatalogTestSuite) TestCatalog_CreateAndRetireItem() {
+	var adminID int
+	err := s.dbPool.QueryRow(context.Background(),
+		`INSERT INTO users (username, password_hash, role) 
+		VALUES ($1, $2, $3) 
+		RETURNING id`,
+		"admin-"+uuid.New().String(), s.passwordHash, models.RoleAdmin,
+	).Scan(&adminID)
+	s.Require().NoError(err)
+
+	app := server.NewServer(s.cfg, zap.NewNop(), s.dbPool, s.redisClient, s.keys)
+	ts := httptest.NewServer(app.RegisterHandlers())
+	defer ts.Close()
+
+	token := s.login(adminID)
+
+	item := "sticker-" + uuid.New().String()[:8]
+	reqBody := fmt.Sprintf(`{"name": "%s", "price": %d}`, item, 5)
+	req, err := http.NewRequest(http.MethodPost, ts.URL+"/api/admin/items", strings.NewReader(reqBody))
+	s.Require().NoError(err)
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+
+	s.Equal(http.StatusCreated, resp.StatusCode)
+
+	var product models.Product
+	err = json.NewDecoder(resp.Body).Decode(&product)
+	s.Require().NoError(err)
+	s.Equal(item, product.Name)
+	s.Equal(int64(5), product.Price)
+	s.Nil(product.RetiredAt)
+
+	req, err = http.NewRequest(http.MethodPost, ts.URL+"/api/admin/items", strings.NewReader(reqBody))
+	s.Require().NoError(err)
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err = http.DefaultClient.Do(req)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+
+	s.Equal(http.StatusConflict, resp.StatusCode)
+
+	req, err = http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/api/admin/items/%d", ts.URL, product.ID), nil)
+	s.Require().NoError(err)
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+
+	resp, err = http.DefaultClient.Do(req)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+
+	s.Equal(http.StatusOK, resp.StatusCode)
+
+	req, err = http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/buy/%s", ts.URL, item), nil)
+	s.Require().NoError(err)
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+
+	resp, err = http.DefaultClient.Do(req)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+}
+
+func (s *CatalogTestSuite) TestCatalog_NotAdmin() {
+	app := server.NewServer(s.cfg, zap.NewNop(), s.dbPool, s.redisClient, s.keys)
+	ts := httptest.NewServer(app.RegisterHandlers())
+	defer ts.Close()
+
+	var id int
+	err := s.dbPool.QueryRow(context.Background(),
+		`INSERT INTO users (username, password_hash) 
+		VALUES ($1, $2) 
+		RETURNING id`,
+		"user-"+uuid.New().String(), s.passwordHash,
+	).Scan(&id)
+	s.Require().NoError(err)
+
+	token := s.login(id)
+
+	req, err := http.NewRequest(http.MethodGet, ts.URL+"/api/admin/items", nil)
+	s.Require().NoError(err)
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+
+	resp, err := http.DefaultClient.Do(req)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+
+	s.Equal(http.StatusForbidden, resp.StatusCode)
+
+	var response map[string]interface{}
+	err = json.NewDecoder(resp.Body).Decode(&response)
+	s.Require().NoError(err)
+	s.Equal("you don't have permission to access this resource", response["errors"])
+}
+
+func (s *CatalogTestSuite) TestCatalog_GetItems_Affordable() {
+	app := server.NewServer(s.cfg, zap.NewNop(), s.dbPool, s.redisClient, s.keys)
+	ts := httptest.NewServer(app.RegisterHandlers())
+	defer ts.Close()
+
+	var id int
+	err := s.dbPool.QueryRow(context.Background(),
+		`INSERT INTO users (username, password_hash, balance) 
+		VALUES ($1, $2, $3) 
+		RETURNING id`,
+		"user-"+uuid.New().String(), s.passwordHash, 20,
+	).Scan(&id)
+	s.Require().NoError(err)
+
+	token := s.login(id)
+
+	req, err := http.NewRequest(http.MethodGet, ts.URL+"/api/items?affordable=true&sort=price_desc", nil)
+	s.Require().NoError(err)
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+
+	resp, err := http.DefaultClient.Do(req)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+
+	s.Equal(http.StatusOK, resp.StatusCode)
+
+	var items []models.CatalogItem
+	err = json.NewDecoder(resp.Body).Decode(&items)
+	s.Require().NoError(err)
+	s.Require().NotEmpty(items)
+
+	for i, item := range items {
+		s.True(item.Available)
+		s.LessOrEqual(item.Price, int64(20))
+		if i > 0 {
+			s.GreaterOrEqual(items[i-1].Price, item.Price)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+
+	"cyansnbrst/merch-service/internal/models"
+	"cyansnbrst/merch-service/internal/server"
+)
+
+type InvoiceTestSuite struct {
+	BaseTestSuite
+}
+
+func TestInvoiceSuite(t *testing.T) {
+	suite.Run(t, new(InvoiceTestSuite))
+}
+
+func (s *InvoiceTestSuite) SetupSuite() {
+	s.BaseTestSuite.SetupSuite()
+}
+
+func (s *InvoiceTestSuite) TearDownSuite() {
+	s.BaseTestSuite.TearDownSuite()
+}
+
+func (s *InvoiceTestSuite) doRequest(method, url, token, body string) *http.Response {
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	s.Require().NoError(err)
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	s.Require().NoError(err)
+
+	return resp
+}
+
+func (s *InvoiceTestSuite) createInvoice(ts *httptest.Server, token, fromUser string, amount int) models.Invoice {
+	reqBody := fmt.Sprintf(`{"from_user": "%s", "amount": %d, "comment": "lunch"}`, fromUser, amount)
+	resp := s.doRequest(http.MethodPost, ts.URL+"/api/invoices", token, reqBody)
+	defer resp.Body.Close()
+
+	s.Require().Equal(http.StatusCreated, resp.StatusCode)
+
+	var inv models.Invoice
+	err := json.NewDecoder(resp.Body).Decode(&inv)
+	s.Require().NoError(err)
+
+	return inv
+}
+
+func (s *InvoiceTestSuite) TestInvoice_Accept() {
+	app := server.NewServer(s.cfg, zap.NewNop(), s.dbPool, s.redisClient, s.keys)
+	ts := httptest.NewServer(app.RegisterHandlers())
+	defer ts.Close()
+
+	payerID, payer, payerToken := s.createUser(models.RoleUser)
+	requesterID, requester, requesterToken := s.createUser(models.RoleUser)
+
+	inv := s.createInvoice(ts, requesterToken, payer, 150)
+	s.Equal(payer, inv.FromUser)
+	s.Equal(requester, inv.ToUser)
+	s.Equal(models.InvoiceStatusPending, inv.Status)
+
+	resp := s.doRequest(http.MethodGet, ts.URL+"/api/invoices?direction=incoming", payerToken, "")
+	defer resp.Body.Close()
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var incoming []models.Invoice
+	err := json.NewDecoder(resp.Body).Decode(&incoming)
+	s.Require().NoError(err)
+	s.Require().Len(incoming, 1)
+	s.Equal(inv.ID, incoming[0].ID)
+
+	resp = s.doRequest(http.MethodPost, fmt.Sprintf("%s/api/invoices/%d/accept", ts.URL, inv.ID), requesterToken, "")
+	resp.Body.Close()
+	s.Equal(http.StatusNotFound, resp.StatusCode)
+
+	resp = s.doRequest(http.MethodPost, fmt.Sprintf("%s/api/invoices/%d/accept", ts.URL, inv.ID), payerToken, "")
+	resp.Body.Close()
+	s.Equal(http.StatusOK, resp.StatusCode)
+
+	resp = s.doRequest(http.MethodPost, fmt.Sprintf("%s/api/invoices/%d/accept", ts.URL, inv.ID), payerToken, "")
+	resp.Body.Close()
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+
+	var payerBalance, requesterBalance int
+	err = s.dbPool.QueryRow(context.Background(),
+		`SELECT balance FROM users 
+		WHERE id = $1`,
+		payerID,
+	).Scan(&payerBalance)
+	s.Require().NoError(err)
+	s.Equal(850, payerBalance)
+
+	err = s.dbPool.QueryRow(context.Background(),
+		`SELECT balance FROM users 
+		WHERE id = $1`,
+		requesterID,
+	).Scan(&requesterBalance)
+	s.Require().NoError(err)
+	s.Equal(1150, requesterBalance)
+
+	var status string
+	err = s.dbPool.QueryRow(context.Background(),
+		`SELECT status FROM invoices 
+		WHERE id = $1`,
+		inv.ID,
+	).Scan(&status)
+	s.Require().NoError(err)
+	s.Equal(models.InvoiceStatusAccepted, status)
+}
+
+func (s *InvoiceTestSuite) TestInvoice_Decline() {
+	app := server.NewServer(s.cfg, zap.NewNop(), s.dbPool, s.redisClient, s.keys)
+	ts := httptest.NewServer(app.RegisterHandlers())
+	defer ts.Close()
+
+	payerID, payer, payerToken := s.createUser(models.RoleUser)
+	_, _, requesterToken := s.createUser(models.RoleUser)
+
+	inv := s.createInvoice(ts, requesterToken, payer, 150)
+
+	resp := s.doRequest(http.MethodPost, fmt.Sprintf("%s/api/invoices/%d/decline", ts.URL, inv.ID), payerToken, "")
+	resp.Body.Close()
+	s.Equal(http.StatusOK, resp.StatusCode)
+
+	resp = s.doRequest(http.MethodPost, fmt.Sprintf("%s/api/invoices/%d/accept", ts.URL, inv.ID), payerToken, "")
+	resp.Body.Close()
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+
+	var balance int
+	err := s.dbPool.QueryRow(context.Background(),
+		`SELECT balance FROM users 
+		WHERE id = $1`,
+		payerID,
+	).Scan(&balance)
+	s.Require().NoError(err)
+	s.Equal(1000, balance)
+}
+
+func (s *InvoiceTestSuite) TestInvoice_SelfInvoice() {
+	app := server.NewServer(s.cfg, zap.NewNop(), s.dbPool, s.redisClient, s.keys)
+	ts := httptest.NewServer(app.RegisterHandlers())
+	defer ts.Close()
+
+	_, username, token := s.createUser(models.RoleUser)
+
+	reqBody := fmt.Sprintf(`{"from_user": "%s", "amount": 10}`, username)
+	resp := s.doRequest(http.MethodPost, ts.URL+"/api/invoices", token, reqBody)
+	defer resp.Body.Close()
+
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+}
@@ -16,17 +16,14 @@ import (
 	"github.com/stretchr/testify/suite"
 	"go.uber.org/zap"
 
-	"cyansnbrst/merch-service/internal/auth"
-	"cyansnbrst/merch-service/internal/auth/repository"
-	"cyansnbrst/merch-service/internal/auth/usecase"
 	"cyansnbrst/merch-service/internal/models"
 	"cyansnbrst/merch-service/internal/server"
 	"cyansnbrst/merch-service/pkg/db"
+	"cyansnbrst/merch-service/pkg/db/redis"
 )
 
 type MerchTestSuite struct {
 	BaseTestSuite
-	authUC auth.UseCase
 }
 
 func TestMerchSuite(t *testing.T) {
@@ -35,9 +32,6 @@ func TestMerchSuite(t *testing.T) {
 
 func (s *MerchTestSuite) SetupSuite() {
 	s.BaseTestSuite.SetupSuite()
-
-	authRepo := repository.NewAuthRepo(s.dbPool)
-	s.authUC = usecase.NewAuthUseCase(s.cfg, authRepo)
 }
 
 func (s *MerchTestSuite) TearDownSuite() {
@@ -45,13 +39,13 @@ func (s *MerchTestSuite) TearDownSuite() {
 }
 
 func (s *MerchTestSuite) TestMerch_BuyItem_Success() {
-	app := server.NewServer(s.cfg, zap.NewNop(), s.dbPool, s.redisClient)
+	app := server.NewServer(s.cfg, zap.NewNop(), s.dbPool, s.redisClient, s.keys)
 	ts := httptest.NewServer(app.RegisterHandlers())
 	defer ts.Close()
 
 	user := models.User{
 		Username:     "user-" + uuid.New().String(),
-		PasswordHash: "asdlfkas2op2348n3",
+		PasswordHash: s.passwordHash,
 	}
 	item := "pink-hoody"
 	itemID := 10
@@ -66,8 +60,7 @@ func (s *MerchTestSuite) TestMerch_BuyItem_Success() {
 	s.Require().NoError(err)
 	s.Equal(1000, balance)
 
-	token, err := s.authUC.GenerateJWT(&models.User{ID: int64(id)})
-	s.Require().NoError(err)
+	token := s.login(id)
 
 	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/buy/%s", ts.URL, item), nil)
 	s.Require().NoError(err)
@@ -99,13 +92,13 @@ func (s *MerchTestSuite) TestMerch_BuyItem_Success() {
 }
 
 func (s *MerchTestSuite) TestMerch_BuyItem_InsufficientFunds() {
-	app := server.NewServer(s.cfg, zap.NewNop(), s.dbPool, s.redisClient)
+	app := server.NewServer(s.cfg, zap.NewNop(), s.dbPool, s.redisClient, s.keys)
 	ts := httptest.NewServer(app.RegisterHandlers())
 	defer ts.Close()
 
 	user := models.User{
 		Username:     "user-" + uuid.New().String(),
-		PasswordHash: "asdlfkas2op2348n3",
+		PasswordHash: s.passwordHash,
 	}
 	item := "pink-hoody"
 
@@ -119,8 +112,7 @@ func (s *MerchTestSuite) TestMerch_BuyItem_InsufficientFunds() {
 	s.Require().NoError(err)
 	s.Equal(300, balance)
 
-	token, err := s.authUC.GenerateJWT(&models.User{ID: int64(id)})
-	s.Require().NoError(err)
+	token := s.login(id)
 
 	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/buy/%s", ts.URL, item), nil)
 	s.Require().NoError(err)
@@ -148,13 +140,13 @@ func (s *MerchTestSuite) TestMerch_BuyItem_InsufficientFunds() {
 }
 
 func (s *MerchTestSuite) TestMerch_BuyItem_ItemNotFound() {
-	app := server.NewServer(s.cfg, zap.NewNop(), s.dbPool, s.redisClient)
+	app := server.NewServer(s.cfg, zap.NewNop(), s.dbPool, s.redisClient, s.keys)
 	ts := httptest.NewServer(app.RegisterHandlers())
 	defer ts.Close()
 
 	user := models.User{
 		Username:     "user-" + uuid.New().String(),
-		PasswordHash: "asdlfkas2op2348n3",
+		PasswordHash: s.passwordHash,
 	}
 	item := "non-existent-item"
 
@@ -168,8 +160,7 @@ func (s *MerchTestSuite) TestMerch_BuyItem_ItemNotFound() {
 	s.Require().NoError(err)
 	s.Equal(1000, balance)
 
-	token, err := s.authUC.GenerateJWT(&models.User{ID: int64(id)})
-	s.Require().NoError(err)
+	token := s.login(id)
 
 	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/buy/%s", ts.URL, item), nil)
 	s.Require().NoError(err)
@@ -197,7 +188,7 @@ func (s *MerchTestSuite) TestMerch_BuyItem_ItemNotFound() {
 }
 
 func (s *MerchTestSuite) TestMerch_Unauthorized() {
-	app := server.NewServer(s.cfg, zap.NewNop(), s.dbPool, s.redisClient)
+	app := server.NewServer(s.cfg, zap.NewNop(), s.dbPool, s.redisClient, s.keys)
 	ts := httptest.NewServer(app.RegisterHandlers())
 	defer ts.Close()
 
@@ -219,7 +210,7 @@ func (s *MerchTestSuite) TestMerch_Unauthorized() {
 }
 
 func (s *MerchTestSuite) TestMerch_SendCoins_Success() {
-	app := server.NewServer(s.cfg, zap.NewNop(), s.dbPool, s.redisClient)
+	app := server.NewServer(s.cfg, zap.NewNop(), s.dbPool, s.redisClient, s.keys)
 	ts := httptest.NewServer(app.RegisterHandlers())
 	defer ts.Close()
 
@@ -251,11 +242,10 @@ func (s *MerchTestSuite) TestMerch_SendCoins_Success() {
 	s.Require().NoError(err)
 	s.Equal(500, receiverBalance)
 
-	token, err := s.authUC.GenerateJWT(&models.User{ID: int64(senderID)})
-	s.Require().NoError(err)
+	token := s.login(senderID)
 
 	transferAmount := 300
-	reqBody := fmt.Sprintf(`{"to_user": "%s", "amount": %d}`, receiver.Username, transferAmount)
+	reqBody := fmt.Sprintf(`{"to_user": "%s", "amount": %d, "comment": "thanks"}`, receiver.Username, transferAmount)
 	req, err := http.NewRequest(http.MethodPost, ts.URL+"/api/sendCoin", strings.NewReader(reqBody))
 	s.Require().NoError(err)
 
@@ -286,23 +276,25 @@ func (s *MerchTestSuite) TestMerch_SendCoins_Success() {
 
 	var transactionID int
 	var fromID, toID, amount int
+	var comment string
 	var transactionDate time.Time
 	err = s.dbPool.QueryRow(context.Background(),
-		`SELECT id, from_id, to_id, amount, transaction_date 
+		`SELECT id, from_id, to_id, amount, comment, transaction_date 
 		FROM transactions 
 		WHERE from_id = $1 AND to_id = $2 AND amount = $3`,
 		senderID, receiverID, transferAmount,
-	).Scan(&transactionID, &fromID, &toID, &amount, &transactionDate)
+	).Scan(&transactionID, &fromID, &toID, &amount, &comment, &transactionDate)
 	s.Require().NoError(err)
 
 	s.Equal(senderID, fromID)
 	s.Equal(receiverID, toID)
 	s.Equal(transferAmount, amount)
+	s.Equal("thanks", comment)
 	s.WithinDuration(time.Now(), transactionDate, time.Second)
 }
 
 func (s *MerchTestSuite) TestMerch_SendCoins_UserNotFound() {
-	app := server.NewServer(s.cfg, zap.NewNop(), s.dbPool, s.redisClient)
+	app := server.NewServer(s.cfg, zap.NewNop(), s.dbPool, s.redisClient, s.keys)
 	ts := httptest.NewServer(app.RegisterHandlers())
 	defer ts.Close()
 
@@ -320,8 +312,7 @@ func (s *MerchTestSuite) TestMerch_SendCoins_UserNotFound() {
 	s.Require().NoError(err)
 	s.Equal(1000, senderBalance)
 
-	token, err := s.authUC.GenerateJWT(&models.User{ID: int64(senderID)})
-	s.Require().NoError(err)
+	token := s.login(senderID)
 
 	transferAmount := 300
 	reqBody := fmt.Sprintf(`{"to_user": "%s", "amount": %d}`, "some-user", transferAmount)
@@ -344,7 +335,7 @@ func (s *MerchTestSuite) TestMerch_SendCoins_UserNotFound() {
 }
 
 func (s *MerchTestSuite) TestMerch_SendCoins_InsufficientFunds() {
-	app := server.NewServer(s.cfg, zap.NewNop(), s.dbPool, s.redisClient)
+	app := server.NewServer(s.cfg, zap.NewNop(), s.dbPool, s.redisClient, s.keys)
 	ts := httptest.NewServer(app.RegisterHandlers())
 	defer ts.Close()
 
@@ -376,8 +367,7 @@ func (s *MerchTestSuite) TestMerch_SendCoins_InsufficientFunds() {
 	s.Require().NoError(err)
 	s.Equal(500, receiverBalance)
 
-	token, err := s.authUC.GenerateJWT(&models.User{ID: int64(senderID)})
-	s.Require().NoError(err)
+	token := s.login(senderID)
 
 	transferAmount := 300
 	reqBody := fmt.Sprintf(`{"to_user": "%s", "amount": %d}`, receiver.Username, transferAmount)
@@ -400,12 +390,11 @@ func (s *MerchTestSuite) TestMerch_SendCoins_InsufficientFunds() {
 }
 
 func (s *MerchTestSuite) TestMerch_SendCoins_InvalidJSON() {
-	app := server.NewServer(s.cfg, zap.NewNop(), s.dbPool, s.redisClient)
+	app := server.NewServer(s.cfg, zap.NewNop(), s.dbPool, s.redisClient, s.keys)
 	ts := httptest.NewServer(app.RegisterHandlers())
 	defer ts.Close()
 
-	token, err := s.authUC.GenerateJWT(&models.User{ID: 1})
-	s.Require().NoError(err)
+	_, _, token := s.createUser(models.RoleUser)
 
 	reqBody := `{"to_user": user-123, "amount": 300}`
 	req, err := http.NewRequest(http.MethodPost, ts.URL+"/api/sendCoin", strings.NewReader(reqBody))
@@ -422,7 +411,7 @@ func (s *MerchTestSuite) TestMerch_SendCoins_InvalidJSON() {
 }
 
 func (s *MerchTestSuite) TestMerch_SendCoins_NegativeAmount() {
-	app := server.NewServer(s.cfg, zap.NewNop(), s.dbPool, s.redisClient)
+	app := server.NewServer(s.cfg, zap.NewNop(), s.dbPool, s.redisClient, s.keys)
 	ts := httptest.NewServer(app.RegisterHandlers())
 	defer ts.Close()
 
@@ -454,8 +443,7 @@ func (s *MerchTestSuite) TestMerch_SendCoins_NegativeAmount() {
 	s.Require().NoError(err)
 	s.Equal(1000, receiverBalance)
 
-	token, err := s.authUC.GenerateJWT(&models.User{ID: int64(senderID)})
-	s.Require().NoError(err)
+	token := s.login(senderID)
 
 	transferAmount := -300
 	reqBody := fmt.Sprintf(`{"to_user": "%s", "amount": %d}`, receiver.Username, transferAmount)
@@ -478,7 +466,7 @@ func (s *MerchTestSuite) TestMerch_SendCoins_NegativeAmount() {
 }
 
 func (s *MerchTestSuite) TestMerch_SendCoins_SelfTransfer() {
-	app := server.NewServer(s.cfg, zap.NewNop(), s.dbPool, s.redisClient)
+	app := server.NewServer(s.cfg, zap.NewNop(), s.dbPool, s.redisClient, s.keys)
 	ts := httptest.NewServer(app.RegisterHandlers())
 	defer ts.Close()
 
@@ -496,8 +484,7 @@ func (s *MerchTestSuite) TestMerch_SendCoins_SelfTransfer() {
 	s.Require().NoError(err)
 	s.Equal(1000, userBalance)
 
-	token, err := s.authUC.GenerateJWT(&models.User{ID: int64(userID)})
-	s.Require().NoError(err)
+	token := s.login(userID)
 
 	transferAmount := 300
 	reqBody := fmt.Sprintf(`{"to_user": "%s", "amount": %d}`, user.Username, transferAmount)
@@ -520,7 +507,7 @@ func (s *MerchTestSuite) TestMerch_SendCoins_SelfTransfer() {
 }
 
 func (s *MerchTestSuite) TestMerch_GetInfo_Success() {
-	app := server.NewServer(s.cfg, zap.NewNop(), s.dbPool, s.redisClient)
+	app := server.NewServer(s.cfg, zap.NewNop(), s.dbPool, s.redisClient, s.keys)
 	ts := httptest.NewServer(app.RegisterHandlers())
 	defer ts.Close()
 
@@ -578,8 +565,7 @@ func (s *MerchTestSuite) TestMerch_GetInfo_Success() {
 	)
 	s.Require().NoError(err)
 
-	token, err := s.authUC.GenerateJWT(&models.User{ID: int64(userID)})
-	s.Require().NoError(err)
+	token := s.login(userID)
 
 	req, err := http.NewRequest(http.MethodGet, ts.URL+"/api/info", nil)
 	s.Require().NoError(err)
@@ -609,8 +595,746 @@ func (s *MerchTestSuite) TestMerch_GetInfo_Success() {
 	receivedTx := infoResponse.CoinHistory.Received[0]
 	s.Equal(sender.Username, receivedTx.FromUser)
 	s.Equal(int64(300), receivedTx.Amount)
+	s.NotZero(receivedTx.ID)
+	s.WithinDuration(time.Now(), receivedTx.Date, time.Minute)
 
 	sentTx := infoResponse.CoinHistory.Sent[0]
 	s.Equal(receiver.Username, sentTx.ToUser)
 	s.Equal(int64(200), sentTx.Amount)
+	s.NotZero(sentTx.ID)
+	s.WithinDuration(time.Now(), sentTx.Date, time.Minute)
+}
+
+func (s *MerchTestSuite) TestMerch_BuyItem_OutOfStock() {
+	app := server.NewServer(s.cfg, zap.NewNop(), s.dbPool, s.redisClient, s.keys)
+	ts := httptest.NewServer(app.RegisterHandlers())
+	defer ts.Close()
+
+	item := "limited-" + uuid.New().String()[:8]
+	_, err := s.dbPool.Exec(context.Background(),
+		`INSERT INTO items (name, price, stock) 
+		VALUES ($1, $2, $3)`,
+		item, 100, 1,
+	)
+	s.Require().NoError(err)
+
+	var id int
+	err = s.dbPool.QueryRow(context.Background(),
+		`INSERT INTO users (username, password_hash) 
+		VALUES ($1, $2) 
+		RETURNING id`,
+		"user-"+uuid.New().String(), s.passwordHash,
+	).Scan(&id)
+	s.Require().NoError(err)
+
+	token := s.login(id)
+
+	for _, expectedStatus := range []int{http.StatusOK, http.StatusBadRequest} {
+		req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/buy/%s", ts.URL, item), nil)
+		s.Require().NoError(err)
+
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+
+		resp, err := http.DefaultClient.Do(req)
+		s.Require().NoError(err)
+		resp.Body.Close()
+
+		s.Equal(expectedStatus, resp.StatusCode)
+	}
+
+	var stock, balance int
+	err = s.dbPool.QueryRow(context.Background(),
+		`SELECT stock FROM items 
+		WHERE name = $1`,
+		item,
+	).Scan(&stock)
+	s.Require().NoError(err)
+	s.Equal(0, stock)
+
+	err = s.dbPool.QueryRow(context.Background(),
+		`SELECT balance FROM users 
+		WHERE id = $1`,
+		id,
+	).Scan(&balance)
+	s.Require().NoError(err)
+	s.Equal(900, balance)
+}
+
+func (s *MerchTestSuite) TestMerch_BuyItem_Quantity() {
+	app := server.NewServer(s.cfg, zap.NewNop(), s.dbPool, s.redisClient, s.keys)
+	ts := httptest.NewServer(app.RegisterHandlers())
+	defer ts.Close()
+
+	item := "pen"
+	itemID := 4
+
+	var id int
+	err := s.dbPool.QueryRow(context.Background(),
+		`INSERT INTO users (username, password_hash) 
+		VALUES ($1, $2) 
+		RETURNING id`,
+		"user-"+uuid.New().String(), s.passwordHash,
+	).Scan(&id)
+	s.Require().NoError(err)
+
+	token := s.login(id)
+
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/buy/%s?quantity=%d", ts.URL, item, 10), nil)
+	s.Require().NoError(err)
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+
+	resp, err := http.DefaultClient.Do(req)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+
+	s.Equal(http.StatusOK, resp.StatusCode)
+
+	var balance, quantity int
+	err = s.dbPool.QueryRow(context.Background(),
+		`SELECT balance FROM users 
+		WHERE id = $1`,
+		id,
+	).Scan(&balance)
+	s.Require().NoError(err)
+	s.Equal(900, balance)
+
+	err = s.dbPool.QueryRow(context.Background(),
+		`SELECT quantity FROM inventory_items 
+		WHERE user_id = $1 AND item_id = $2`,
+		id, itemID,
+	).Scan(&quantity)
+	s.Require().NoError(err)
+	s.Equal(10, quantity)
+
+	var price int
+	var createdAt time.Time
+	err = s.dbPool.QueryRow(context.Background(),
+		`SELECT price, quantity, created_at FROM orders 
+		WHERE user_id = $1 AND item_id = $2`,
+		id, itemID,
+	).Scan(&price, &quantity, &createdAt)
+	s.Require().NoError(err)
+	s.Equal(10, price)
+	s.Equal(10, quantity)
+	s.WithinDuration(time.Now(), createdAt, time.Second)
+}
+
+func (s *MerchTestSuite) TestMerch_RefundOrder_Success() {
+	app := server.NewServer(s.cfg, zap.NewNop(), s.dbPool, s.redisClient, s.keys)
+	ts := httptest.NewServer(app.RegisterHandlers())
+	defer ts.Close()
+
+	item := "cup"
+	itemID := 2
+
+	var id int
+	err := s.dbPool.QueryRow(context.Background(),
+		`INSERT INTO users (username, password_hash) 
+		VALUES ($1, $2) 
+		RETURNING id`,
+		"user-"+uuid.New().String(), s.passwordHash,
+	).Scan(&id)
+	s.Require().NoError(err)
+
+	token := s.login(id)
+
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/buy/%s", ts.URL, item), nil)
+	s.Require().NoError(err)
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+
+	resp, err := http.DefaultClient.Do(req)
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var orderID int
+	err = s.dbPool.QueryRow(context.Background(),
+		`SELECT id FROM orders 
+		WHERE user_id = $1 AND item_id = $2`,
+		id, itemID,
+	).Scan(&orderID)
+	s.Require().NoError(err)
+
+	req, err = http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/orders/%d/refund", ts.URL, orderID), nil)
+	s.Require().NoError(err)
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+
+	resp, err = http.DefaultClient.Do(req)
+	s.Require().NoError(err)
+	resp.Body.Close()
+
+	s.Equal(http.StatusOK, resp.StatusCode)
+
+	var balance int
+	err = s.dbPool.QueryRow(context.Background(),
+		`SELECT balance FROM users 
+		WHERE id = $1`,
+		id,
+	).Scan(&balance)
+	s.Require().NoError(err)
+	s.Equal(1000, balance)
+
+	var count int
+	err = s.dbPool.QueryRow(context.Background(),
+		`SELECT COUNT(*) FROM inventory_items 
+		WHERE user_id = $1 AND item_id = $2`,
+		id, itemID,
+	).Scan(&count)
+	s.Require().NoError(err)
+	s.Equal(0, count)
+
+	resp, err = http.DefaultClient.Do(req)
+	s.Require().NoError(err)
+	resp.Body.Close()
+
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+}
+
+func (s *MerchTestSuite) TestMerch_GiftItem_Success() {
+	app := server.NewServer(s.cfg, zap.NewNop(), s.dbPool, s.redisClient, s.keys)
+	ts := httptest.NewServer(app.RegisterHandlers())
+	defer ts.Close()
+
+	itemID := 1
+
+	var senderID int
+	err := s.dbPool.QueryRow(context.Background(),
+		`INSERT INTO users (username, password_hash) 
+		VALUES ($1, $2) 
+		RETURNING id`,
+		"user-"+uuid.New().String(), "sadfswergwrb",
+	).Scan(&senderID)
+	s.Require().NoError(err)
+
+	receiver := "user-" + uuid.New().String()
+	var receiverID int
+	err = s.dbPool.QueryRow(context.Background(),
+		`INSERT INTO users (username, password_hash) 
+		VALUES ($1, $2) 
+		RETURNING id`,
+		receiver, "gasgtefgdagdsag",
+	).Scan(&receiverID)
+	s.Require().NoError(err)
+
+	_, err = s.dbPool.Exec(context.Background(),
+		`INSERT INTO inventory_items (user_id, item_id, quantity) 
+		VALUES ($1, $2, $3)`,
+		senderID, itemID, 3,
+	)
+	s.Require().NoError(err)
+
+	token := s.login(senderID)
+
+	reqBody := fmt.Sprintf(`{"to_user": "%s", "item": "t-shirt", "quantity": 2}`, receiver)
+	req, err := http.NewRequest(http.MethodPost, ts.URL+"/api/giftItem", strings.NewReader(reqBody))
+	s.Require().NoError(err)
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+
+	s.Equal(http.StatusOK, resp.StatusCode)
+
+	var senderQuantity, receiverQuantity int
+	err = s.dbPool.QueryRow(context.Background(),
+		`SELECT quantity FROM inventory_items 
+		WHERE user_id = $1 AND item_id = $2`,
+		senderID, itemID,
+	).Scan(&senderQuantity)
+	s.Require().NoError(err)
+	s.Equal(1, senderQuantity)
+
+	err = s.dbPool.QueryRow(context.Background(),
+		`SELECT quantity FROM inventory_items 
+		WHERE user_id = $1 AND item_id = $2`,
+		receiverID, itemID,
+	).Scan(&receiverQuantity)
+	s.Require().NoError(err)
+	s.Equal(2, receiverQuantity)
+
+	var quantity int
+	err = s.dbPool.QueryRow(context.Background(),
+		`SELECT quantity FROM item_transfers 
+		WHERE from_id = $1 AND to_id = $2 AND item_id = $3`,
+		senderID, receiverID, itemID,
+	).Scan(&quantity)
+	s.Require().NoError(err)
+	s.Equal(2, quantity)
+}
+
+func (s *MerchTestSuite) TestMerch_GiftItem_NotEnoughItems() {
+	app := server.NewServer(s.cfg, zap.NewNop(), s.dbPool, s.redisClient, s.keys)
+	ts := httptest.NewServer(app.RegisterHandlers())
+	defer ts.Close()
+
+	var senderID int
+	err := s.dbPool.QueryRow(context.Background(),
+		`INSERT INTO users (username, password_hash) 
+		VALUES ($1, $2) 
+		RETURNING id`,
+		"user-"+uuid.New().String(), "sadfswergwrb",
+	).Scan(&senderID)
+	s.Require().NoError(err)
+
+	receiver := "user-" + uuid.New().String()
+	_, err = s.dbPool.Exec(context.Background(),
+		`INSERT INTO users (username, password_hash) 
+		VALUES ($1, $2)`,
+		receiver, "gasgtefgdagdsag",
+	)
+	s.Require().NoError(err)
+
+	token := s.login(senderID)
+
+	reqBody := fmt.Sprintf(`{"to_user": "%s", "item": "t-shirt", "quantity": 1}`, receiver)
+	req, err := http.NewRequest(http.MethodPost, ts.URL+"/api/giftItem", strings.NewReader(reqBody))
+	s.Require().NoError(err)
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+}
+
+func (s *MerchTestSuite) TestMerch_GetHistory_Pagination() {
+	app := server.NewServer(s.cfg, zap.NewNop(), s.dbPool, s.redisClient, s.keys)
+	ts := httptest.NewServer(app.RegisterHandlers())
+	defer ts.Close()
+
+	var userID int
+	err := s.dbPool.QueryRow(context.Background(),
+		`INSERT INTO users (username, password_hash) 
+		VALUES ($1, $2) 
+		RETURNING id`,
+		"user-"+uuid.New().String(), "password-hash",
+	).Scan(&userID)
+	s.Require().NoError(err)
+
+	other := "other-" + uuid.New().String()
+	var otherID int
+	err = s.dbPool.QueryRow(context.Background(),
+		`INSERT INTO users (username, password_hash) 
+		VALUES ($1, $2) 
+		RETURNING id`,
+		other, "password-hash",
+	).Scan(&otherID)
+	s.Require().NoError(err)
+
+	_, err = s.dbPool.Exec(context.Background(),
+		`INSERT INTO transactions (from_id, to_id, amount) 
+		VALUES ($1, $2, 10), ($2, $1, 20), ($1, $2, 30)`,
+		userID, otherID,
+	)
+	s.Require().NoError(err)
+
+	token := s.login(userID)
+
+	getPage := func(query string) models.HistoryPage {
+		req, err := http.NewRequest(http.MethodGet, ts.URL+"/api/history?"+query, nil)
+		s.Require().NoError(err)
+
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+
+		resp, err := http.DefaultClient.Do(req)
+		s.Require().NoError(err)
+		defer resp.Body.Close()
+
+		s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+		var page models.HistoryPage
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		s.Require().NoError(err)
+
+		return page
+	}
+
+	page := getPage("limit=2")
+	s.Require().Len(page.Transactions, 2)
+	s.Equal(int64(30), page.Transactions[0].Amount)
+	s.Equal("sent", page.Transactions[0].Direction)
+	s.Equal(other, page.Transactions[0].Counterparty)
+	s.Equal(int64(20), page.Transactions[1].Amount)
+	s.Equal("received", page.Transactions[1].Direction)
+	s.Require().NotNil(page.NextCursor)
+
+	page = getPage(fmt.Sprintf("limit=2&cursor=%d", *page.NextCursor))
+	s.Require().Len(page.Transactions, 1)
+	s.Equal(int64(10), page.Transactions[0].Amount)
+	s.Nil(page.NextCursor)
+
+	page = getPage("direction=received")
+	s.Require().Len(page.Transactions, 1)
+	s.Equal(int64(20), page.Transactions[0].Amount)
+}
+
+func (s *MerchTestSuite) TestMerch_SendCoins_IdempotencyKey() {
+	app := server.NewServer(s.cfg, zap.NewNop(), s.dbPool, s.redisClient, s.keys)
+	ts := httptest.NewServer(app.RegisterHandlers())
+	defer ts.Close()
+
+	var senderID int
+	err := s.dbPool.QueryRow(context.Background(),
+		`INSERT INTO users (username, password_hash) 
+		VALUES ($1, $2) 
+		RETURNING id`,
+		"user-"+uuid.New().String(), "sadfswergwrb",
+	).Scan(&senderID)
+	s.Require().NoError(err)
+
+	receiver := "user-" + uuid.New().String()
+	var receiverID int
+	err = s.dbPool.QueryRow(context.Background(),
+		`INSERT INTO users (username, password_hash) 
+		VALUES ($1, $2) 
+		RETURNING id`,
+		receiver, "gasgtefgdagdsag",
+	).Scan(&receiverID)
+	s.Require().NoError(err)
+
+	token := s.login(senderID)
+
+	idempotencyKey := uuid.New().String()
+	sendCoins := func(amount int) int {
+		reqBody := fmt.Sprintf(`{"to_user": "%s", "amount": %d}`, receiver, amount)
+		req, err := http.NewRequest(http.MethodPost, ts.URL+"/api/sendCoin", strings.NewReader(reqBody))
+		s.Require().NoError(err)
+
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Idempotency-Key", idempotencyKey)
+
+		resp, err := http.DefaultClient.Do(req)
+		s.Require().NoError(err)
+		defer resp.Body.Close()
+
+		return resp.StatusCode
+	}
+
+	s.Equal(http.StatusOK, sendCoins(100))
+	s.Equal(http.StatusOK, sendCoins(100))
+
+	err = s.redisClient.Del(context.Background(), redis.GetIdempotencyCacheKey(int64(senderID), idempotencyKey)).Err()
+	s.Require().NoError(err)
+	s.Equal(http.StatusOK, sendCoins(100))
+
+	s.Equal(http.StatusConflict, sendCoins(200))
+
+	var senderBalance, count int
+	err = s.dbPool.QueryRow(context.Background(),
+		`SELECT balance FROM users 
+		WHERE id = $1`,
+		senderID,
+	).Scan(&senderBalance)
+	s.Require().NoError(err)
+	s.Equal(900, senderBalance)
+
+	err = s.dbPool.QueryRow(context.Background(),
+		`SELECT COUNT(*) FROM transactions 
+		WHERE from_id = $1 AND to_id = $2`,
+		senderID, receiverID,
+	).Scan(&count)
+	s.Require().NoError(err)
+	s.Equal(1, count)
+}
+
+func (s *MerchTestSuite) TestMerch_SendCoins_IdempotencyKey_FailureReplayed() {
+	app := server.NewServer(s.cfg, zap.NewNop(), s.dbPool, s.redisClient, s.keys)
+	ts := httptest.NewServer(app.RegisterHandlers())
+	defer ts.Close()
+
+	var senderID int
+	err := s.dbPool.QueryRow(context.Background(),
+		`INSERT INTO users (username, password_hash, balance) 
+		VALUES ($1, $2, $3) 
+		RETURNING id`,
+		"user-"+uuid.New().String(), "sadfswergwrb", 100,
+	).Scan(&senderID)
+	s.Require().NoError(err)
+
+	receiver := "user-" + uuid.New().String()
+	_, err = s.dbPool.Exec(context.Background(),
+		`INSERT INTO users (username, password_hash) 
+		VALUES ($1, $2)`,
+		receiver, "gasgtefgdagdsag",
+	)
+	s.Require().NoError(err)
+
+	token := s.login(senderID)
+
+	idempotencyKey := uuid.New().String()
+	sendCoins := func() int {
+		reqBody := fmt.Sprintf(`{"to_user": "%s", "amount": %d}`, receiver, 500)
+		req, err := http.NewRequest(http.MethodPost, ts.URL+"/api/sendCoin", strings.NewReader(reqBody))
+		s.Require().NoError(err)
+
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Idempotency-Key", idempotencyKey)
+
+		resp, err := http.DefaultClient.Do(req)
+		s.Require().NoError(err)
+		defer resp.Body.Close()
+
+		return resp.StatusCode
+	}
+
+	s.Equal(http.StatusBadRequest, sendCoins())
+
+	_, err = s.dbPool.Exec(context.Background(),
+		`UPDATE users SET balance = 1000 WHERE id = $1`,
+		senderID,
+	)
+	s.Require().NoError(err)
+
+	s.Equal(http.StatusBadRequest, sendCoins())
+
+	err = s.redisClient.Del(context.Background(), redis.GetIdempotencyCacheKey(int64(senderID), idempotencyKey)).Err()
+	s.Require().NoError(err)
+	s.Equal(http.StatusBadRequest, sendCoins())
+
+	var senderBalance int
+	err = s.dbPool.QueryRow(context.Background(),
+		`SELECT balance FROM users 
+		WHERE id = $1`,
+		senderID,
+	).Scan(&senderBalance)
+	s.Require().NoError(err)
+	s.Equal(1000, senderBalance)
+}
+
+func (s *MerchTestSuite) TestMerch_SendCoins_CommentTooLong() {
+	app := server.NewServer(s.cfg, zap.NewNop(), s.dbPool, s.redisClient, s.keys)
+	ts := httptest.NewServer(app.RegisterHandlers())
+	defer ts.Close()
+
+	var senderID int
+	err := s.dbPool.QueryRow(context.Background(),
+		`INSERT INTO users (username, password_hash) 
+		VALUES ($1, $2) 
+		RETURNING id`,
+		"user-"+uuid.New().String(), "sadfswergwrb",
+	).Scan(&senderID)
+	s.Require().NoError(err)
+
+	receiver := "user-" + uuid.New().String()
+	_, err = s.dbPool.Exec(context.Background(),
+		`INSERT INTO users (username, password_hash) 
+		VALUES ($1, $2)`,
+		receiver, "gasgtefgdagdsag",
+	)
+	s.Require().NoError(err)
+
+	token := s.login(senderID)
+
+	reqBody := fmt.Sprintf(`{"to_user": "%s", "amount": 10, "comment": "%s"}`, receiver, strings.Repeat("a", 256))
+	req, err := http.NewRequest(http.MethodPost, ts.URL+"/api/sendCoin", strings.NewReader(reqBody))
+	s.Require().NoError(err)
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+
+	var response map[string]interface{}
+	err = json.NewDecoder(resp.Body).Decode(&response)
+	s.Require().NoError(err)
+	s.Equal("field 'Comment' failed on the 'max' rule", response["errors"])
+}
+
+func (s *MerchTestSuite) TestMerch_SendCoinBatch_Success() {
+	app := server.NewServer(s.cfg, zap.NewNop(), s.dbPool, s.redisClient, s.keys)
+	ts := httptest.NewServer(app.RegisterHandlers())
+	defer ts.Close()
+
+	var senderID int
+	err := s.dbPool.QueryRow(context.Background(),
+		`INSERT INTO users (username, password_hash) 
+		VALUES ($1, $2) 
+		RETURNING id`,
+		"user-"+uuid.New().String(), "sadfswergwrb",
+	).Scan(&senderID)
+	s.Require().NoError(err)
+
+	receivers := []string{"user-" + uuid.New().String(), "user-" + uuid.New().String()}
+	for _, receiver := range receivers {
+		_, err = s.dbPool.Exec(context.Background(),
+			`INSERT INTO users (username, password_hash) 
+			VALUES ($1, $2)`,
+			receiver, "gasgtefgdagdsag",
+		)
+		s.Require().NoError(err)
+	}
+
+	token := s.login(senderID)
+
+	reqBody := fmt.Sprintf(`{"transfers": [{"to_user": "%s", "amount": 100}, {"to_user": "%s", "amount": 200}]}`, receivers[0], receivers[1])
+	req, err := http.NewRequest(http.MethodPost, ts.URL+"/api/sendCoinBatch", strings.NewReader(reqBody))
+	s.Require().NoError(err)
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+
+	s.Equal(http.StatusOK, resp.StatusCode)
+
+	var balance int
+	err = s.dbPool.QueryRow(context.Background(),
+		`SELECT balance FROM users 
+		WHERE id = $1`,
+		senderID,
+	).Scan(&balance)
+	s.Require().NoError(err)
+	s.Equal(700, balance)
+
+	for i, receiver := range receivers {
+		err = s.dbPool.QueryRow(context.Background(),
+			`SELECT balance FROM users 
+			WHERE username = $1`,
+			receiver,
+		).Scan(&balance)
+		s.Require().NoError(err)
+		s.Equal(1000+(i+1)*100, balance)
+	}
+
+	var count int
+	err = s.dbPool.QueryRow(context.Background(),
+		`SELECT COUNT(*) FROM transactions 
+		WHERE from_id = $1`,
+		senderID,
+	).Scan(&count)
+	s.Require().NoError(err)
+	s.Equal(2, count)
+}
+
+func (s *MerchTestSuite) TestMerch_SendCoinBatch_InsufficientFunds() {
+	app := server.NewServer(s.cfg, zap.NewNop(), s.dbPool, s.redisClient, s.keys)
+	ts := httptest.NewServer(app.RegisterHandlers())
+	defer ts.Close()
+
+	var senderID int
+	err := s.dbPool.QueryRow(context.Background(),
+		`INSERT INTO users (username, password_hash, balance) 
+		VALUES ($1, $2, $3) 
+		RETURNING id`,
+		"user-"+uuid.New().String(), "sadfswergwrb", 500,
+	).Scan(&senderID)
+	s.Require().NoError(err)
+
+	receivers := []string{"user-" + uuid.New().String(), "user-" + uuid.New().String()}
+	for _, receiver := range receivers {
+		_, err = s.dbPool.Exec(context.Background(),
+			`INSERT INTO users (username, password_hash) 
+			VALUES ($1, $2)`,
+			receiver, "gasgtefgdagdsag",
+		)
+		s.Require().NoError(err)
+	}
+
+	token := s.login(senderID)
+
+	reqBody := fmt.Sprintf(`{"transfers": [{"to_user": "%s", "amount": 300}, {"to_user": "%s", "amount": 300}]}`, receivers[0], receivers[1])
+	req, err := http.NewRequest(http.MethodPost, ts.URL+"/api/sendCoinBatch", strings.NewReader(reqBody))
+	s.Require().NoError(err)
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+
+	var count int
+	err = s.dbPool.QueryRow(context.Background(),
+		`SELECT COUNT(*) FROM transactions 
+		WHERE from_id = $1`,
+		senderID,
+	).Scan(&count)
+	s.Require().NoError(err)
+	s.Equal(0, count)
+}
+
+func (s *MerchTestSuite) TestMerch_SendCoins_DailyLimit() {
+	cfg := *s.cfg
+	cfg.App.MaxTransferAmount = 300
+	cfg.App.DailySendLimit = 500
+
+	app := server.NewServer(&cfg, zap.NewNop(), s.dbPool, s.redisClient, s.keys)
+	ts := httptest.NewServer(app.RegisterHandlers())
+	defer ts.Close()
+
+	var senderID int
+	err := s.dbPool.QueryRow(context.Background(),
+		`INSERT INTO users (username, password_hash) 
+		VALUES ($1, $2) 
+		RETURNING id`,
+		"user-"+uuid.New().String(), "sadfswergwrb",
+	).Scan(&senderID)
+	s.Require().NoError(err)
+
+	receiver := "user-" + uuid.New().String()
+	_, err = s.dbPool.Exec(context.Background(),
+		`INSERT INTO users (username, password_hash) 
+		VALUES ($1, $2)`,
+		receiver, "gasgtefgdagdsag",
+	)
+	s.Require().NoError(err)
+
+	token := s.login(senderID)
+
+	sendCoins := func(amount int) int {
+		reqBody := fmt.Sprintf(`{"to_user": "%s", "amount": %d}`, receiver, amount)
+		req, err := http.NewRequest(http.MethodPost, ts.URL+"/api/sendCoin", strings.NewReader(reqBody))
+		s.Require().NoError(err)
+
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := http.DefaultClient.Do(req)
+		s.Require().NoError(err)
+		defer resp.Body.Close()
+
+		return resp.StatusCode
+	}
+
+	s.Equal(http.StatusBadRequest, sendCoins(400))
+	s.Equal(http.StatusOK, sendCoins(300))
+	s.Equal(http.StatusBadRequest, sendCoins(300))
+	s.Equal(http.StatusOK, sendCoins(200))
+
+	req, err := http.NewRequest(http.MethodGet, ts.URL+"/api/info", nil)
+	s.Require().NoError(err)
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+
+	resp, err := http.DefaultClient.Do(req)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+
+	var infoResponse models.InfoResponse
+	err = json.NewDecoder(resp.Body).Decode(&infoResponse)
+	s.Require().NoError(err)
+
+	s.Equal(int64(500), infoResponse.Coins)
+	s.Require().NotNil(infoResponse.Allowance)
+	s.Require().NotNil(infoResponse.Allowance.Send)
+	s.Equal(int64(0), *infoResponse.Allowance.Send)
 }
@@ -8,10 +8,12 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/alexedwards/argon2id"
 	"github.com/go-redis/redis/v8"
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/golang-migrate/migrate/v4/database/postgres"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
 	_ "github.com/jackc/pgx/v5/stdlib"
 	"github.com/joho/godotenv"
@@ -20,8 +22,16 @@ import (
 	"github.com/stretchr/testify/suite"
 
 	"cyansnbrst/merch-service/config"
+	"cyansnbrst/merch-service/internal/auth"
+	"cyansnbrst/merch-service/internal/auth/repository"
+	"cyansnbrst/merch-service/internal/auth/usecase"
+	"cyansnbrst/merch-service/internal/models"
+	"cyansnbrst/merch-service/pkg/auth/jwt"
 )
 
+// Password of the users created by tests
+const testPassword = "password"
+
 type BaseTestSuite struct {
 	suite.Suite
 	pool           *dockertest.Pool
@@ -30,6 +40,9 @@ type BaseTestSuite struct {
 	dbPool         *pgxpool.Pool
 	redisClient    *redis.Client
 	cfg            *config.Config
+	keys           *jwt.Keys
+	authUC         auth.UseCase
+	passwordHash   string
 }
 
 func (s *BaseTestSuite) SetupSuite() {
@@ -106,6 +119,47 @@ func (s *BaseTestSuite) SetupSuite() {
 	configPath := filepath.ToSlash(filepath.Join("..", "config", "config-local.yml"))
 	s.cfg, err = config.LoadConfig(configPath)
 	s.Require().NoError(err)
+
+	s.keys, err = jwt.LoadKeys(s.cfg)
+	s.Require().NoError(err)
+
+	authRepo := repository.NewAuthRepo(s.dbPool)
+	s.authUC = usecase.NewAuthUseCase(s.cfg, authRepo, repository.NewAuthRedisRepo(s.cfg, s.redisClient), s.keys)
+
+	s.passwordHash, err = argon2id.CreateHash(testPassword, argon2id.DefaultParams)
+	s.Require().NoError(err)
+}
+
+// Create user with the role, returning its id, username and access token
+func (s *BaseTestSuite) createUser(role string) (int, string, string) {
+	username := "user-" + uuid.New().String()
+
+	var id int
+	err := s.dbPool.QueryRow(context.Background(),
+		`INSERT INTO users (username, password_hash, role) 
+		VALUES ($1, $2, $3) 
+		RETURNING id`,
+		username, s.passwordHash, role,
+	).Scan(&id)
+	s.Require().NoError(err)
+
+	return id, username, s.login(id)
+}
+
+// Log in as the user created with the test password, returning access token
+func (s *BaseTestSuite) login(id int) string {
+	var username string
+	err := s.dbPool.QueryRow(context.Background(),
+		`SELECT username FROM users 
+		WHERE id = $1`,
+		id,
+	).Scan(&username)
+	s.Require().NoError(err)
+
+	tokens, err := s.authUC.Login(context.Background(), username, testPassword, models.ClientInfo{})
+	s.Require().NoError(err)
+
+	return tokens.Token
 }
 
 func (s *BaseTestSuite) runMigrations(dbDSN string) {
@@ -0,0 +1,337 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alexedwards/argon2id"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/google/uuid"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+
+	"cyansnbrst/merch-service/internal/models"
+	"cyansnbrst/merch-service/internal/server"
+)
+
+type UsersTestSuite struct {
+	BaseTestSuite
+}
+
+func TestUsersSuite(t *testing.T) {
+	suite.Run(t, new(UsersTestSuite))
+}
+
+func (s *UsersTestSuite) SetupSuite() {
+	s.BaseTestSuite.SetupSuite()
+}
+
+func (s *UsersTestSuite) TearDownSuite() {
+	s.BaseTestSuite.TearDownSuite()
+}
+
+func (s *UsersTestSuite) setRole(ts *httptest.Server, token string, id int, role string) int {
+	reqBody := fmt.Sprintf(`{"role": "%s"}`, role)
+	req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%s/api/admin/users/%d/role", ts.URL, id), strings.NewReader(reqBody))
+	s.Require().NoError(err)
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+
+	return resp.StatusCode
+}
+
+func (s *UsersTestSuite) TestUsers_SetRole() {
+	app := server.NewServer(s.cfg, zap.NewNop(), s.dbPool, s.redisClient, s.keys)
+	ts := httptest.NewServer(app.RegisterHandlers())
+	defer ts.Close()
+
+	adminID, _, adminToken := s.createUser(models.RoleAdmin)
+	userID, _, userToken := s.createUser(models.RoleUser)
+
+	s.Equal(http.StatusForbidden, s.setRole(ts, userToken, userID, models.RoleAdmin))
+	s.Equal(http.StatusBadRequest, s.setRole(ts, adminToken, adminID, models.RoleUser))
+	s.Equal(http.StatusBadRequest, s.setRole(ts, adminToken, userID, "superuser"))
+	s.Equal(http.StatusNotFound, s.setRole(ts, adminToken, 999999999, models.RoleAdmin))
+	s.Equal(http.StatusOK, s.setRole(ts, adminToken, userID, models.RoleAdmin))
+
+	var role string
+	err := s.dbPool.QueryRow(context.Background(),
+		`SELECT role FROM users WHERE id = $1`, userID,
+	).Scan(&role)
+	s.Require().NoError(err)
+	s.Equal(models.RoleAdmin, role)
+
+	req, err := http.NewRequest(http.MethodGet, ts.URL+"/api/info", nil)
+	s.Require().NoError(err)
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", userToken))
+
+	resp, err := http.DefaultClient.Do(req)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	s.Equal(http.StatusUnauthorized, resp.StatusCode)
+}
+
+func (s *UsersTestSuite) TestUsers_RevokeAllSessions() {
+	app := server.NewServer(s.cfg, zap.NewNop(), s.dbPool, s.redisClient, s.keys)
+	ts := httptest.NewServer(app.RegisterHandlers())
+	defer ts.Close()
+
+	_, _, adminToken := s.createUser(models.RoleAdmin)
+	userID, _, userToken := s.createUser(models.RoleUser)
+
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/admin/users/%d/revoke-sessions", ts.URL, userID), nil)
+	s.Require().NoError(err)
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", adminToken))
+
+	resp, err := http.DefaultClient.Do(req)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	s.Equal(http.StatusOK, resp.StatusCode)
+
+	req, err = http.NewRequest(http.MethodGet, ts.URL+"/api/info", nil)
+	s.Require().NoError(err)
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", userToken))
+
+	resp, err = http.DefaultClient.Do(req)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	s.Equal(http.StatusUnauthorized, resp.StatusCode)
+}
+
+func (s *UsersTestSuite) TestUsers_PasswordReset() {
+	app := server.NewServer(s.cfg, zap.NewNop(), s.dbPool, s.redisClient, s.keys)
+	ts := httptest.NewServer(app.RegisterHandlers())
+	defer ts.Close()
+
+	_, _, adminToken := s.createUser(models.RoleAdmin)
+	userID, _, _ := s.createUser(models.RoleUser)
+
+	var username string
+	err := s.dbPool.QueryRow(context.Background(),
+		`SELECT username FROM users WHERE id = $1`, userID,
+	).Scan(&username)
+	s.Require().NoError(err)
+
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/admin/users/%d/password-reset", ts.URL, userID), nil)
+	s.Require().NoError(err)
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", adminToken))
+
+	resp, err := http.DefaultClient.Do(req)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	s.Require().Equal(http.StatusCreated, resp.StatusCode)
+
+	var reset models.PasswordResetResponse
+	err = json.NewDecoder(resp.Body).Decode(&reset)
+	s.Require().NoError(err)
+	s.NotEmpty(reset.ResetToken)
+
+	redeem := func() int {
+		reqBody := fmt.Sprintf(`{"reset_token": "%s", "new_password": "new-password"}`, reset.ResetToken)
+		resp, err := http.Post(ts.URL+"/api/password/reset", "application/json", strings.NewReader(reqBody))
+		s.Require().NoError(err)
+		defer resp.Body.Close()
+
+		return resp.StatusCode
+	}
+
+	s.Equal(http.StatusOK, redeem())
+	s.Equal(http.StatusUnauthorized, redeem())
+
+	reqBody := fmt.Sprintf(`{"username": "%s", "password": "new-password"}`, username)
+	resp, err = http.Post(ts.URL+"/api/login", "application/json", strings.NewReader(reqBody))
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	s.Equal(http.StatusOK, resp.StatusCode)
+}
+
+func (s *UsersTestSuite) adminAction(ts *httptest.Server, token, method string, id int, action string) int {
+	url := fmt.Sprintf("%s/api/admin/users/%d", ts.URL, id)
+	if action != "" {
+		url += "/" + action
+	}
+
+	req, err := http.NewRequest(method, url, nil)
+	s.Require().NoError(err)
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+
+	resp, err := http.DefaultClient.Do(req)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+
+	return resp.StatusCode
+}
+
+func (s *UsersTestSuite) TestUsers_DeactivateAndDelete() {
+	app := server.NewServer(s.cfg, zap.NewNop(), s.dbPool, s.redisClient, s.keys)
+	ts := httptest.NewServer(app.RegisterHandlers())
+	defer ts.Close()
+
+	adminID, _, adminToken := s.createUser(models.RoleAdmin)
+	senderID, _, senderToken := s.createUser(models.RoleUser)
+
+	username := "user-" + uuid.New().String()
+	hashedPassword, err := argon2id.CreateHash("password", argon2id.DefaultParams)
+	s.Require().NoError(err)
+
+	var userID int
+	err = s.dbPool.QueryRow(context.Background(),
+		`INSERT INTO users (username, password_hash) 
+		VALUES ($1, $2) 
+		RETURNING id`,
+		username, hashedPassword,
+	).Scan(&userID)
+	s.Require().NoError(err)
+
+	login := func() int {
+		reqBody := fmt.Sprintf(`{"username": "%s", "password": "password"}`, username)
+		resp, err := http.Post(ts.URL+"/api/login", "application/json", strings.NewReader(reqBody))
+		s.Require().NoError(err)
+		defer resp.Body.Close()
+
+		return resp.StatusCode
+	}
+
+	sendCoins := func() int {
+		reqBody := fmt.Sprintf(`{"to_user": "%s", "amount": 10}`, username)
+		req, err := http.NewRequest(http.MethodPost, ts.URL+"/api/sendCoin", strings.NewReader(reqBody))
+		s.Require().NoError(err)
+
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", senderToken))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := http.DefaultClient.Do(req)
+		s.Require().NoError(err)
+		defer resp.Body.Close()
+
+		return resp.StatusCode
+	}
+
+	s.Equal(http.StatusBadRequest, s.adminAction(ts, adminToken, http.MethodPost, adminID, "deactivate"))
+	s.Equal(http.StatusNotFound, s.adminAction(ts, adminToken, http.MethodPost, 999999999, "deactivate"))
+
+	s.Require().Equal(http.StatusOK, s.adminAction(ts, adminToken, http.MethodPost, userID, "deactivate"))
+	s.Equal(http.StatusForbidden, login())
+	s.Equal(http.StatusBadRequest, sendCoins())
+
+	s.Require().Equal(http.StatusOK, s.adminAction(ts, adminToken, http.MethodPost, userID, "reactivate"))
+	s.Equal(http.StatusOK, login())
+	s.Require().Equal(http.StatusOK, sendCoins())
+
+	_, err = s.dbPool.Exec(context.Background(),
+		`INSERT INTO invoices (from_id, to_id, amount) 
+		VALUES ($1, $2, $3)`,
+		senderID, userID, 50,
+	)
+	s.Require().NoError(err)
+
+	var orderID int
+	err = s.dbPool.QueryRow(context.Background(),
+		`INSERT INTO orders (user_id, item_id, price, quantity) 
+		SELECT $1, id, price, 1 FROM items WHERE name = $2 
+		RETURNING id`,
+		userID, "cup",
+	).Scan(&orderID)
+	s.Require().NoError(err)
+
+	getInfo := func() models.InfoResponse {
+		req, err := http.NewRequest(http.MethodGet, ts.URL+"/api/info", nil)
+		s.Require().NoError(err)
+
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", senderToken))
+
+		resp, err := http.DefaultClient.Do(req)
+		s.Require().NoError(err)
+		defer resp.Body.Close()
+		s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+		var info models.InfoResponse
+		err = json.NewDecoder(resp.Body).Decode(&info)
+		s.Require().NoError(err)
+		return info
+	}
+
+	info := getInfo()
+	s.Require().Len(info.CoinHistory.Sent, 1)
+	s.Equal(username, info.CoinHistory.Sent[0].ToUser)
+
+	s.Require().Equal(http.StatusOK, s.adminAction(ts, adminToken, http.MethodDelete, userID, ""))
+	s.Equal(http.StatusNotFound, s.adminAction(ts, adminToken, http.MethodDelete, userID, ""))
+
+	var ownerDeleted bool
+	err = s.dbPool.QueryRow(context.Background(),
+		`SELECT user_id IS NULL FROM orders WHERE id = $1`, orderID,
+	).Scan(&ownerDeleted)
+	s.Require().NoError(err)
+	s.True(ownerDeleted)
+
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/admin/orders/%d/refund", ts.URL, orderID), nil)
+	s.Require().NoError(err)
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", adminToken))
+
+	resp, err := http.DefaultClient.Do(req)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+
+	info = getInfo()
+	s.Require().Len(info.CoinHistory.Sent, 1)
+	s.Equal("deleted user", info.CoinHistory.Sent[0].ToUser)
+
+	var count int
+	err = s.dbPool.QueryRow(context.Background(),
+		`SELECT COUNT(*) FROM transactions WHERE from_id = $1 AND to_id IS NULL`, senderID,
+	).Scan(&count)
+	s.Require().NoError(err)
+	s.Equal(1, count)
+
+	req, err = http.NewRequest(http.MethodGet, ts.URL+"/api/history?direction=sent", nil)
+	s.Require().NoError(err)
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", senderToken))
+
+	resp, err = http.DefaultClient.Do(req)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var page models.HistoryPage
+	err = json.NewDecoder(resp.Body).Decode(&page)
+	s.Require().NoError(err)
+	s.Require().Len(page.Transactions, 1)
+	s.Equal("deleted user", page.Transactions[0].Counterparty)
+
+	req, err = http.NewRequest(http.MethodGet, ts.URL+"/api/invoices?direction=incoming", nil)
+	s.Require().NoError(err)
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", senderToken))
+
+	resp, err = http.DefaultClient.Do(req)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var invoices []models.Invoice
+	err = json.NewDecoder(resp.Body).Decode(&invoices)
+	s.Require().NoError(err)
+	s.Require().Len(invoices, 1)
+	s.Equal("deleted user", invoices[0].ToUser)
+	s.Equal(models.InvoiceStatusDeclined, invoices[0].Status)
+}